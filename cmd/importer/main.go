@@ -49,7 +49,7 @@ func main() {
 	}
 	defer db.Close()
 
-	log.Println("database:", db.Filename())
+	log.Println("database:", db.Filename(sqlite3.DefaultSchema))
 
 	// Create a configuration
 	config := SQImportConfig{
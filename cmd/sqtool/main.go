@@ -30,8 +30,33 @@ var (
 	flagDelimiter = flag.String("delimiter", "", "Field delimiter")
 	flagComment   = flag.String("comment", "#", "Comment character")
 	flagTrimSpace = flag.Bool("trimspace", true, "Trim leading space of a field")
+	flagLazy      = flag.Bool("lazyquotes", false, "Allow non-standard quotes within fields")
+	flagFields    = flag.Bool("fieldspermissive", false, "Allow rows with a variable number of fields")
+	flagNull      = flag.String("null", "", "Sentinel value for NULL fields, such as \\N or NULL")
+	flagNullEmpty = flag.Bool("nullempty", false, "Import empty fields as NULL")
+	flagTable     = flag.String("table", "", "Table name to import into (required when reading from standard input)")
+	flagDecimal   = flag.String("decimal-sep", "", "Decimal separator, parses numeric fields when set")
+	flagThousands = flag.String("thousands-sep", "", "Thousands separator, parses numeric fields when set")
+	flagJSON      = flag.Bool("json", false, "Write a summary of each import to standard output as JSON")
+	flagDate      stringList
 )
 
+func init() {
+	flag.Var(&flagDate, "date-format", "Date layout for parsing date fields, such as 02/01/2006 (can be repeated)")
+}
+
+// stringList is a flag value which can be repeated
+type stringList []string
+
+func (s *stringList) String() string {
+	return fmt.Sprint([]string(*s))
+}
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 func main() {
@@ -43,7 +68,7 @@ func main() {
 
 	// Check number of arguments
 	if flag.NArg() < 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %v <sqlite-database> <url>...\n", name)
+		fmt.Fprintf(os.Stderr, "Usage: %v <sqlite-database> <url>|-...\n", name)
 		os.Exit(1)
 	}
 
@@ -61,10 +86,43 @@ func main() {
 	log.Println("database:", db.Filename(sqlite3.DefaultSchema))
 
 	// Create a configuration
+	config := importConfig(log)
+
+	// Create an SQL Writer
+	writer, err := importer.NewSQLWriter(config, db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Read files
+	summaries := make([]*summary, 0, flag.NArg()-1)
+	for _, url := range flag.Args()[1:] {
+		summaries = append(summaries, importFile(log, config, writer, url))
+	}
+
+	// Write the summary
+	if *flagJSON {
+		if err := writeSummary(os.Stdout, summaries); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// importConfig returns the import configuration from the command-line flags,
+// with warnings written to the logger
+func importConfig(log *log.Logger) SQImportConfig {
 	config := SQImportConfig{
-		Header:    *flagHeader,
-		TrimSpace: *flagTrimSpace,
-		Overwrite: *flagOverwrite,
+		Header:           *flagHeader,
+		TrimSpace:        *flagTrimSpace,
+		Overwrite:        *flagOverwrite,
+		LazyQuotes:       *flagLazy,
+		FieldsPermissive: *flagFields,
+		Name:             *flagTable,
+		Null:             *flagNull,
+		NullEmpty:        *flagNullEmpty,
+		Log:              log,
 	}
 	if *flagDelimiter != "" {
 		config.Delimiter = rune((*flagDelimiter)[0])
@@ -72,56 +130,70 @@ func main() {
 	if *flagComment != "" {
 		config.Comment = rune((*flagComment)[0])
 	}
+	if *flagDecimal != "" {
+		config.DecimalSep = rune((*flagDecimal)[0])
+	}
+	if *flagThousands != "" {
+		config.ThousandsSep = rune((*flagThousands)[0])
+	}
+	config.DateFormats = flagDate
+	return config
+}
 
-	// Create an SQL Writer
-	writer, err := importer.NewSQLWriter(config, db)
+// importFile reads rows from a file or URL and writes them to the database,
+// reporting progress to the logger and errors to stderr. Returns a summary
+// of the import
+func importFile(log *log.Logger, config SQImportConfig, writer *importer.SQLWriter, url string) *summary {
+	result := &summary{URL: url, Columns: []summaryColumn{}}
+
+	// Create an importer
+	importer, err := importer.NewImporter(config, url, &summaryWriter{writer, result})
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, url, ": ", err)
+		result.AddError(err)
+		return result
+	} else {
+		result.Table = importer.Name()
 	}
 
-	// Read files
-	for _, url := range flag.Args()[1:] {
-		// Create an importer
-		importer, err := importer.NewImporter(config, url, writer)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, importer.URL(), ": ", err)
-			continue
-		}
+	// Create the decoder, guess mimetype instead of supplying it
+	decoder, err := importer.Decoder("")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, importer.URL(), ": ", err)
+		result.AddError(err)
+		return result
+	}
 
-		// Create the decoder, guess mimetype instead of supplying it
-		decoder, err := importer.Decoder("")
-		if err != nil {
+	// Reset the counter
+	log.Println(" import:", importer.URL())
+	log.Println("     ...decoder", decoder)
+
+	// Read and write rows
+	start, mark := time.Now(), time.Now()
+	for {
+		if err := importer.ReadWrite(decoder); err == io.EOF {
+			break
+		} else if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
 			fmt.Fprintln(os.Stderr, importer.URL(), ": ", err)
-			continue
+			result.AddError(err)
+			break
 		}
-
-		// Reset the counter
-		log.Println(" import:", importer.URL())
-		log.Println("     ...decoder", decoder)
-
-		// Read and write rows
-		start, mark := time.Now(), time.Now()
-		for {
-			if err := importer.ReadWrite(decoder); err == io.EOF {
-				break
-			} else if errors.Is(err, io.EOF) {
-				break
-			} else if err != nil {
-				fmt.Fprintln(os.Stderr, importer.URL(), ": ", err)
-				break
-			}
-			if time.Since(mark) > 5*time.Second {
-				log.Printf("     ...written %d rows", writer.Count())
-				mark = time.Now()
-			}
+		if time.Since(mark) > 5*time.Second {
+			log.Printf("     ...written %d rows", writer.Count())
+			mark = time.Now()
 		}
-
-		// Report
-		since := time.Since(start)
-		ops_per_sec := math.Round(float64(writer.Count()) * 1000 / float64(since.Milliseconds()))
-		log.Printf("     ...written %d rows in %v (%.0f ops/s)", writer.Count(), since.Truncate(time.Millisecond), ops_per_sec)
 	}
+	result.Inserted = writer.Count()
+
+	// Report
+	since := time.Since(start)
+	ops_per_sec := math.Round(float64(writer.Count()) * 1000 / float64(since.Milliseconds()))
+	log.Printf("     ...written %d rows in %v (%.0f ops/s)", writer.Count(), since.Truncate(time.Millisecond), ops_per_sec)
+
+	// Return the summary
+	return result
 }
 
 func logger(name string) *log.Logger {
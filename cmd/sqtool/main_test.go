@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	// Modules
+	importer "github.com/mutablelogic/go-sqlite/pkg/importer"
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite"
+)
+
+func Test_Summary_001(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.csv")
+	if err := os.WriteFile(path, []byte("a,b,c\n1,2.5,x\n2,3,\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sqlite3.OpenPathEx(filepath.Join(dir, "test.sqlite"), sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	config := SQImportConfig{Header: true, TrimSpace: true, NullEmpty: true, DecimalSep: '.'}
+	writer, err := importer.NewSQLWriter(config, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Import a file, and a file which does not exist
+	log := log.New(io.Discard, "", 0)
+	summaries := []*summary{
+		importFile(log, config, writer, path),
+		importFile(log, config, writer, filepath.Join(dir, "missing.csv")),
+	}
+
+	// Write the summary and check the JSON
+	buf := new(bytes.Buffer)
+	if err := writeSummary(buf, summaries); err != nil {
+		t.Fatal(err)
+	}
+	var result []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	} else if len(result) != 2 {
+		t.Fatal("Expected two summaries, got", len(result))
+	}
+	expected := map[string]interface{}{
+		"url":           path,
+		"table":         "test",
+		"rows_read":     float64(2),
+		"rows_inserted": float64(2),
+		"columns": []interface{}{
+			map[string]interface{}{"name": "a", "type": "INTEGER"},
+			map[string]interface{}{"name": "b", "type": "REAL"},
+			map[string]interface{}{"name": "c", "type": "TEXT"},
+		},
+	}
+	if !reflect.DeepEqual(result[0], expected) {
+		t.Errorf("Expected %v, got %v", expected, result[0])
+	}
+	if errs, ok := result[1]["errors"].([]interface{}); !ok || len(errs) == 0 {
+		t.Error("Expected errors, got", result[1])
+	} else if n := result[1]["rows_read"]; n != float64(0) {
+		t.Error("Unexpected rows read", n)
+	}
+}
+
+func Test_Flags_001(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ragged.csv")
+	if err := os.WriteFile(path, []byte("a,b,c\n1,2,3\n4,5\n6,7,8,9\n10,say \"hi\",11\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sqlite3.OpenPathEx(filepath.Join(dir, "test.sqlite"), sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Without the flags, the ragged file is not imported
+	buf := new(bytes.Buffer)
+	log := log.New(buf, "", 0)
+	config := importConfig(log)
+	if config.LazyQuotes || config.FieldsPermissive || config.Log != log {
+		t.Error("Unexpected config", config)
+	}
+	writer, err := importer.NewSQLWriter(config, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := importFile(log, config, writer, path); len(result.Errors) == 0 {
+		t.Error("Expected errors, got", result)
+	}
+
+	// With the flags, short rows are padded and long rows truncated
+	for _, name := range []string{"lazyquotes", "fieldspermissive", "overwrite"} {
+		if err := flag.Set(name, "true"); err != nil {
+			t.Fatal(err)
+		}
+		defer flag.Set(name, "false")
+	}
+	config = importConfig(log)
+	if !config.LazyQuotes || !config.FieldsPermissive {
+		t.Error("Unexpected config", config)
+	}
+	writer, err = importer.NewSQLWriter(config, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := importFile(log, config, writer, path); len(result.Errors) != 0 {
+		t.Error("Unexpected errors", result.Errors)
+	} else if result.Inserted != 4 {
+		t.Error("Expected four rows inserted, got", result.Inserted)
+	}
+	if !strings.Contains(buf.String(), "truncated 4 fields to 3") {
+		t.Error("Expected warning, got", buf.String())
+	}
+}
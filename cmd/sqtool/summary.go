@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	// Modules
+	multierror "github.com/hashicorp/go-multierror"
+	importer "github.com/mutablelogic/go-sqlite/pkg/importer"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// summary is the machine-readable result of importing a file
+type summary struct {
+	URL      string          `json:"url"`
+	Table    string          `json:"table"`
+	Read     int             `json:"rows_read"`
+	Inserted int             `json:"rows_inserted"`
+	Columns  []summaryColumn `json:"columns"`
+	Errors   []string        `json:"errors,omitempty"`
+}
+
+// summaryColumn is a column with the type inferred from the values read
+type summaryColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// summaryWriter counts the rows read and infers the column types while
+// passing rows to a writer
+type summaryWriter struct {
+	importer.SQImportWriter
+	*summary
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Begin records the table name and columns, and returns a function which
+// records each row before writing it
+func (w *summaryWriter) Begin(name, schema string, cols []string) (importer.SQImportWriterFunc, error) {
+	fn, err := w.SQImportWriter.Begin(name, schema, cols)
+	if err != nil {
+		return nil, err
+	}
+	w.Table = name
+	w.Columns = make([]summaryColumn, len(cols))
+	for i, col := range cols {
+		w.Columns[i].Name = col
+	}
+	return func(row []interface{}) error {
+		w.Read++
+		for i, v := range row {
+			if i < len(w.Columns) {
+				w.Columns[i].Type = inferType(w.Columns[i].Type, v)
+			}
+		}
+		return fn(row)
+	}, nil
+}
+
+// AddError records an error, flattening multiple errors
+func (s *summary) AddError(err error) {
+	if errs, ok := err.(*multierror.Error); ok {
+		for _, err := range errs.Errors {
+			s.AddError(err)
+		}
+	} else if err != nil {
+		s.Errors = append(s.Errors, err.Error())
+	}
+}
+
+// writeSummary writes the summaries as JSON
+func writeSummary(w io.Writer, summaries []*summary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(summaries)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// inferType returns the column type for the values read so far, given the
+// type for the previous values and the next value. NULL values do not change
+// the type, integers and floats are REAL and other mixed types are TEXT
+func inferType(prev string, v interface{}) string {
+	var t string
+	switch v.(type) {
+	case nil:
+		return prev
+	case int64:
+		t = "INTEGER"
+	case float64:
+		t = "REAL"
+	case time.Time:
+		t = "TIMESTAMP"
+	case []byte:
+		t = "BLOB"
+	default:
+		t = "TEXT"
+	}
+	switch {
+	case prev == "" || prev == t:
+		return t
+	case (prev == "INTEGER" && t == "REAL") || (prev == "REAL" && t == "INTEGER"):
+		return "REAL"
+	default:
+		return "TEXT"
+	}
+}
@@ -0,0 +1,198 @@
+package sqlite
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// NullString is a string which may be NULL. It can be used as a bind
+// argument and as a scan type for a nullable column
+type NullString struct {
+	String string
+	Valid  bool // Valid is true if String is not NULL
+}
+
+// NullInt64 is an integer which may be NULL
+type NullInt64 struct {
+	Int64 int64
+	Valid bool // Valid is true if Int64 is not NULL
+}
+
+// NullFloat64 is a floating point value which may be NULL
+type NullFloat64 struct {
+	Float64 float64
+	Valid   bool // Valid is true if Float64 is not NULL
+}
+
+// NullTime is a time which may be NULL. Times are stored as TEXT in
+// RFC3339 format, and INTEGER values are scanned as unix seconds
+type NullTime struct {
+	Time  time.Time
+	Valid bool // Valid is true if Time is not NULL
+}
+
+// NullBytes is a byte slice which may be NULL
+type NullBytes struct {
+	Bytes []byte
+	Valid bool // Valid is true if Bytes is not NULL
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// SCANNER
+
+// Scan implements the sql.Scanner interface
+func (n *NullString) Scan(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		n.String, n.Valid = "", false
+	case string:
+		n.String, n.Valid = v, true
+	case []byte:
+		n.String, n.Valid = string(v), true
+	case int64, float64, bool:
+		n.String, n.Valid = fmt.Sprint(v), true
+	case time.Time:
+		n.String, n.Valid = v.UTC().Format(time.RFC3339), true
+	default:
+		return fmt.Errorf("NullString: cannot scan %T", v)
+	}
+	return nil
+}
+
+// Scan implements the sql.Scanner interface
+func (n *NullInt64) Scan(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		n.Int64, n.Valid = 0, false
+	case int64:
+		n.Int64, n.Valid = v, true
+	case float64:
+		n.Int64, n.Valid = int64(v), true
+	case bool:
+		n.Int64, n.Valid = boolToInt64(v), true
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		n.Int64, n.Valid = i, true
+	default:
+		return fmt.Errorf("NullInt64: cannot scan %T", v)
+	}
+	return nil
+}
+
+// Scan implements the sql.Scanner interface
+func (n *NullFloat64) Scan(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		n.Float64, n.Valid = 0, false
+	case float64:
+		n.Float64, n.Valid = v, true
+	case int64:
+		n.Float64, n.Valid = float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		n.Float64, n.Valid = f, true
+	default:
+		return fmt.Errorf("NullFloat64: cannot scan %T", v)
+	}
+	return nil
+}
+
+// Scan implements the sql.Scanner interface
+func (n *NullTime) Scan(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		n.Time, n.Valid = time.Time{}, false
+	case time.Time:
+		n.Time, n.Valid = v, true
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return err
+		}
+		n.Time, n.Valid = t, true
+	case int64:
+		n.Time, n.Valid = time.Unix(v, 0).UTC(), true
+	default:
+		return fmt.Errorf("NullTime: cannot scan %T", v)
+	}
+	return nil
+}
+
+// Scan implements the sql.Scanner interface. The bytes are copied
+func (n *NullBytes) Scan(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		n.Bytes, n.Valid = nil, false
+	case []byte:
+		n.Bytes, n.Valid = append([]byte{}, v...), true
+	case string:
+		n.Bytes, n.Valid = []byte(v), true
+	default:
+		return fmt.Errorf("NullBytes: cannot scan %T", v)
+	}
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// VALUER
+
+// Value implements the driver.Valuer interface
+func (n NullString) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.String, nil
+}
+
+// Value implements the driver.Valuer interface
+func (n NullInt64) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Int64, nil
+}
+
+// Value implements the driver.Valuer interface
+func (n NullFloat64) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Float64, nil
+}
+
+// Value implements the driver.Valuer interface
+func (n NullTime) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Time, nil
+}
+
+// Value implements the driver.Valuer interface
+func (n NullBytes) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Bytes, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func boolToInt64(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"log"
 
 	// Namespace Imports
 	. "github.com/mutablelogic/go-sqlite"
@@ -13,11 +14,16 @@ import (
 // TYPES
 
 type csvdecoder struct {
-	c      io.Closer
-	r      *csv.Reader
-	header bool
-	cols   []string
-	values []interface{}
+	c          io.Closer
+	r          *csv.Reader
+	header     bool
+	permissive bool
+	null       string
+	nullempty  bool
+	parser     *valueparser
+	log        *log.Logger
+	cols       []string
+	values     []interface{}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -25,7 +31,7 @@ type csvdecoder struct {
 
 // NewCSVDecoder returns a CSV decoder setting options
 func (this *Importer) NewCSVDecoder(c io.Closer, r io.Reader, delimiter rune) (SQImportDecoder, error) {
-	decoder := &csvdecoder{c, csv.NewReader(r), this.c.Header, nil, nil}
+	decoder := &csvdecoder{c, csv.NewReader(r), this.c.Header, this.c.FieldsPermissive, this.c.Null, this.c.NullEmpty, newValueParser(this.c), this.c.Log, nil, nil}
 
 	// Set delimiter
 	if this.c.Delimiter != 0 {
@@ -42,6 +48,12 @@ func (this *Importer) NewCSVDecoder(c io.Closer, r io.Reader, delimiter rune) (S
 	decoder.r.LazyQuotes = this.c.LazyQuotes
 	decoder.r.ReuseRecord = true
 
+	// Allow a variable number of fields per row
+	if this.c.FieldsPermissive {
+		decoder.r.FieldsPerRecord = -1
+		decoder.r.LazyQuotes = true
+	}
+
 	// Return success
 	return decoder, nil
 }
@@ -84,15 +96,29 @@ func (this *csvdecoder) Read() ([]string, []interface{}, error) {
 		}
 	}
 
-	// Add new column headings as necessary, populate values
+	// Truncate long rows when permissive, otherwise add new column
+	// headings as necessary
+	if this.permissive && len(row) > len(this.cols) {
+		if this.log != nil {
+			line, _ := this.r.FieldPos(0)
+			this.log.Printf("line %d: truncated %d fields to %d", line, len(row), len(this.cols))
+		}
+		row = row[:len(this.cols)]
+	}
 	for len(row) > len(this.cols) {
 		this.cols = append(this.cols, this.makeCol(len(this.cols)))
 	}
-	if len(this.values) != len(row) {
+
+	// Populate values, padding short rows with NULL values
+	if len(this.values) != len(this.cols) {
 		this.values = make([]interface{}, len(this.cols))
 	}
-	for i, v := range row {
-		this.values[i] = v
+	for i := range this.values {
+		if i < len(row) && !this.isNull(row[i]) {
+			this.values[i] = this.parser.parse(row[i])
+		} else {
+			this.values[i] = nil
+		}
 	}
 
 	// Return
@@ -102,6 +128,15 @@ func (this *csvdecoder) Read() ([]string, []interface{}, error) {
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// Return true if the field value should be imported as NULL
+func (this *csvdecoder) isNull(v string) bool {
+	if v == "" {
+		return this.nullempty
+	} else {
+		return this.null != "" && v == this.null
+	}
+}
+
 // Return a column heading for the given index
 func (this *csvdecoder) makeCol(i int) string {
 	return fmt.Sprintf("col_%02d", i)
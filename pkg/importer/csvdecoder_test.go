@@ -0,0 +1,145 @@
+package importer_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	// Packages
+	importer "github.com/mutablelogic/go-sqlite/pkg/importer"
+
+	// Namespace Imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+)
+
+func Test_Decoder_001(t *testing.T) {
+	var buf bytes.Buffer
+	config := importer.DefaultConfig
+	config.FieldsPermissive = true
+	config.Log = log.New(&buf, "", 0)
+	i, err := importer.NewImporter(config, "test.csv", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ragged rows with lazy quotes
+	data := "a,b,c\n1,2,3\n4,5\n6,7,8,9\n10,say \"hi\",11\n"
+	dec, err := i.NewCSVDecoder(io.NopCloser(nil), strings.NewReader(data), ',')
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{
+		{"1", "2", "3"},
+		{"4", "5", nil},
+		{"6", "7", "8"},
+		{"10", "say \"hi\"", "11"},
+	}
+	rows := readAll(t, dec)
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %q, got %q", expected, rows)
+	}
+	if !strings.Contains(buf.String(), "line 4: truncated 4 fields to 3") {
+		t.Error("Unexpected log output", buf.String())
+	}
+}
+
+func Test_Decoder_002(t *testing.T) {
+	config := importer.DefaultConfig
+	i, err := importer.NewImporter(config, "test.csv", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ragged rows are an error when not permissive
+	dec, err := i.NewCSVDecoder(io.NopCloser(nil), strings.NewReader("a,b\n1,2\n3\n"), ',')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := dec.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := dec.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := dec.Read(); err == nil || errors.Is(err, io.EOF) {
+		t.Error("Expected error for ragged row, got", err)
+	}
+}
+
+func Test_Decoder_003(t *testing.T) {
+	var tests = []struct {
+		null      string
+		nullempty bool
+		expected  [][]interface{}
+	}{
+		{"", false, [][]interface{}{{"1", "", "NULL"}, {"", `\N`, ""}}},
+		{"", true, [][]interface{}{{"1", nil, "NULL"}, {nil, `\N`, nil}}},
+		{"NULL", false, [][]interface{}{{"1", "", nil}, {"", `\N`, ""}}},
+		{`\N`, true, [][]interface{}{{"1", nil, "NULL"}, {nil, nil, nil}}},
+	}
+	for _, test := range tests {
+		config := importer.DefaultConfig
+		config.Null = test.null
+		config.NullEmpty = test.nullempty
+		i, err := importer.NewImporter(config, "test.csv", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dec, err := i.NewCSVDecoder(io.NopCloser(nil), strings.NewReader("a,b,c\n1,,NULL\n,\\N,\"\"\n"), ',')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rows := readAll(t, dec); !reflect.DeepEqual(rows, test.expected) {
+			t.Errorf("null=%q nullempty=%v: Expected %q, got %q", test.null, test.nullempty, test.expected, rows)
+		}
+	}
+}
+
+func readAll(t *testing.T, dec SQImportDecoder) [][]interface{} {
+	var result [][]interface{}
+	for {
+		cols, values, err := dec.Read()
+		if errors.Is(err, io.EOF) {
+			return result
+		} else if err != nil {
+			t.Fatal(err)
+		} else if cols != nil && values != nil {
+			result = append(result, append([]interface{}{}, values...))
+		}
+	}
+}
+
+func Test_Decoder_004(t *testing.T) {
+	config := importer.DefaultConfig
+	config.DecimalSep = ','
+	config.ThousandsSep = '.'
+	config.DateFormats = []string{"02/01/2006", "02/01/2006 15:04"}
+	i, err := importer.NewImporter(config, "test.csv", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := i.NewCSVDecoder(io.NopCloser(nil), strings.NewReader("a;b;c;d\n1.234,56;-42;31/12/2021;007\n1.234.567;0,5;01/02/2022 13:45;1.2.3\n12,34,5;1234;2022-02-01;hello\n"), ';')
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{
+		{1234.56, int64(-42), time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC), "007"},
+		{int64(1234567), 0.5, time.Date(2022, 2, 1, 13, 45, 0, 0, time.UTC), "1.2.3"},
+		{"12,34,5", int64(1234), "2022-02-01", "hello"},
+	}
+	if rows := readAll(t, dec); !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, rows)
+	}
+
+	// Separators must be different
+	config.ThousandsSep = ','
+	if _, err := importer.NewImporter(config, "test.csv", nil); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+}
@@ -0,0 +1,182 @@
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	// Namespace Imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type Exporter struct {
+	c    SQExportConfig
+	conn SQConnection
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	DefaultExportConfig = SQExportConfig{
+		Header:     true,
+		Delimiter:  ',',
+		TimeLayout: time.RFC3339,
+	}
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Create an exporter with default configuation
+func DefaultExporter(conn SQConnection) (*Exporter, error) {
+	return NewExporter(DefaultExportConfig, conn)
+}
+
+// Create a new exporter which reads from a database connection
+func NewExporter(c SQExportConfig, conn SQConnection) (*Exporter, error) {
+	this := &Exporter{
+		c:    c,
+		conn: conn,
+	}
+
+	// Check parameters
+	if conn == nil {
+		return nil, ErrBadParameter.With("NewExporter")
+	}
+
+	// Set defaults
+	if this.c.Delimiter == 0 {
+		this.c.Delimiter = DefaultExportConfig.Delimiter
+	}
+	if this.c.TimeLayout == "" {
+		this.c.TimeLayout = DefaultExportConfig.TimeLayout
+	}
+	if this.c.Location == nil {
+		this.c.Location = time.UTC
+	}
+
+	// Return success
+	return this, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Exporter) String() string {
+	return fmt.Sprintf("<text/csv delimiter=%q layout=%q tz=%q>", this.c.Delimiter, this.c.TimeLayout, this.c.Location)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Export executes a query and writes the rows to w as CSV, with the column
+// names as the first row if the header option is set. Rows are streamed
+// to the writer as they are read.
+func (this *Exporter) Export(ctx context.Context, w io.Writer, q SQStatement, args ...interface{}) error {
+	return this.conn.Do(ctx, 0, func(txn SQTransaction) error {
+		rs, err := txn.Query(q, args...)
+		if err != nil {
+			return err
+		}
+
+		// Set the header and types for casting, time values are cast from
+		// columns with a date or time declared type
+		cols := rs.Columns()
+		header := make([]string, len(cols))
+		types := make([]reflect.Type, len(cols))
+		for i, col := range cols {
+			header[i] = col.Name()
+			if isTimeDeclType(col.Type()) {
+				types[i] = timeType
+			}
+		}
+
+		// Create the writer
+		writer := csv.NewWriter(w)
+		writer.Comma = this.c.Delimiter
+		if this.c.Header {
+			if err := writer.Write(header); err != nil {
+				return err
+			}
+		}
+
+		// Write the rows
+		record := make([]string, len(cols))
+		for {
+			row := rs.Next(types...)
+			if row == nil {
+				break
+			}
+			for i, v := range row {
+				record[i] = this.format(v)
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+
+		// Flush the writer
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+
+		// Return any context error, as iteration stops on interrupt
+		if ctx != nil {
+			return ctx.Err()
+		} else {
+			return nil
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// format returns a field value as a string
+func (this *Exporter) format(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return this.c.Null
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if v.IsZero() {
+			return this.c.Null
+		}
+		return v.In(this.c.Location).Format(this.c.TimeLayout)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// isTimeDeclType returns true if the declared type of a column
+// is a date or time
+func isTimeDeclType(decltype string) bool {
+	switch strings.ToUpper(decltype) {
+	case "TIMESTAMP", "DATETIME", "DATE":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,140 @@
+package importer_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	// Packages
+	importer "github.com/mutablelogic/go-sqlite/pkg/importer"
+	sqlite3 "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+)
+
+func Test_Exporter_001(t *testing.T) {
+	pool, err := sqlite3.NewPool(":memory:", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Create a table with rows
+	ts := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if _, err := txn.Query(N("src").CreateTable(C("a").WithType("INTEGER"), C("b"), C("c").WithType("TIMESTAMP"))); err != nil {
+			return err
+		}
+		if _, err := txn.Query(N("src").Insert("a", "b", "c"), 1, "hello, world", ts); err != nil {
+			return err
+		}
+		if _, err := txn.Query(N("src").Insert("a", "b", "c"), 2, "say \"hi\"", nil); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Export the table
+	exporter, err := importer.DefaultExporter(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := exporter.Export(context.Background(), buf, S(N("src")).Order(N("a"))); err != nil {
+		t.Fatal(err)
+	}
+	expected := "a,b,c\n1,\"hello, world\",2021-10-01T12:00:00Z\n2,\"say \"\"hi\"\"\",\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected export: %q", buf.String())
+	}
+
+	// Re-import the table
+	writer, err := importer.NewSQLWriter(importer.DefaultConfig, conn.(*sqlite3.Conn).ConnEx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := importer.DefaultConfig
+	config.Name = "dest"
+	imp, err := importer.NewImporter(config, "dest.csv", writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := imp.NewCSVDecoder(io.NopCloser(nil), bytes.NewReader(buf.Bytes()), ',')
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if err := imp.ReadWrite(dec); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Export the imported table and compare
+	buf2 := new(bytes.Buffer)
+	if err := exporter.Export(context.Background(), buf2, S(N("dest")).Order(N("a"))); err != nil {
+		t.Fatal(err)
+	}
+	if buf2.String() != expected {
+		t.Errorf("Unexpected round-trip: %q", buf2.String())
+	}
+}
+
+func Test_Exporter_002(t *testing.T) {
+	pool, err := sqlite3.NewPool(":memory:", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Export with a NULL sentinel, timezone and delimiter
+	tz, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	exporter, err := importer.NewExporter(SQExportConfig{
+		Delimiter:  ';',
+		TimeLayout: "2006-01-02 15:04",
+		Location:   tz,
+		Null:       "NULL",
+	}, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if _, err := txn.Query(N("src").CreateTable(C("a"), C("b").WithType("TIMESTAMP"))); err != nil {
+			return err
+		}
+		if _, err := txn.Query(N("src").Insert("a", "b"), nil, ts); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := exporter.Export(context.Background(), buf, S(N("src"))); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "NULL;2021-10-01 14:00" {
+		t.Errorf("Unexpected export: %q", buf.String())
+	}
+}
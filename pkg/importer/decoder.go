@@ -1,8 +1,11 @@
 package importer
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"mime"
+	"net/http"
 
 	// Namespace Imports
 	"github.com/hashicorp/go-multierror"
@@ -23,19 +26,42 @@ func (this *Importer) Decoder(mimetype string) (SQImportDecoder, error) {
 		guessedmimetype = mimetype
 	}
 
+	// Return the decoder for the reader
+	return this.NewDecoder(r, guessedmimetype)
+}
+
+// Return a new decoder which reads from r, for the given mimetype. When the
+// mimetype is empty it is detected from the data or the file extension. The
+// reader is closed when the decoder is closed.
+func (this *Importer) NewDecoder(r io.ReadCloser, mimetype string) (SQImportDecoder, error) {
+	// Detect the mimetype from the start of the data
+	var br io.Reader = r
+	if mimetype == "" {
+		buf := bufio.NewReader(r)
+		if data, err := buf.Peek(512); err != nil && err != io.EOF {
+			if err_ := r.Close(); err_ != nil {
+				err = multierror.Append(err, err_)
+			}
+			return nil, err
+		} else if mimetype = http.DetectContentType(data); mimetype == "application/octet-stream" {
+			mimetype = mime.TypeByExtension(this.c.Ext)
+		}
+		br = buf
+	}
+
 	// Parse mediatype
-	mediatype, params, err := mime.ParseMediaType(guessedmimetype)
+	mediatype, params, err := mime.ParseMediaType(mimetype)
 	if err != nil {
-		if err_ := r.Close(); err != nil {
+		if err_ := r.Close(); err_ != nil {
 			err = multierror.Append(err, err_)
 		}
 		return nil, err
 	}
 
 	// Set charset
-	cr, err := charsetReader(r, params["charset"])
+	cr, err := charsetReader(br, params["charset"])
 	if err != nil {
-		if err_ := r.Close(); err != nil {
+		if err_ := r.Close(); err_ != nil {
 			err = multierror.Append(err, err_)
 		}
 		return nil, err
@@ -45,15 +71,15 @@ func (this *Importer) Decoder(mimetype string) (SQImportDecoder, error) {
 	// parameters
 	switch {
 	case mediatype == "application/vnd.ms-excel":
-		return this.NewXLSDecoder(r)
+		return this.NewXLSDecoder(br)
 	case mediatype == "application/excel":
-		return this.NewXLSDecoder(r)
+		return this.NewXLSDecoder(br)
 	case mediatype == "application/x-excel":
-		return this.NewXLSDecoder(r)
+		return this.NewXLSDecoder(br)
 	case mediatype == "application/x-msexcel":
-		return this.NewXLSDecoder(r)
+		return this.NewXLSDecoder(br)
 	case mediatype == "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
-		return this.NewXLSDecoder(r)
+		return this.NewXLSDecoder(br)
 	case mediatype == "text/csv":
 		return this.NewCSVDecoder(r, cr, ',')
 	case mediatype == "text/tsv":
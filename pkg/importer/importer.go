@@ -11,6 +11,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 
 	// Namespace Imports
+	. "github.com/djthorpe/go-errors"
 	. "github.com/mutablelogic/go-sqlite"
 )
 
@@ -27,6 +28,11 @@ type Importer struct {
 ///////////////////////////////////////////////////////////////////////////////
 // GLOBALS
 
+const (
+	// Stdin is the URL used to read data from standard input
+	Stdin = "-"
+)
+
 var (
 	DefaultConfig = SQImportConfig{
 		Header:     true,
@@ -57,6 +63,21 @@ func NewImporter(c SQImportConfig, u string, w SQImportWriter) (*Importer, error
 		this.url = url
 	}
 
+	// When reading from standard input, the table name is required and the
+	// extension defaults to CSV
+	if this.url.Scheme == "" && this.url.Path == Stdin {
+		if this.c.Name == "" {
+			return nil, ErrBadParameter.With("Table name is required when reading from standard input")
+		} else if this.c.Ext == "" {
+			this.c.Ext = ".csv"
+		}
+	}
+
+	// Check the numeric separators are different
+	if this.c.DecimalSep != 0 && this.c.DecimalSep == this.c.ThousandsSep {
+		return nil, ErrBadParameter.With("Decimal and thousands separators are the same")
+	}
+
 	// Set the table name and extension if not already set
 	if this.c.Name == "" {
 		this.c.Name = filepath.Base(this.url.String())
@@ -0,0 +1,86 @@
+package importer_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	// Packages
+	importer "github.com/mutablelogic/go-sqlite/pkg/importer"
+	sqlite3 "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+
+	// Namespace Imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+)
+
+func Test_Importer_001(t *testing.T) {
+	// A table name is required when reading from standard input
+	if _, err := importer.NewImporter(importer.DefaultConfig, importer.Stdin, nil); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+}
+
+func Test_Importer_002(t *testing.T) {
+	pool, err := sqlite3.NewPool(":memory:", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Pipe CSV data through standard input
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+	go func() {
+		w.Write([]byte("a,b\n1,hello\n2,world\n"))
+		w.Close()
+	}()
+
+	// Import the data
+	writer, err := importer.NewSQLWriter(importer.DefaultConfig, conn.(*sqlite3.Conn).ConnEx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := importer.DefaultConfig
+	config.Name = "test"
+	imp, err := importer.NewImporter(config, importer.Stdin, writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := imp.Decoder("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if err := imp.ReadWrite(dec); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Check the table contents
+	var rows [][]string
+	if err := conn.Exec(S(N("test")).Order(N("a")), func(row, _ []string) bool {
+		rows = append(rows, row)
+		return false
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{{"1", "hello"}, {"2", "world"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, rows)
+	}
+}
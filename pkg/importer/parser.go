@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// valueparser converts field values into numbers and times, according to
+// the separators and date layouts in the import configuration
+type valueparser struct {
+	numbers   bool
+	decimal   rune
+	thousands rune
+	layouts   []string
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// newValueParser returns a parser for field values, or nil if no
+// parsing options are set
+func newValueParser(c SQImportConfig) *valueparser {
+	parser := &valueparser{c.DecimalSep != 0 || c.ThousandsSep != 0, c.DecimalSep, c.ThousandsSep, c.DateFormats}
+	if !parser.numbers && len(parser.layouts) == 0 {
+		return nil
+	}
+	if parser.decimal == 0 {
+		parser.decimal = '.'
+	}
+	return parser
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// parse returns a field value as an int64, float64 or time.Time if it
+// fully parses, or else returns the string unchanged
+func (p *valueparser) parse(v string) interface{} {
+	if p == nil {
+		return v
+	}
+	if p.numbers {
+		if n, ok := p.number(v); ok {
+			return n
+		}
+	}
+	for _, layout := range p.layouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+	return v
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// number parses a number with an optional sign, thousands separators between
+// groups of three digits, and a decimal separator. Integers with a leading
+// zero, such as "007", are not parsed
+func (p *valueparser) number(v string) (interface{}, bool) {
+	sign := ""
+	if strings.HasPrefix(v, "-") || strings.HasPrefix(v, "+") {
+		sign, v = v[:1], v[1:]
+	}
+	integer, fraction, hasFraction := v, "", false
+	if i := strings.IndexRune(v, p.decimal); i >= 0 {
+		integer, fraction, hasFraction = v[:i], v[i+len(string(p.decimal)):], true
+	}
+	if hasFraction && !isDigits(fraction) {
+		return nil, false
+	}
+	if p.thousands != 0 && strings.ContainsRune(integer, p.thousands) {
+		groups := strings.Split(integer, string(p.thousands))
+		for i, group := range groups {
+			if !isDigits(group) || (i == 0 && len(group) > 3) || (i > 0 && len(group) != 3) {
+				return nil, false
+			}
+		}
+		integer = strings.Join(groups, "")
+	}
+	if !isDigits(integer) || (len(integer) > 1 && integer[0] == '0') {
+		return nil, false
+	}
+
+	// Return an integer or a float
+	if !hasFraction {
+		if n, err := strconv.ParseInt(sign+integer, 10, 64); err == nil {
+			return n, true
+		}
+	}
+	if f, err := strconv.ParseFloat(sign+integer+"."+fraction, 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
+
+// isDigits returns true if a string is non-empty and contains only digits
+func isDigits(v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, r := range v {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -20,7 +20,10 @@ import (
 
 // Open the table for reading, return a reader and a mimetype
 func open(url *url.URL) (io.ReadCloser, string, error) {
-	if url.Scheme == "file" || url.Scheme == "" {
+	if url.Scheme == "" && url.Path == Stdin {
+		// The mimetype is detected from the data when the decoder is created
+		return io.NopCloser(os.Stdin), "", nil
+	} else if url.Scheme == "file" || url.Scheme == "" {
 		if mimetype, err := detectMimetype(url.Path); err != nil {
 			return nil, "", err
 		} else if fh, err := os.Open(url.Path); err != nil {
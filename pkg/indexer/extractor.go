@@ -0,0 +1,134 @@
+package indexer
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	// Import namepaces
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Extractor returns the text content of a file, for indexing
+type Extractor func(io.Reader) (string, error)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Number of bytes used to sniff the content type of a file
+	sniffLen = 512
+)
+
+var (
+	extractorLock sync.RWMutex
+	extractors    = make(map[string]Extractor)
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterExtractor registers a function which extracts text content from a
+// file. The key is either a file extension (ie, ".md") or a mimetype
+// (ie, "text/html"). Registering a nil function removes an existing extractor.
+func RegisterExtractor(ext string, fn Extractor) error {
+	key := extractorKey(ext)
+	if key == "" || key == "." {
+		return ErrBadParameter.With("RegisterExtractor: ", ext)
+	}
+
+	extractorLock.Lock()
+	defer extractorLock.Unlock()
+	if fn == nil {
+		delete(extractors, key)
+	} else {
+		extractors[key] = fn
+	}
+
+	// Return success
+	return nil
+}
+
+// Extract returns the text content of a file at path. An extractor is chosen
+// by file extension first, then by sniffed mimetype. Where no extractor is
+// registered, UTF-8 text is returned as-is and binary content is skipped, by
+// returning an empty string.
+func Extract(path string) (string, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer fh.Close()
+
+	// Sniff the content type
+	r := bufio.NewReaderSize(fh, sniffLen)
+	data, err := r.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	// Extract by file extension or content type
+	if fn := extractorFor(filepath.Ext(path)); fn != nil {
+		return fn(r)
+	} else if fn := extractorFor(contentType(path, data)); fn != nil {
+		return fn(r)
+	}
+
+	// Fallback to UTF-8 text, skip binary files
+	if !utf8.Valid(data) {
+		return "", nil
+	} else if text, err := io.ReadAll(r); err != nil {
+		return "", err
+	} else if !utf8.Valid(text) {
+		return "", nil
+	} else {
+		return string(text), nil
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// extractorKey returns an extension or mediatype in lowercase, without
+// parameters
+func extractorKey(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if strings.Contains(ext, "/") {
+		if mediatype, _, err := mime.ParseMediaType(ext); err == nil {
+			return mediatype
+		}
+	} else if ext != "" && !strings.HasPrefix(ext, ".") {
+		return "." + ext
+	}
+	return ext
+}
+
+// extractorFor returns the extractor for an extension or mediatype, or nil
+func extractorFor(ext string) Extractor {
+	key := extractorKey(ext)
+	if key == "" {
+		return nil
+	}
+	extractorLock.RLock()
+	defer extractorLock.RUnlock()
+	return extractors[key]
+}
+
+// contentType returns the sniffed content type of the data, or
+// the content type registered for the file extension
+func contentType(path string, data []byte) string {
+	if mimetype := http.DetectContentType(data); mimetype != "application/octet-stream" {
+		return mimetype
+	} else {
+		return mime.TypeByExtension(filepath.Ext(path))
+	}
+}
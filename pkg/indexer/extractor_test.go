@@ -0,0 +1,146 @@
+package indexer_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	// Packages
+	sqlite3 "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+
+	// Namespace imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/indexer"
+)
+
+func Test_Extractor_001(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "indexer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	// Register a fake extractor which uppercases content
+	if err := RegisterExtractor(".fake", func(r io.Reader) (string, error) {
+		data, err := io.ReadAll(r)
+		return "extracted " + strings.ToUpper(string(data)), err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer RegisterExtractor(".fake", nil)
+
+	// Write files
+	files := map[string][]byte{
+		"a.fake": []byte("hello"),
+		"b.txt":  []byte("plain text"),
+		"c.bin":  {0x00, 0xFF, 0xFE, 0x00},
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(tmpdir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Extract content
+	if text, err := Extract(filepath.Join(tmpdir, "a.fake")); err != nil {
+		t.Error(err)
+	} else if text != "extracted HELLO" {
+		t.Errorf("Unexpected text %q", text)
+	}
+	if text, err := Extract(filepath.Join(tmpdir, "b.txt")); err != nil {
+		t.Error(err)
+	} else if text != "plain text" {
+		t.Errorf("Unexpected text %q", text)
+	}
+	if text, err := Extract(filepath.Join(tmpdir, "c.bin")); err != nil {
+		t.Error(err)
+	} else if text != "" {
+		t.Errorf("Expected binary file to be skipped, got %q", text)
+	}
+}
+
+func Test_Extractor_002(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "indexer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	// Register a fake extractor which returns a word not in the file
+	if err := RegisterExtractor(".fake", func(r io.Reader) (string, error) {
+		return "fakeword", nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer RegisterExtractor(".fake", nil)
+
+	files := map[string]string{"a.fake": "hello", "b.txt": "world"}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(tmpdir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pool, err := sqlite3.NewPool(":memory:", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	conn := pool.Get()
+	defer pool.Put(conn)
+	if err := CreateSchema(context.Background(), conn, "main", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Walk the files, draining the queue until reindexing is completed
+	queue := NewQueue()
+	indexer, err := NewIndexer("test", tmpdir, queue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go indexer.Run(ctx, nil)
+	if err := indexer.Walk(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	var evts []*QueueEvent
+	for ctx.Err() == nil {
+		evt := queue.Next()
+		if evt == nil {
+			time.Sleep(time.Millisecond)
+		} else if evt.EventType == EventAdd {
+			evts = append(evts, evt)
+		} else if evt.EventType == EventReindexCompleted {
+			break
+		}
+	}
+
+	// Index the files and search for the extracted content
+	var found []string
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		for _, evt := range evts {
+			q, args := Replace("main", evt)
+			if _, err := txn.Query(q, args...); err != nil {
+				return err
+			}
+		}
+		r, err := txn.Query(Query("main", false, false), "fakeword")
+		if err != nil {
+			return err
+		}
+		for row := r.Next(); row != nil; row = r.Next() {
+			found = append(found, row[6].(string))
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0] != "a.fake" {
+		t.Errorf("Unexpected search results %q", found)
+	}
+}
@@ -23,11 +23,16 @@ import (
 
 type Indexer struct {
 	*walkfs.WalkFS
-	queue    *Queue
-	name     string
-	path     string
-	walk     chan WalkFunc
-	indexing bool
+	sync.Mutex
+	queue       *Queue
+	name        string
+	path        string
+	walk        chan WalkFunc
+	indexing    bool
+	interrupted bool
+	resume      chan struct{}      // Non-nil when reindexing is paused
+	cancel      context.CancelFunc // Cancels reindexing
+	done        chan struct{}      // Closed when reindexing ends
 }
 
 // WalkFunc is called after a reindexing with any walk errors
@@ -108,15 +113,18 @@ FOR_LOOP:
 				defer walking.Unlock()
 
 				// Indicate reindexing is in progress
-				i.indexing = true
+				walkctx := i.start(ctx)
 				i.queue.Mark(i.name, i.path, true)
 				defer func() {
 					i.queue.Mark(i.name, i.path, false)
-					i.indexing = false
+					i.end(walkctx.Err() != nil && ctx.Err() == nil)
 				}()
 
 				// Start the walk and return any errors
-				fn(i.WalkFS.Walk(ctx, i.path))
+				err := i.WalkFS.Walk(walkctx, i.path)
+				if fn != nil {
+					fn(err)
+				}
 			}()
 		}
 	}
@@ -164,9 +172,25 @@ func (i *Indexer) Queue() *Queue {
 
 // Return true if indexing
 func (i *Indexer) IsIndexing() bool {
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
 	return i.indexing
 }
 
+// Return true if indexing is paused
+func (i *Indexer) IsPaused() bool {
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
+	return i.resume != nil
+}
+
+// Return true if the last reindexing was cancelled before completion
+func (i *Indexer) IsInterrupted() bool {
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
+	return i.interrupted
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
@@ -183,9 +207,98 @@ func (i *Indexer) Walk(ctx context.Context, fn WalkFunc) error {
 	return nil
 }
 
+// Pause reindexing, which continues when Resume is called. Returns an
+// error if reindexing is not in progress
+func (i *Indexer) Pause() error {
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
+	if !i.indexing {
+		return ErrOutOfOrder.With("Pause: not indexing: ", strconv.Quote(i.name))
+	}
+	if i.resume == nil {
+		i.resume = make(chan struct{})
+	}
+	return nil
+}
+
+// Resume paused reindexing. Returns an error if reindexing is not paused
+func (i *Indexer) Resume() error {
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
+	if i.resume == nil {
+		return ErrOutOfOrder.With("Resume: not paused: ", strconv.Quote(i.name))
+	}
+	close(i.resume)
+	i.resume = nil
+	return nil
+}
+
+// Cancel reindexing and block until reindexing has ended or the context is
+// cancelled. Files which have already been indexed remain in the index.
+// Returns an error if reindexing is not in progress
+func (i *Indexer) Cancel(ctx context.Context) error {
+	i.Mutex.Lock()
+	if !i.indexing {
+		i.Mutex.Unlock()
+		return ErrOutOfOrder.With("Cancel: not indexing: ", strconv.Quote(i.name))
+	}
+	cancel, done := i.cancel, i.done
+	i.Mutex.Unlock()
+
+	// Cancel and wait for reindexing to end
+	cancel()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// start sets the state when reindexing starts, and returns a context
+// which is cancelled by Cancel
+func (i *Indexer) start(ctx context.Context) context.Context {
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
+	ctx, i.cancel = context.WithCancel(ctx)
+	i.done = make(chan struct{})
+	i.indexing = true
+	i.interrupted = false
+	return ctx
+}
+
+// end sets the state when reindexing ends
+func (i *Indexer) end(interrupted bool) {
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
+	i.cancel()
+	close(i.done)
+	if i.resume != nil {
+		close(i.resume)
+		i.resume = nil
+	}
+	i.indexing = false
+	i.interrupted = interrupted
+}
+
+// wait blocks while reindexing is paused, and returns false if reindexing
+// is cancelled
+func (i *Indexer) wait(ctx context.Context) bool {
+	i.Mutex.Lock()
+	resume := i.resume
+	i.Mutex.Unlock()
+	if resume != nil {
+		select {
+		case <-ctx.Done():
+		case <-resume:
+		}
+	}
+	return ctx.Err() == nil
+}
+
 // event is used to process an event from the notify
 func (i *Indexer) event(ctx context.Context, evt notify.EventInfo) error {
 	relpath, err := filepath.Rel(i.path, evt.Path())
@@ -196,12 +309,12 @@ func (i *Indexer) event(ctx context.Context, evt notify.EventInfo) error {
 	case notify.Create, notify.Write:
 		info, err := os.Stat(evt.Path())
 		if err == nil && info.Mode().IsRegular() && i.ShouldVisit(relpath, info) {
-			i.queue.Add(i.name, relpath, info)
+			i.add(evt.Path(), relpath, info)
 		}
 	case notify.Remove, notify.Rename:
 		info, err := os.Stat(evt.Path())
 		if err == nil && info.Mode().IsRegular() && i.ShouldVisit(relpath, info) {
-			i.queue.Add(i.name, relpath, info)
+			i.add(evt.Path(), relpath, info)
 		} else {
 			// Always attempt removal from index
 			i.queue.Remove(i.name, relpath)
@@ -213,12 +326,22 @@ func (i *Indexer) event(ctx context.Context, evt notify.EventInfo) error {
 
 // visit is used to index a file from the indexer
 func (i *Indexer) visit(ctx context.Context, abspath, relpath string, info fs.FileInfo) error {
+	if !i.wait(ctx) {
+		return nil
+	}
 	if info.Mode().IsRegular() {
-		i.queue.Add(i.name, relpath, info)
+		i.add(filepath.Join(abspath, relpath), relpath, info)
 	}
 	return nil
 }
 
+// add queues a file with the content hash and the extracted text content.
+// Content which cannot be extracted is not indexed
+func (i *Indexer) add(path, relpath string, info fs.FileInfo) {
+	content, _ := Extract(path)
+	i.queue.AddWithContent(i.name, relpath, info, fileHash(path), content)
+}
+
 // senderr is used to send an error without blocking
 func senderr(ch chan<- error, err error) {
 	if ch != nil {
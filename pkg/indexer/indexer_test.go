@@ -2,6 +2,9 @@ package indexer_test
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -62,6 +65,83 @@ func Test_Indexer_001(t *testing.T) {
 	wg.Wait()
 }
 
+func Test_Indexer_002(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "indexer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	for i := 0; i < 2000; i++ {
+		if err := os.WriteFile(filepath.Join(tmpdir, fmt.Sprint("file", i, ".txt")), []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Create indexer, and drain the queue
+	queue := NewQueue()
+	indexer, err := NewIndexer("test", tmpdir, queue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for ctx.Err() == nil {
+			if queue.Next() == nil {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+	go indexer.Run(ctx, nil)
+
+	// Start reindexing and pause
+	if err := indexer.Pause(); err == nil {
+		t.Error("Expected error pausing when not indexing")
+	}
+	done := make(chan error, 1)
+	if err := indexer.Walk(ctx, func(err error) { done <- err }); err != nil {
+		t.Fatal(err)
+	}
+	for !indexer.IsIndexing() {
+		select {
+		case <-done:
+			t.Skip("Reindexing completed before pause")
+		default:
+			time.Sleep(time.Microsecond)
+		}
+	}
+	if err := indexer.Pause(); err != nil {
+		t.Skip("Reindexing completed before pause")
+	} else if !indexer.IsPaused() {
+		t.Error("Expected indexer to be paused")
+	}
+	select {
+	case <-done:
+		t.Error("Expected reindexing to be paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Cancel reindexing, which should stop promptly
+	timeout, cancel2 := context.WithTimeout(ctx, time.Second)
+	defer cancel2()
+	if err := indexer.Cancel(timeout); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected reindexing to end")
+	}
+	if indexer.IsIndexing() || indexer.IsPaused() {
+		t.Error("Expected reindexing to have ended")
+	} else if !indexer.IsInterrupted() {
+		t.Error("Expected reindexing to be interrupted")
+	}
+	if err := indexer.Resume(); err == nil {
+		t.Error("Expected error resuming when not paused")
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
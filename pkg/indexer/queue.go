@@ -18,9 +18,11 @@ type Queue struct {
 
 type QueueEvent struct {
 	EventType
-	Name string
-	Path string
-	Info fs.FileInfo
+	Name    string
+	Path    string
+	Info    fs.FileInfo
+	Hash    string // Content hash, or empty if not computed
+	Content string // Extracted text content, or empty if not extracted
 }
 
 type EventType uint
@@ -79,25 +81,49 @@ func (this *Queue) String() string {
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
-// Indicate reindexing in progress or completed
+// Indicate reindexing in progress or completed. A mark which has not been
+// read from the queue is replaced
 func (q *Queue) Mark(name, path string, flag bool) {
+	if elem := q.Get(name, path); elem != nil {
+		// Remove the element from the existing queue
+		q.del(name, path)
+	}
 	if flag {
-		q.add(EventReindexStarted, name, path, nil)
+		q.add(EventReindexStarted, name, path, nil, "", "")
 	} else {
-		q.add(EventReindexCompleted, name, path, nil)
+		q.add(EventReindexCompleted, name, path, nil, "", "")
 	}
 }
 
 // Add an item to the queue. If the item is already in the queue,
 // then it is bumped to the end of the queue
 func (q *Queue) Add(name, path string, info fs.FileInfo) {
+	q.AddWithHash(name, path, info, "")
+}
+
+// Add an item to the queue with a content hash. If the item is already
+// in the queue, then it is bumped to the end of the queue
+func (q *Queue) AddWithHash(name, path string, info fs.FileInfo, hash string) {
+	if elem := q.Get(name, path); elem != nil {
+		// Remove the element from the existing queue
+		q.del(name, path)
+	}
+
+	// Add the element to the queue
+	q.add(EventAdd, name, path, info, hash, "")
+}
+
+// Add an item to the queue with a content hash and the text content
+// extracted for indexing. If the item is already in the queue, then it is
+// bumped to the end of the queue
+func (q *Queue) AddWithContent(name, path string, info fs.FileInfo, hash, content string) {
 	if elem := q.Get(name, path); elem != nil {
 		// Remove the element from the existing queue
 		q.del(name, path)
 	}
 
 	// Add the element to the queue
-	q.add(EventAdd, name, path, info)
+	q.add(EventAdd, name, path, info, hash, content)
 }
 
 // Remove an item to the queue. If the item is already in the queue,
@@ -109,7 +135,7 @@ func (q *Queue) Remove(name, path string) {
 	}
 
 	// Add the element to the queue
-	q.add(EventRemove, name, path, nil)
+	q.add(EventRemove, name, path, nil, "", "")
 }
 
 // Return a queue event from the queue, or nil
@@ -151,7 +177,7 @@ func (q *Queue) Count() int {
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
-func (q *Queue) add(e EventType, name, path string, info fs.FileInfo) {
+func (q *Queue) add(e EventType, name, path string, info fs.FileInfo, hash, content string) {
 	q.RWMutex.Lock()
 	defer q.RWMutex.Unlock()
 	// This assumes the key does not exist
@@ -160,7 +186,7 @@ func (q *Queue) add(e EventType, name, path string, info fs.FileInfo) {
 		panic("Queue: key already exists, " + key)
 	}
 	q.q = append(q.q, key)
-	q.k[key] = &QueueEvent{e, name, path, info}
+	q.k[key] = &QueueEvent{e, name, path, info, hash, content}
 }
 
 func (q *Queue) del(name, path string) {
@@ -28,6 +28,8 @@ type File struct {
 	Ext      string    `sqlite:"ext,index:ext"`
 	ModTime  time.Time `sqlite:"modtime"`
 	Size     int64     `sqlite:"size"`
+	Hash     string    `sqlite:"hash,index:hash"` // Content hash
+	Content  string    `sqlite:"content"`         // Extracted text content
 }
 
 type Doc struct {
@@ -54,6 +56,13 @@ type View struct {
 	Title       string `sqlite:"title"`
 	Description string `sqlite:"description"`
 	Shortform   string `sqlite:"shortform"`
+	Content     string `sqlite:"content"`
+}
+
+// migrator is implemented by transactions which can add new columns to an
+// existing table
+type migrator interface {
+	Migrate(SQClass, string) ([]SQStatement, error)
 }
 
 // Search virtual table uses View to get content
@@ -64,6 +73,7 @@ type Search struct {
 	Title       string `sqlite:"title"`
 	Description string `sqlite:"description"`
 	Shortform   string `sqlite:"shortform"`
+	Content     string `sqlite:"content"`
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -94,6 +104,7 @@ var (
 		reflect.TypeOf(""),
 		reflect.TypeOf(time.Time{}),
 		reflect.TypeOf(int64(0)),
+		reflect.TypeOf(""),
 	}
 )
 
@@ -116,6 +127,17 @@ func CreateSchema(ctx context.Context, conn SQConnection, schema string, tokeniz
 
 	// Create tables
 	return conn.Do(ctx, 0, func(txn SQTransaction) error {
+		// Add new columns to an existing file table before the indexes
+		// on those columns are created
+		if err := migrate(txn, fileTable, schema); err != nil {
+			return err
+		}
+		// Drop a search table without a content column, which is created
+		// again with the view and triggers, and then filled from the file table
+		rebuild, err := dropSearch(txn, schema)
+		if err != nil {
+			return err
+		}
 		if err := fileTable.Create(txn, schema); err != nil {
 			return err
 		}
@@ -134,21 +156,26 @@ func CreateSchema(ctx context.Context, conn SQConnection, schema string, tokeniz
 		// triggers to keep the FTS index up to date
 		// https://www.sqlite.org/fts5.html
 		if _, err := txn.Query(N(searchTriggerInsertName).WithSchema(schema).CreateTrigger(fileTableName,
-			Q("INSERT INTO ", searchTableName, " (rowid, name, parent, filename) VALUES (new.rowid, new.name, new.parent, new.filename)"),
+			Q("INSERT INTO ", searchTableName, " (rowid, name, parent, filename, content) VALUES (new.rowid, new.name, new.parent, new.filename, new.content)"),
 		).After().Insert().IfNotExists()); err != nil {
 			return err
 		}
 		if _, err := txn.Query(N(searchTriggerDeleteName).WithSchema(schema).CreateTrigger(fileTableName,
-			Q("INSERT INTO ", searchTableName, " (", searchTableName, ", rowid, name, parent, filename) VALUES ('delete', old.rowid, old.name, old.parent, old.filename)"),
+			Q("INSERT INTO ", searchTableName, " (", searchTableName, ", rowid, name, parent, filename, content) VALUES ('delete', old.rowid, old.name, old.parent, old.filename, old.content)"),
 		).After().Delete().IfNotExists()); err != nil {
 			return err
 		}
 		if _, err := txn.Query(N(searchTriggerUpdateName).WithSchema(schema).CreateTrigger(fileTableName,
-			Q("INSERT INTO ", searchTableName, " (", searchTableName, ", rowid, name, parent, filename) VALUES ('delete', old.rowid, old.name, old.parent, old.filename)"),
-			Q("INSERT INTO ", searchTableName, " (rowid, name, parent, filename) VALUES (new.rowid, new.name, new.parent, new.filename)"),
+			Q("INSERT INTO ", searchTableName, " (", searchTableName, ", rowid, name, parent, filename, content) VALUES ('delete', old.rowid, old.name, old.parent, old.filename, old.content)"),
+			Q("INSERT INTO ", searchTableName, " (rowid, name, parent, filename, content) VALUES (new.rowid, new.name, new.parent, new.filename, new.content)"),
 		).After().Update().IfNotExists()); err != nil {
 			return err
 		}
+		if rebuild {
+			if _, err := txn.Query(Q("INSERT INTO ", N(searchTableName).WithSchema(schema), " (rowid, name, parent, filename, content) SELECT rowid, name, parent, filename, content FROM ", N(fileTableName).WithSchema(schema))); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 }
@@ -182,7 +209,7 @@ func ListIndexWithCount(ctx context.Context, conn SQConnection, schema string) (
 
 func Replace(schema string, evt *QueueEvent) (SQStatement, []interface{}) {
 	return N(fileTableName).WithSchema(schema).Insert(
-			"name", "path", "parent", "filename", "isdir", "ext", "modtime", "size",
+			"name", "path", "parent", "filename", "isdir", "ext", "modtime", "size", "hash", "content",
 		).WithConflictUpdate("name", "path"),
 		[]interface{}{
 			evt.Name,
@@ -193,6 +220,8 @@ func Replace(schema string, evt *QueueEvent) (SQStatement, []interface{}) {
 			filepath.Ext(evt.Info.Name()),
 			evt.Info.ModTime(),
 			evt.Info.Size(),
+			stringOrNil(evt.Hash),
+			evt.Content,
 		}
 }
 
@@ -203,7 +232,7 @@ func Delete(schema string, evt *QueueEvent) (SQStatement, []interface{}) {
 
 func GetFile(schema string, rowid int64) (SQStatement, []interface{}, []reflect.Type) {
 	return S(N(fileTableName).WithSchema(schema)).
-		To(N("name"), N("path"), N("parent"), N("filename"), N("isdir"), N("ext"), N("modtime"), N("size")).
+		To(N("name"), N("path"), N("parent"), N("filename"), N("isdir"), N("ext"), N("modtime"), N("size"), N("hash")).
 		Where(Q("rowid", "=", P)), []interface{}{rowid}, filesTypeCast
 }
 
@@ -226,7 +255,42 @@ func UpsertDoc(txn SQTransaction, doc *Doc) (int64, error) {
 	return n[0], nil
 }
 
-func Query(schema string, snippet bool) SQSelect {
+// QueryFacets returns a statement which counts the files matching a search query,
+// grouped by a column of the file table (ie, "ext" or "parent")
+func QueryFacets(schema, column string) SQStatement {
+	return Q("SELECT ", N(column).WithSchema(fileTableName), ",COUNT(*) AS count FROM ",
+		N(searchTableName).WithSchema(schema), " LEFT JOIN ", N(fileTableName).WithSchema(schema),
+		" ON ", N(searchTableName), ".rowid=", N(fileTableName), ".rowid",
+		" WHERE ", N(searchTableName).Match(P), " GROUP BY ", N(column).WithSchema(fileTableName))
+}
+
+// Facets returns the count of files matching a search query, grouped by a
+// column of the file table
+func Facets(txn SQTransaction, schema, column, query string) (map[string]int64, error) {
+	r, err := txn.Query(QueryFacets(schema, column), query)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	results := make(map[string]int64)
+	for {
+		row := r.Next()
+		if row == nil {
+			break
+		}
+		if len(row) == 2 {
+			key, _ := row[0].(string)
+			count, _ := row[1].(int64)
+			results[key] = count
+		}
+	}
+
+	// Return success
+	return results, nil
+}
+
+// Query returns a statement which searches the index. When dedupe is true,
+// files with the same content hash are collapsed into the first matching file
+func Query(schema string, snippet, dedupe bool) SQSelect {
 	// Set the query join
 	queryJoin := J(
 		N(searchTableName).WithSchema(schema),
@@ -237,6 +301,17 @@ func Query(schema string, snippet bool) SQSelect {
 	if snippet {
 		snippetExpr = Q("SNIPPET(", searchTableName, ",-1, '<em>', '</em>', '...', 64) AS snippet")
 	}
+	// Set the where clause, which excludes matching files with the same
+	// content hash and a lower rowid when deduplicating. The query parameter
+	// is referenced twice, as ?1
+	whereExpr := []interface{}{N(searchTableName).Match(P)}
+	if dedupe {
+		whereExpr = append(whereExpr, Q("(", N(fileTableName), ".hash IS NULL OR NOT EXISTS (",
+			"SELECT 1 FROM ", N(searchTableName).WithSchema(schema),
+			" INNER JOIN ", N(fileTableName).WithSchema(schema), " AS dup ON ", N(searchTableName), ".rowid=dup.rowid",
+			" WHERE dup.hash=", N(fileTableName), ".hash AND dup.rowid<", N(fileTableName), ".rowid",
+			" AND ", N(searchTableName), " MATCH ?1))"))
+	}
 	// Return the select
 	return S(queryJoin).To(
 		N("rowid").WithSchema(searchTableName),
@@ -250,5 +325,49 @@ func Query(schema string, snippet bool) SQSelect {
 		N("ext").WithSchema(fileTableName),
 		N("modtime").WithSchema(fileTableName),
 		N("size").WithSchema(fileTableName),
-	).Where(Q(searchTableName, " MATCH ", P)).Order(N("rank"))
+		N("hash").WithSchema(fileTableName),
+	).Where(whereExpr...).Order(N("rank"))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// migrate adds any new columns of a class to an existing table
+func migrate(txn SQTransaction, class SQClass, schema string) error {
+	m, ok := txn.(migrator)
+	if !ok || !stringSliceContains(txn.Tables(schema), class.Name()) {
+		return nil
+	}
+	st, err := m.Migrate(class, schema)
+	for _, st := range st {
+		if _, err := txn.Query(st); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// dropSearch drops the search table, view and triggers when the search table
+// does not have a content column, and returns true if they were dropped
+func dropSearch(txn SQTransaction, schema string) (bool, error) {
+	if !stringSliceContains(txn.Tables(schema), searchTableName) {
+		return false, nil
+	}
+	for _, col := range txn.ColumnsForTable(schema, searchTableName) {
+		if col.Name() == "content" {
+			return false, nil
+		}
+	}
+	for _, st := range []SQStatement{
+		N(searchTriggerInsertName).WithSchema(schema).DropTrigger().IfExists(),
+		N(searchTriggerDeleteName).WithSchema(schema).DropTrigger().IfExists(),
+		N(searchTriggerUpdateName).WithSchema(schema).DropTrigger().IfExists(),
+		N(searchTableName).WithSchema(schema).DropTable().IfExists(),
+		N(viewTableName).WithSchema(schema).DropView().IfExists(),
+	} {
+		if _, err := txn.Query(st); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
 }
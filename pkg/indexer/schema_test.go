@@ -0,0 +1,267 @@
+package indexer_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	// Packages
+	sqlite3 "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+
+	// Namespace imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/indexer"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+)
+
+type fileinfo struct {
+	name string
+}
+
+func (f fileinfo) Name() string       { return f.name }
+func (f fileinfo) Size() int64        { return 100 }
+func (f fileinfo) Mode() fs.FileMode  { return 0644 }
+func (f fileinfo) ModTime() time.Time { return time.Now() }
+func (f fileinfo) IsDir() bool        { return false }
+func (f fileinfo) Sys() interface{}   { return nil }
+
+func Test_Schema_001(t *testing.T) {
+	pool, err := sqlite3.NewPool(":memory:", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Create schema and files
+	if err := CreateSchema(context.Background(), conn, "main", ""); err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{"a/report.txt", "a/report.md", "b/report.txt", "b/notes.txt", "b/report.go"}
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		for _, path := range paths {
+			evt := &QueueEvent{EventType: EventAdd, Name: "test", Path: path, Info: fileinfo{path[2:]}}
+			q, args := Replace("main", evt)
+			if _, err := txn.Query(q, args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Count facets for the query
+	var ext, parent map[string]int64
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		var err error
+		if ext, err = Facets(txn, "main", "ext", "report"); err != nil {
+			return err
+		}
+		if parent, err = Facets(txn, "main", "parent", "report"); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ext) != 3 || ext[".txt"] != 2 || ext[".md"] != 1 || ext[".go"] != 1 {
+		t.Error("Unexpected ext facets", ext)
+	}
+	if len(parent) != 2 || parent["a"] != 2 || parent["b"] != 2 {
+		t.Error("Unexpected parent facets", parent)
+	}
+}
+
+func Test_Schema_002(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "indexer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	files := map[string]string{"report1.txt": "hello", "report2.txt": "hello", "report3.txt": "world"}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(tmpdir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pool, err := sqlite3.NewPool(":memory:", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	conn := pool.Get()
+	defer pool.Put(conn)
+	if err := CreateSchema(context.Background(), conn, "main", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Walk the files, draining the queue until reindexing is completed
+	queue := NewQueue()
+	indexer, err := NewIndexer("test", tmpdir, queue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go indexer.Run(ctx, nil)
+	if err := indexer.Walk(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	var evts []*QueueEvent
+	for ctx.Err() == nil {
+		evt := queue.Next()
+		if evt == nil {
+			time.Sleep(time.Millisecond)
+		} else if evt.EventType == EventAdd {
+			evts = append(evts, evt)
+		} else if evt.EventType == EventReindexCompleted {
+			break
+		}
+	}
+
+	// Index the files
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		for _, evt := range evts {
+			if evt.Hash == "" {
+				t.Error("Expected hash for", evt.Path)
+			}
+			q, args := Replace("main", evt)
+			if _, err := txn.Query(q, args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Query with and without deduplication
+	for _, test := range []struct {
+		dedupe   bool
+		expected int
+	}{{false, 3}, {true, 2}} {
+		hashes := make(map[string]int)
+		if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+			r, err := txn.Query(Query("main", false, test.dedupe), "report*")
+			if err != nil {
+				return err
+			}
+			for row := r.Next(); row != nil; row = r.Next() {
+				hash, _ := row[11].(string)
+				hashes[hash]++
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for _, count := range hashes {
+			n += count
+			if test.dedupe && count != 1 {
+				t.Error("Expected duplicate hashes to be collapsed", hashes)
+			}
+		}
+		if n != test.expected || len(hashes) != 2 {
+			t.Errorf("dedupe=%v: Expected %d results, got %v", test.dedupe, test.expected, hashes)
+		}
+	}
+}
+
+func Test_Schema_003(t *testing.T) {
+	pool, err := sqlite3.NewPool(":memory:", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Create a file table from before the hash column was added
+	if err := conn.Exec(Q("CREATE TABLE main.file (name TEXT NOT NULL,path TEXT NOT NULL,parent TEXT,filename TEXT NOT NULL,isdir INTEGER NOT NULL,ext TEXT,modtime TIMESTAMP,size INTEGER,PRIMARY KEY (name,path))"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO main.file (name,path,filename,isdir) VALUES ('test','a/report.txt','report.txt',0)"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The hash column is added to the existing table
+	if err := CreateSchema(context.Background(), conn, "main", ""); err != nil {
+		t.Fatal(err)
+	}
+	var hash bool
+	for _, col := range conn.ColumnsForTable("main", "file") {
+		hash = hash || col.Name() == "hash"
+	}
+	if !hash {
+		t.Error("Expected hash column to be added")
+	}
+	if n := conn.Count("main", "file"); n != 1 {
+		t.Error("Expected existing row to be kept, got", n)
+	}
+
+	// Creating the schema again makes no changes
+	if err := CreateSchema(context.Background(), conn, "main", ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Schema_004(t *testing.T) {
+	pool, err := sqlite3.NewPool(":memory:", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Replace the search table with one from before the content column was added
+	if err := CreateSchema(context.Background(), conn, "main", ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range []SQStatement{
+		N("search_insert").WithSchema("main").DropTrigger(),
+		N("search_delete").WithSchema("main").DropTrigger(),
+		N("search_update").WithSchema("main").DropTrigger(),
+		N("search").WithSchema("main").DropTable(),
+		Q("CREATE VIRTUAL TABLE main.search USING fts5(name,parent,filename,title,description,shortform,content='view')"),
+	} {
+		if err := conn.Exec(st, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	evt := &QueueEvent{EventAdd, "test", "a/report.txt", fileinfo{"report.txt"}, "", "fakeword"}
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		q, args := Replace("main", evt)
+		_, err := txn.Query(q, args...)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The search table is created again and the content rebuilt
+	if err := CreateSchema(context.Background(), conn, "main", ""); err != nil {
+		t.Fatal(err)
+	}
+	var found int
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		r, err := txn.Query(Query("main", false, false), "fakeword")
+		if err != nil {
+			return err
+		}
+		for row := r.Next(); row != nil; row = r.Next() {
+			found++
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if found != 1 {
+		t.Error("Expected one search result, got", found)
+	}
+}
@@ -1,6 +1,9 @@
 package indexer
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"os"
 	"path/filepath"
 )
@@ -40,3 +43,26 @@ func uintMin(a, b uint) uint {
 	}
 	return b
 }
+
+// fileHash returns the hex-encoded SHA-256 hash of the contents of a file,
+// or an empty string if the file cannot be read
+func fileHash(path string) string {
+	r, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer r.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// stringOrNil returns nil for an empty string, so it is stored as NULL
+func stringOrNil(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
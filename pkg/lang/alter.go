@@ -5,6 +5,9 @@ import (
 	"strings"
 
 	sqlite "github.com/mutablelogic/go-sqlite"
+
+	// Import namespaces
+	. "github.com/mutablelogic/go-sqlite/pkg/quote"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -14,6 +17,8 @@ type altertable struct {
 	source
 	token string
 	col   sqlite.SQColumn
+	from  string
+	to    string
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -21,7 +26,7 @@ type altertable struct {
 
 // Create a new table with name and defined columns
 func (this *source) AlterTable() sqlite.SQAlter {
-	return &altertable{source{this.name, this.schema, "", false}, "", nil}
+	return &altertable{source{this.name, this.schema, "", false, ""}, "", nil, "", ""}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -45,6 +50,26 @@ func (this *altertable) DropColumn(col sqlite.SQColumn) sqlite.SQStatement {
 	return this
 }
 
+// RenameTo renames the table
+func (this *altertable) RenameTo(name string) sqlite.SQStatement {
+	if name == "" {
+		return nil
+	}
+	this.to = name
+	this.token = "RENAME"
+	return this
+}
+
+// RenameColumn renames a column in the table
+func (this *altertable) RenameColumn(from, to string) sqlite.SQStatement {
+	if from == "" || to == "" {
+		return nil
+	}
+	this.from, this.to = from, to
+	this.token = "RENAME COLUMN"
+	return this
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -63,6 +88,10 @@ func (this *altertable) Query() string {
 		}
 	case "DROP":
 		tokens = append(tokens, "DROP COLUMN", this.col.Name())
+	case "RENAME":
+		tokens = append(tokens, "RENAME TO", QuoteIdentifier(this.to))
+	case "RENAME COLUMN":
+		tokens = append(tokens, "RENAME COLUMN", QuoteIdentifier(this.from), "TO", QuoteIdentifier(this.to))
 	}
 
 	// Return the query
@@ -19,6 +19,10 @@ func Test_Alter_000(t *testing.T) {
 		{N("foo").WithSchema("main").AlterTable().AddColumn(C("a")), `ALTER TABLE main.foo ADD COLUMN a TEXT`},
 		{N("foo").WithSchema("main").AlterTable().AddColumn(C("a").NotNull()), `ALTER TABLE main.foo ADD COLUMN a TEXT NOT NULL`},
 		{N("foo").WithSchema("main").AlterTable().AddColumn(C("a").WithPrimary()), `ALTER TABLE main.foo ADD COLUMN a TEXT NOT NULL PRIMARY KEY`},
+		{N("foo").AlterTable().RenameTo("bar"), `ALTER TABLE foo RENAME TO bar`},
+		{N("foo").WithSchema("main").AlterTable().RenameTo("select"), `ALTER TABLE main.foo RENAME TO "select"`},
+		{N("foo").AlterTable().RenameColumn("a", "b"), `ALTER TABLE foo RENAME COLUMN a TO b`},
+		{N("foo").AlterTable().RenameColumn("a b", "order"), `ALTER TABLE foo RENAME COLUMN "a b" TO "order"`},
 	}
 
 	for _, test := range tests {
@@ -29,3 +33,12 @@ func Test_Alter_000(t *testing.T) {
 		}
 	}
 }
+
+func Test_Alter_001(t *testing.T) {
+	if st := N("foo").AlterTable().RenameTo(""); st != nil {
+		t.Error("Expected nil, got", st)
+	}
+	if st := N("foo").AlterTable().RenameColumn("a", ""); st != nil {
+		t.Error("Expected nil, got", st)
+	}
+}
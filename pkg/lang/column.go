@@ -2,6 +2,7 @@ package lang
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	// Import namespaces
@@ -28,12 +29,36 @@ const (
 	defaultColumnDecltype = "TEXT"
 )
 
+var (
+	// Keywords which can be used as default values without parentheses
+	defaultKeywords = map[string]bool{
+		"CURRENT_TIMESTAMP": true,
+		"CURRENT_DATE":      true,
+		"CURRENT_TIME":      true,
+		"NULL":              true,
+		"TRUE":              true,
+		"FALSE":             true,
+	}
+)
+
 ///////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
 // C defines a column name
 func C(name string) SQColumn {
-	return &column{source{name, "", "", false}, defaultColumnDecltype, false, false, false, nil}
+	return &column{source{name, "", "", false, ""}, defaultColumnDecltype, false, false, false, nil}
+}
+
+// DefaultExpr returns a default expression for a column from text, such as a
+// struct tag. Keywords (ie, CURRENT_TIMESTAMP), numbers, quoted literals and
+// parenthesized expressions are returned unchanged, and any other text
+// is returned as a quoted literal value
+func DefaultExpr(v string) string {
+	v = strings.TrimSpace(v)
+	if isDefaultLiteral(v) || (strings.HasPrefix(v, "(") && strings.HasSuffix(v, ")")) {
+		return v
+	}
+	return Quote(v)
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -66,7 +91,7 @@ func (this *column) WithType(v string) SQColumn {
 }
 
 func (this *column) WithAlias(v string) SQSource {
-	return &source{this.name, "", v, false}
+	return &source{this.name, "", v, false, ""}
 }
 
 func (this *column) NotNull() SQColumn {
@@ -85,8 +110,15 @@ func (this *column) WithDefault(v interface{}) SQColumn {
 	return &column{this.source, this.decltype, true, true, this.autoincrement, V(v)}
 }
 
+// WithDefaultExpr sets the default for the column to an SQL expression, which
+// is never quoted as a literal value. Expressions other than literal values and
+// keywords (ie, CURRENT_TIMESTAMP) are wrapped in parentheses
+func (this *column) WithDefaultExpr(expr string) SQColumn {
+	return &column{this.source, this.decltype, this.notnull, this.primary, this.autoincrement, Q(expr)}
+}
+
 func (this *column) WithDefaultNow() SQColumn {
-	return &column{this.source, this.decltype, true, true, this.autoincrement, V("CURRENT_TIMESTAMP")}
+	return &column{this.source, this.decltype, true, true, this.autoincrement, Q("CURRENT_TIMESTAMP")}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -103,7 +135,68 @@ func (this *column) String() string {
 		tokens = append(tokens, "NOT NULL")
 	}
 	if this.def != nil {
-		tokens = append(tokens, "DEFAULT", fmt.Sprint(this.def))
+		tokens = append(tokens, "DEFAULT", defaultValue(fmt.Sprint(this.def)))
 	}
 	return strings.Join(tokens, " ")
 }
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// defaultValue returns a default value, wrapped in parentheses unless it
+// is a literal value, keyword or is already wrapped in parentheses
+func defaultValue(v string) string {
+	v = strings.TrimSpace(v)
+	switch {
+	case v == "":
+		return "NULL"
+	case isDefaultLiteral(v), isWrapped(v):
+		return v
+	}
+	return "(" + v + ")"
+}
+
+// isDefaultLiteral returns true if v is a keyword, number, string or blob
+// literal which can be used as a default value without parentheses
+func isDefaultLiteral(v string) bool {
+	switch {
+	case defaultKeywords[strings.ToUpper(v)], isQuotedLiteral(v):
+		return true
+	case len(v) > 1 && (v[0] == 'X' || v[0] == 'x') && isQuotedLiteral(v[1:]):
+		return true
+	}
+	_, err := strconv.ParseFloat(v, 64)
+	return err == nil
+}
+
+// isQuotedLiteral returns true if v is a single-quoted string literal
+func isQuotedLiteral(v string) bool {
+	if len(v) < 2 || v[0] != '\'' || v[len(v)-1] != '\'' {
+		return false
+	}
+	return !strings.Contains(strings.ReplaceAll(v[1:len(v)-1], "''", ""), "'")
+}
+
+// isWrapped returns true if v is wrapped in a single pair of parentheses
+func isWrapped(v string) bool {
+	if len(v) < 2 || v[0] != '(' || v[len(v)-1] != ')' {
+		return false
+	}
+	depth, quoted := 0, false
+	for i, ch := range v {
+		switch {
+		case ch == '\'':
+			quoted = !quoted
+		case quoted:
+			continue
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth == 0 && i != len(v)-1 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
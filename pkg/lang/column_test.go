@@ -18,6 +18,20 @@ func Test_Column_000(t *testing.T) {
 		{C("a").NotNull(), `a TEXT NOT NULL`},
 		{C("a").WithType("VARCHAR"), `a VARCHAR`},
 		{C("a").WithAlias("b"), `a AS b`},
+		{C("a").WithType("TIMESTAMP").WithDefaultNow(), `a TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP`},
+		{C("a").WithDefault("b"), `a TEXT NOT NULL DEFAULT 'b'`},
+		{C("a").WithDefaultExpr("b"), `a TEXT DEFAULT (b)`},
+		{C("a").WithDefault("1+2"), `a TEXT NOT NULL DEFAULT '1+2'`},
+		{C("a").WithDefaultExpr("1+2"), `a TEXT DEFAULT (1+2)`},
+		{C("a").NotNull().WithDefaultExpr("(1+2)"), `a TEXT NOT NULL DEFAULT (1+2)`},
+		{C("a").WithDefaultExpr("(1)+(2)"), `a TEXT DEFAULT ((1)+(2))`},
+		{C("a").WithDefaultExpr("'a'||'b'"), `a TEXT DEFAULT ('a'||'b')`},
+		{C("a").WithDefaultExpr("lower(hex(randomblob(16)))"), `a TEXT DEFAULT (lower(hex(randomblob(16))))`},
+		{C("a").WithDefaultExpr("current_date"), `a TEXT DEFAULT current_date`},
+		{C("a").WithDefaultExpr("'b'"), `a TEXT DEFAULT 'b'`},
+		{C("a").WithDefaultExpr("-10.5"), `a TEXT DEFAULT -10.5`},
+		{C("a").WithDefaultExpr("X'00FF'"), `a TEXT DEFAULT X'00FF'`},
+		{C("a").WithDefaultExpr("NULL"), `a TEXT DEFAULT NULL`},
 	}
 
 	for _, test := range tests {
@@ -26,3 +40,43 @@ func Test_Column_000(t *testing.T) {
 		}
 	}
 }
+
+func Test_Column_001(t *testing.T) {
+	// WithDefaultNow sets the column as a NOT NULL primary key, which defaults
+	// to the current time rather than the literal 'CURRENT_TIMESTAMP'
+	col := C("a").WithType("TIMESTAMP").WithDefaultNow()
+	if col.Nullable() {
+		t.Error("Expected column to be NOT NULL")
+	}
+	if v := col.Primary(); v != "PRIMARY KEY" {
+		t.Errorf("Unexpected primary %q", v)
+	}
+	if v := N("test").CreateTable(col).Query(); v != `CREATE TABLE test (a TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP PRIMARY KEY)` {
+		t.Errorf("Unexpected statement %q", v)
+	}
+}
+
+func Test_Column_002(t *testing.T) {
+	// DefaultExpr decides whether text is an expression or a literal value
+	tests := []struct {
+		In     string
+		String string
+	}{
+		{"CURRENT_TIMESTAMP", `a TEXT DEFAULT CURRENT_TIMESTAMP`},
+		{"current_date", `a TEXT DEFAULT current_date`},
+		{"NULL", `a TEXT DEFAULT NULL`},
+		{" 10 ", `a TEXT DEFAULT 10`},
+		{"-10.5", `a TEXT DEFAULT -10.5`},
+		{"'a'", `a TEXT DEFAULT 'a'`},
+		{"X'00FF'", `a TEXT DEFAULT X'00FF'`},
+		{"(1+2)", `a TEXT DEFAULT (1+2)`},
+		{"(1)+(2)", `a TEXT DEFAULT ((1)+(2))`},
+		{"CURRENT_TIMESTAMP is not a keyword", `a TEXT DEFAULT 'CURRENT_TIMESTAMP is not a keyword'`},
+		{"it's", `a TEXT DEFAULT 'it''s'`},
+	}
+	for _, test := range tests {
+		if v := C("a").WithDefaultExpr(DefaultExpr(test.In)).String(); v != test.String {
+			t.Errorf("DefaultExpr(%q) = %v, wanted %v", test.In, v, test.String)
+		}
+	}
+}
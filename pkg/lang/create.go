@@ -28,7 +28,7 @@ type createtable struct {
 
 // Create a new table with name and defined columns
 func (this *source) CreateTable(columns ...SQColumn) SQTable {
-	return &createtable{source{this.name, this.schema, "", false}, false, false, false, nil, nil, nil, columns}
+	return &createtable{source{this.name, this.schema, "", false, ""}, false, false, false, nil, nil, nil, columns}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
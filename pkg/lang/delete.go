@@ -24,7 +24,7 @@ func (this *source) Delete(expr ...interface{}) SQStatement {
 	if len(expr) == 0 {
 		return nil
 	} else {
-		return &delete{&source{this.name, this.schema, "", false}, expr}
+		return &delete{&source{this.name, this.schema, "", false, ""}, expr}
 	}
 }
 
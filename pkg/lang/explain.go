@@ -0,0 +1,42 @@
+package lang
+
+import (
+	sqlite "github.com/mutablelogic/go-sqlite"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type explain struct {
+	sqlite.SQStatement
+	plan bool
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Explain returns a statement which explains how a statement is executed
+func Explain(st sqlite.SQStatement) sqlite.SQStatement {
+	return &explain{st, false}
+}
+
+// ExplainQueryPlan returns a statement which describes the query plan
+// for a statement
+func ExplainQueryPlan(st sqlite.SQStatement) sqlite.SQStatement {
+	return &explain{st, true}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *explain) Query() string {
+	if this.plan {
+		return "EXPLAIN QUERY PLAN " + this.SQStatement.Query()
+	} else {
+		return "EXPLAIN " + this.SQStatement.Query()
+	}
+}
+
+func (this *explain) String() string {
+	return this.Query()
+}
@@ -0,0 +1,28 @@
+package lang_test
+
+import (
+	"testing"
+
+	// Namespace imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+)
+
+func Test_Explain_000(t *testing.T) {
+	tests := []struct {
+		In    SQStatement
+		Query string
+	}{
+		{Explain(Q("SELECT 1")), `EXPLAIN SELECT 1`},
+		{ExplainQueryPlan(Q("SELECT 1")), `EXPLAIN QUERY PLAN SELECT 1`},
+		{Explain(S(N("foo")).Where(Q("a=", P))), `EXPLAIN SELECT * FROM foo WHERE a=?`},
+		{ExplainQueryPlan(S(N("foo")).Where(Q("a=", P))), `EXPLAIN QUERY PLAN SELECT * FROM foo WHERE a=?`},
+		{ExplainQueryPlan(N("foo").Insert("a", "b")), `EXPLAIN QUERY PLAN INSERT INTO foo (a,b) VALUES (?,?)`},
+	}
+
+	for _, test := range tests {
+		if v := test.In.Query(); v != test.Query {
+			t.Errorf("got %v, wanted %v", v, test.Query)
+		}
+	}
+}
@@ -53,6 +53,19 @@ func V(v interface{}) SQExpr {
 	panic(fmt.Sprintf("V unsupported type %T", v))
 }
 
+// Exists returns a comparison which is true when the subquery returns
+// at least one row. Any bound parameters in the subquery are bound in order
+// with the parameters of the outer query
+func Exists(sub SQSelect) SQComparison {
+	return &e{nil, Q("(", sub, ")"), "EXISTS"}
+}
+
+// NotExists returns a comparison which is true when the subquery returns
+// no rows
+func NotExists(sub SQSelect) SQComparison {
+	return &e{nil, Q("(", sub, ")"), "NOT EXISTS"}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // METHODS
 
@@ -77,6 +90,28 @@ func (this *e) Or(v interface{}) SQExpr {
 	panic(fmt.Sprintf("V unsupported type %T", v))
 }
 
+// Not returns the negated form of a comparison, so that "a IS NULL" becomes
+// "a IS NOT NULL" and "a GLOB ?" becomes "a NOT GLOB ?". Any other
+// expression is negated as "NOT (expr)"
+func (this *e) Not() SQComparison {
+	switch this.op {
+	case "IS":
+		return &e{this.v, this.r, "IS NOT"}
+	case "IS NOT":
+		return &e{this.v, this.r, "IS"}
+	case "EXISTS":
+		return &e{this.v, this.r, "NOT EXISTS"}
+	case "NOT EXISTS":
+		return &e{this.v, this.r, "EXISTS"}
+	case "LIKE", "GLOB", "REGEXP", "MATCH":
+		return &e{this.v, this.r, "NOT " + this.op}
+	case "NOT LIKE", "NOT GLOB", "NOT REGEXP", "NOT MATCH":
+		return &e{this.v, this.r, strings.TrimPrefix(this.op, "NOT ")}
+	default:
+		return &e{Q("NOT (", this, ")"), nil, ""}
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -86,6 +121,8 @@ func (this *e) String() string {
 	}
 	if this.op == "" {
 		return lhs(this.v)
+	} else if this.op == "EXISTS" || this.op == "NOT EXISTS" {
+		return rhs(this.op, this.r)
 	} else {
 		return lhs(this.v) + " " + rhs(this.op, this.r)
 	}
@@ -33,3 +33,23 @@ func Test_Expr_000(t *testing.T) {
 		}
 	}
 }
+
+func Test_Expr_001(t *testing.T) {
+	sub := S(N("child")).To(V(1)).Where(Q("child.parent=parent.id"), Q("child.name=", P))
+	tests := []struct {
+		In     SQExpr
+		String string
+	}{
+		{Exists(sub), `EXISTS (SELECT 1 FROM child WHERE child.parent=parent.id AND child.name=?)`},
+		{NotExists(sub), `NOT EXISTS (SELECT 1 FROM child WHERE child.parent=parent.id AND child.name=?)`},
+		{Exists(sub).Not(), `NOT EXISTS (SELECT 1 FROM child WHERE child.parent=parent.id AND child.name=?)`},
+		{NotExists(sub).Not(), `EXISTS (SELECT 1 FROM child WHERE child.parent=parent.id AND child.name=?)`},
+		{S(N("parent")).Where(Q("parent.id>", P), Exists(sub)), `SELECT * FROM parent WHERE parent.id>? AND EXISTS (SELECT 1 FROM child WHERE child.parent=parent.id AND child.name=?)`},
+	}
+
+	for _, test := range tests {
+		if v := fmt.Sprint(test.In); v != test.String {
+			t.Errorf("Unexpected return from String(): %q, wanted %q", v, test.String)
+		}
+	}
+}
@@ -23,7 +23,7 @@ type foreignkey struct {
 
 // Create a foreign key
 func (this *source) ForeignKey(columns ...string) SQForeignKey {
-	return &foreignkey{&source{this.name, "", "", false}, columns, ""}
+	return &foreignkey{&source{this.name, "", "", false, ""}, columns, ""}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
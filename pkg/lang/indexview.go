@@ -16,7 +16,7 @@ type createindex struct {
 	name        string
 	unique      bool
 	ifnotexists bool
-	columns     []string
+	columns     []SQSource
 	auto        bool
 }
 
@@ -39,19 +39,20 @@ type createview struct {
 ///////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
-// Create a new index with name and defined columns
-func (this *source) CreateIndex(name string, columns ...string) SQIndexView {
-	return &createindex{source{this.name, this.schema, "", false}, name, false, false, columns, false}
+// Create a new index on a table name with defined columns. Columns can include
+// a collating sequence and sort order (ie, N("a").WithCollate("NOCASE").WithDesc())
+func (this *source) CreateIndex(name string, columns ...SQSource) SQIndexView {
+	return &createindex{source{this.name, this.schema, "", false, ""}, name, false, false, columns, false}
 }
 
 // Create a virtual table with module name name and arguments
 func (this *source) CreateVirtualTable(module string, args ...string) SQIndexView {
-	return &createvirtual{source{this.name, this.schema, "", false}, module, false, args, nil}
+	return &createvirtual{source{this.name, this.schema, "", false, ""}, module, false, args, nil}
 }
 
 // Create a view with name and defined columns
 func (this *source) CreateView(st SQSelect, columns ...string) SQIndexView {
-	return &createview{source{this.name, this.schema, "", false}, false, false, columns, st}
+	return &createview{source{this.name, this.schema, "", false, ""}, false, false, columns, st}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -83,7 +84,7 @@ func (this *createview) Table() string {
 func (this *createindex) Columns() []string {
 	result := make([]string, len(this.columns))
 	for i := range this.columns {
-		result[i] = this.columns[i]
+		result[i] = this.columns[i].Name()
 	}
 	return result
 }
@@ -142,7 +143,7 @@ func (this *createindex) WithTemporary() SQIndexView {
 	return nil
 }
 func (this *createvirtual) WithTemporary() SQIndexView {
-	return &createvirtual{source{this.name, "temp", "", false}, this.module, this.ifnotexists, this.args, this.opts}
+	return &createvirtual{source{this.name, "temp", "", false, ""}, this.module, this.ifnotexists, this.args, this.opts}
 }
 func (this *createview) WithTemporary() SQIndexView {
 	view := *this
@@ -167,7 +168,7 @@ func (this *createindex) Options(opts ...string) SQIndexView {
 	return nil
 }
 func (this *createvirtual) Options(opts ...string) SQIndexView {
-	return &createvirtual{source{this.name, this.schema, "", false}, this.module, this.ifnotexists, this.args, opts}
+	return &createvirtual{source{this.name, this.schema, "", false, ""}, this.module, this.ifnotexists, this.args, opts}
 }
 func (this *createview) Options(opts ...string) SQIndexView {
 	return nil
@@ -203,7 +204,11 @@ func (this *createindex) Query() string {
 	if this.ifnotexists {
 		tokens = append(tokens, "IF NOT EXISTS")
 	}
-	tokens = append(tokens, this.source.String(), "ON", QuoteIdentifier(this.name), "("+QuoteIdentifiers(this.columns...)+")")
+	columns := make([]string, len(this.columns))
+	for i, column := range this.columns {
+		columns[i] = column.String()
+	}
+	tokens = append(tokens, this.source.String(), "ON", QuoteIdentifier(this.name), "("+strings.Join(columns, ",")+")")
 
 	// Return the query
 	return strings.Join(tokens, " ")
@@ -16,8 +16,10 @@ func Test_CreateIndexView_000(t *testing.T) {
 		{N("foo").CreateIndex("foo"), `CREATE INDEX foo ON foo ()`},
 		{N("foo").CreateIndex("foo").IfNotExists(), `CREATE INDEX IF NOT EXISTS foo ON foo ()`},
 		{N("foo").CreateIndex("bar").WithUnique(), `CREATE UNIQUE INDEX foo ON bar ()`},
-		{N("foo").CreateIndex("bar", "a", "b").WithUnique(), `CREATE UNIQUE INDEX foo ON bar (a,b)`},
-		{N("foo").WithSchema("main").CreateIndex("bar", "a", "b").WithUnique(), `CREATE UNIQUE INDEX main.foo ON bar (a,b)`},
+		{N("foo").CreateIndex("bar", N("a"), N("b")).WithUnique(), `CREATE UNIQUE INDEX foo ON bar (a,b)`},
+		{N("foo").WithSchema("main").CreateIndex("bar", N("a"), N("b")).WithUnique(), `CREATE UNIQUE INDEX main.foo ON bar (a,b)`},
+		{N("foo").CreateIndex("bar", N("a").WithCollate("NOCASE"), N("b").WithDesc()), `CREATE INDEX foo ON bar (a COLLATE NOCASE,b DESC)`},
+		{N("foo").CreateIndex("bar", N("a").WithCollate("NOCASE").WithDesc()), `CREATE INDEX foo ON bar (a COLLATE NOCASE DESC)`},
 	}
 
 	for _, test := range tests {
@@ -14,9 +14,12 @@ import (
 type insert struct {
 	source
 	class         string
+	resolution    SQConflict
 	defaultvalues bool
 	columns       []string
+	rows          uint
 	conflicts     []conflict
+	comment       string
 }
 
 type conflict struct {
@@ -29,31 +32,51 @@ type conflict struct {
 
 // Insert values into a table with a name and defined column names
 func (this *source) Insert(columns ...string) SQInsert {
-	return &insert{source{this.name, this.schema, "", false}, "INSERT", false, columns, nil}
+	return &insert{source{this.name, this.schema, "", false, ""}, "INSERT", SQLITE_CONFLICT_NONE, false, columns, 1, nil, ""}
 }
 
 // Replace values into a table with a name and defined column names
 func (this *source) Replace(columns ...string) SQInsert {
-	return &insert{source{this.name, this.schema, "", false}, "REPLACE", false, columns, nil}
+	return &insert{source{this.name, this.schema, "", false, ""}, "REPLACE", SQLITE_CONFLICT_NONE, false, columns, 1, nil, ""}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // PROPERTIES
 
 func (this *insert) DefaultValues() SQInsert {
-	return &insert{this.source, this.class, true, this.columns, nil}
+	return &insert{this.source, this.class, this.resolution, true, this.columns, this.rows, nil, this.comment}
 }
 
 // WithConflictUpdate sets the conflict resolution to do nothing (that is,
 // silently fail)
 func (this *insert) WithConflictDoNothing(target ...string) SQInsert {
-	return &insert{this.source, this.class, this.defaultvalues, this.columns, append(this.conflicts, conflict{"NOTHING", target})}
+	return &insert{this.source, this.class, this.resolution, this.defaultvalues, this.columns, this.rows, append(this.conflicts, conflict{"NOTHING", target}), this.comment}
 }
 
 // WithConflictUpdate sets the conflict resolution to update the row only
 // when named columns are changed
 func (this *insert) WithConflictUpdate(target ...string) SQInsert {
-	return &insert{this.source, this.class, this.defaultvalues, this.columns, append(this.conflicts, conflict{"UPDATE SET", target})}
+	return &insert{this.source, this.class, this.resolution, this.defaultvalues, this.columns, this.rows, append(this.conflicts, conflict{"UPDATE SET", target}), this.comment}
+}
+
+// WithConflictResolution sets the conflict resolution for an insert, which
+// is rendered as INSERT OR <action>. It is ignored for a replace statement
+func (this *insert) WithConflictResolution(v SQConflict) SQInsert {
+	return &insert{this.source, this.class, v, this.defaultvalues, this.columns, this.rows, this.conflicts, this.comment}
+}
+
+// WithComment appends a comment to the statement
+func (this *insert) WithComment(v string) SQInsert {
+	return &insert{this.source, this.class, this.resolution, this.defaultvalues, this.columns, this.rows, this.conflicts, v}
+}
+
+// WithRows sets the number of rows of values to insert in a single statement,
+// which is rendered as VALUES (?,?),(?,?). A value of zero is treated as one row
+func (this *insert) WithRows(n uint) SQInsert {
+	if n == 0 {
+		n = 1
+	}
+	return &insert{this.source, this.class, this.resolution, this.defaultvalues, this.columns, n, this.conflicts, this.comment}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -64,7 +87,13 @@ func (this *insert) String() string {
 }
 
 func (this *insert) Query() string {
-	tokens := []string{this.class, "INTO"}
+	tokens := []string{this.class}
+
+	// Add conflict resolution
+	if this.class == "INSERT" && this.resolution != SQLITE_CONFLICT_NONE {
+		tokens = append(tokens, "OR", this.resolution.String())
+	}
+	tokens = append(tokens, "INTO")
 
 	// Add table name
 	tokens = append(tokens, this.source.String())
@@ -78,7 +107,8 @@ func (this *insert) Query() string {
 	if this.defaultvalues || (len(this.columns) == 0) {
 		tokens = append(tokens, "DEFAULT VALUES")
 	} else if len(this.columns) > 0 {
-		tokens = append(tokens, "VALUES", this.argsN(len(this.columns)))
+		args := this.argsN(len(this.columns))
+		tokens = append(tokens, "VALUES", strings.Repeat(args+",", int(this.rows)-1)+args)
 	} else {
 		// No columns, return empty query
 		return ""
@@ -91,6 +121,11 @@ func (this *insert) Query() string {
 		}
 	}
 
+	// Add comment
+	if v := comment(this.comment); v != "" {
+		tokens = append(tokens, v)
+	}
+
 	// Return the query
 	return strings.Join(tokens, " ")
 }
@@ -49,3 +49,47 @@ func Test_Insert_001(t *testing.T) {
 		}
 	}
 }
+
+func Test_Insert_002(t *testing.T) {
+	tests := []struct {
+		In    SQStatement
+		Query string
+	}{
+		{N("foo").Insert("a").WithConflictResolution(SQLITE_CONFLICT_NONE), `INSERT INTO foo (a) VALUES (?)`},
+		{N("foo").Insert("a").WithConflictResolution(SQLITE_CONFLICT_ROLLBACK), `INSERT OR ROLLBACK INTO foo (a) VALUES (?)`},
+		{N("foo").Insert("a").WithConflictResolution(SQLITE_CONFLICT_ABORT), `INSERT OR ABORT INTO foo (a) VALUES (?)`},
+		{N("foo").Insert("a").WithConflictResolution(SQLITE_CONFLICT_FAIL), `INSERT OR FAIL INTO foo (a) VALUES (?)`},
+		{N("foo").Insert("a").WithConflictResolution(SQLITE_CONFLICT_IGNORE), `INSERT OR IGNORE INTO foo (a) VALUES (?)`},
+		{N("foo").Insert("a").WithConflictResolution(SQLITE_CONFLICT_REPLACE), `INSERT OR REPLACE INTO foo (a) VALUES (?)`},
+		{N("foo").Insert().WithConflictResolution(SQLITE_CONFLICT_IGNORE), `INSERT OR IGNORE INTO foo DEFAULT VALUES`},
+		{N("foo").Replace("a").WithConflictResolution(SQLITE_CONFLICT_IGNORE), `REPLACE INTO foo (a) VALUES (?)`},
+	}
+
+	for _, test := range tests {
+		if v := test.In.Query(); v != test.Query {
+			t.Errorf("db.V = %v, wanted %v", v, test.Query)
+		}
+	}
+}
+
+func Test_Insert_003(t *testing.T) {
+	tests := []struct {
+		In    SQStatement
+		Query string
+	}{
+		{N("foo").Insert("a").WithRows(0), `INSERT INTO foo (a) VALUES (?)`},
+		{N("foo").Insert("a").WithRows(1), `INSERT INTO foo (a) VALUES (?)`},
+		{N("foo").Insert("a", "b").WithRows(3), `INSERT INTO foo (a,b) VALUES (?,?),(?,?),(?,?)`},
+		{N("foo").Insert().WithRows(2), `INSERT INTO foo DEFAULT VALUES`},
+		{N("foo").Insert("a", "b").WithRows(2).WithConflictUpdate("a"), `INSERT INTO foo (a,b) VALUES (?,?),(?,?) ON CONFLICT (a) DO UPDATE SET a=excluded.a,b=excluded.b WHERE a<>excluded.a OR b<>excluded.b`},
+		{N("foo").Insert("a").WithComment("request 1"), `INSERT INTO foo (a) VALUES (?) /* request 1 */`},
+		{N("foo").Insert("a").WithComment("request 1").WithConflictDoNothing(), `INSERT INTO foo (a) VALUES (?) ON CONFLICT DO NOTHING /* request 1 */`},
+		{N("foo").Insert().WithComment(" "), `INSERT INTO foo DEFAULT VALUES`},
+	}
+
+	for _, test := range tests {
+		if v := test.In.Query(); v != test.Query {
+			t.Errorf("db.V = %v, wanted %v", v, test.Query)
+		}
+	}
+}
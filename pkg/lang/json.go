@@ -0,0 +1,138 @@
+package lang
+
+import (
+	"strings"
+
+	// Import namespaces
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/quote"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// fn is a source which is rendered as a function call or operator expression,
+// such as json_extract(a,'$.b'), rather than as a quoted identifier. It can
+// be used in a select with an alias, or as the left hand side of a comparison
+type fn struct {
+	*source
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// JSONExtract returns the value at a path in a JSON column, which renders
+// as json_extract(src,'$.path')
+func JSONExtract(src SQSource, path string) SQSource {
+	return newfn("json_extract(" + lhs(src) + "," + Quote(path) + ")")
+}
+
+// JSONArray returns a JSON array of values, which renders as
+// json_array(v1,v2,...). Values can be P for bound parameters
+func JSONArray(v ...interface{}) SQSource {
+	return newfn("json_array(" + jsonArgs(v) + ")")
+}
+
+// JSONObject returns a JSON object from name and value pairs, which renders
+// as json_object('name',value,...). Panics if the number of arguments is odd
+func JSONObject(v ...interface{}) SQSource {
+	if len(v)%2 != 0 {
+		panic("JSONObject requires name and value pairs")
+	}
+	return newfn("json_object(" + jsonArgs(v) + ")")
+}
+
+// JSONGet returns the JSON representation of the value at a path in a JSON
+// column, which renders as src -> '$.path'. The operator requires
+// SQLite 3.38 or later
+func JSONGet(src SQSource, path string) SQSource {
+	return newfn(lhs(src) + " -> " + Quote(path))
+}
+
+// JSONGetValue returns the SQL value at a path in a JSON column, which renders
+// as src ->> '$.path'. The operator requires SQLite 3.38 or later
+func JSONGetValue(src SQSource, path string) SQSource {
+	return newfn(lhs(src) + " ->> " + Quote(path))
+}
+
+func newfn(expr string) *fn {
+	return &fn{&source{expr, "", "", false, ""}}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *fn) WithAlias(alias string) SQSource {
+	return &fn{&source{this.name, "", alias, this.desc, this.collate}}
+}
+
+func (this *fn) WithDesc() SQSource {
+	return &fn{&source{this.name, "", this.alias, true, this.collate}}
+}
+
+func (this *fn) WithCollate(name string) SQSource {
+	return &fn{&source{this.name, "", this.alias, this.desc, name}}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// CONVERT TO EXPR
+
+func (this *fn) Or(v interface{}) SQExpr {
+	return &e{this, v, "OR"}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMPARISONS
+
+func (this *fn) IsNull() SQComparison {
+	return &e{this, nil, "IS"}
+}
+
+func (this *fn) IsNotNull() SQComparison {
+	return &e{this, nil, "IS NOT"}
+}
+
+func (this *fn) IsDistinctFrom(v interface{}) SQComparison {
+	return &e{this, v, "IS NOT"}
+}
+
+func (this *fn) Glob(v interface{}) SQComparison {
+	return &e{this, v, "GLOB"}
+}
+
+func (this *fn) Match(v interface{}) SQComparison {
+	return &e{this, v, "MATCH"}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *fn) String() string {
+	tokens := []string{this.name}
+	if this.alias != "" {
+		tokens = append(tokens, " AS ", QuoteIdentifier(this.alias))
+	}
+	if this.collate != "" {
+		tokens = append(tokens, " COLLATE ", QuoteIdentifier(this.collate))
+	}
+	if this.desc {
+		tokens = append(tokens, " DESC")
+	}
+	return strings.Join(tokens, "")
+}
+
+func (this *fn) Query() string {
+	return this.String()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// jsonArgs returns function arguments separated by commas
+func jsonArgs(v []interface{}) string {
+	args := make([]string, len(v))
+	for i, v := range v {
+		args[i] = lhs(v)
+	}
+	return strings.Join(args, ",")
+}
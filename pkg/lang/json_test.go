@@ -0,0 +1,40 @@
+package lang_test
+
+import (
+	"testing"
+
+	// Namespace imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+)
+
+func Test_JSON_000(t *testing.T) {
+	tests := []struct {
+		In    SQStatement
+		Query string
+	}{
+		{S(N("foo")).To(JSONExtract(N("meta"), "$.k")), `SELECT json_extract(meta,'$.k') FROM foo`},
+		{S(N("foo")).To(JSONExtract(N("meta"), "$.k").WithAlias("k")), `SELECT json_extract(meta,'$.k') AS k FROM foo`},
+		{S(N("foo")).Where(Q(JSONExtract(N("meta"), "$.k"), "=", P)), `SELECT * FROM foo WHERE json_extract(meta,'$.k')=?`},
+		{S(N("foo")).Where(JSONExtract(N("meta").WithAlias("m"), "$.k").IsNull()), `SELECT * FROM foo WHERE json_extract(meta,'$.k') IS NULL`},
+		{S(N("foo")).Order(JSONExtract(N("meta"), "$.k").WithDesc()), `SELECT * FROM foo ORDER BY json_extract(meta,'$.k') DESC`},
+		{S().To(JSONArray(1, "a", P, nil)), `SELECT json_array(1,'a',?,NULL)`},
+		{S().To(JSONObject("a", 1, "b", P).WithAlias("obj")), `SELECT json_object('a',1,'b',?) AS obj`},
+		{S(N("foo")).To(JSONGet(N("meta"), "$.k"), JSONGetValue(N("meta"), "$.k").WithAlias("v")), `SELECT meta -> '$.k',meta ->> '$.k' AS v FROM foo`},
+	}
+
+	for i, test := range tests {
+		if v := test.In.Query(); v != test.Query {
+			t.Errorf("Test %d, Unexpected return from Query(): %q, wanted %q", i, v, test.Query)
+		}
+	}
+}
+
+func Test_JSON_001(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for odd number of arguments")
+		}
+	}()
+	JSONObject("a")
+}
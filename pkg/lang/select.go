@@ -17,53 +17,94 @@ type sel struct {
 	limit, offset uint
 	where         []interface{}
 	to            []SQExpr
-	order         []SQSource
+	order         []interface{}
+	distincton    []SQSource
+	comment       string
 }
 
+// or is a group of where expressions joined with OR
+type or []interface{}
+
 ///////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
 // S defines a select statement
 func S(sources ...SQExpr) SQSelect {
-	return &sel{sources, false, 0, 0, nil, nil, nil}
+	return &sel{sources, false, 0, 0, nil, nil, nil, nil, ""}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // PROPERTIES
 
 func (this *sel) WithDistinct() SQSelect {
-	return &sel{this.source, true, this.limit, this.offset, this.where, this.to, this.order}
+	return &sel{this.source, true, this.limit, this.offset, this.where, this.to, this.order, this.distincton, this.comment}
+}
+
+// DistinctOn returns one row for each distinct value of the named columns,
+// choosing the row with the lowest rowid in each group which matches the where
+// clause. The source should be a single table with a rowid. Note the row chosen
+// does not depend on the order clause.
+func (this *sel) DistinctOn(cols ...SQSource) SQSelect {
+	return &sel{this.source, this.distinct, this.limit, this.offset, this.where, this.to, this.order, cols, this.comment}
+}
+
+// WithComment appends a comment to the statement, which can be used to
+// correlate queries with application context
+func (this *sel) WithComment(v string) SQSelect {
+	return &sel{this.source, this.distinct, this.limit, this.offset, this.where, this.to, this.order, this.distincton, v}
 }
 
 func (this *sel) WithLimitOffset(limit, offset uint) SQSelect {
-	return &sel{this.source, this.distinct, limit, offset, this.where, this.to, this.order}
+	return &sel{this.source, this.distinct, limit, offset, this.where, this.to, this.order, this.distincton, this.comment}
 }
 
 func (this *sel) Where(v ...interface{}) SQSelect {
 	if len(v) == 0 {
 		// Reset where clause
-		return &sel{this.source, this.distinct, this.limit, this.offset, nil, this.to, this.order}
+		return &sel{this.source, this.distinct, this.limit, this.offset, nil, this.to, this.order, this.distincton, this.comment}
 	}
 	// Where clause with an expression
-	return &sel{this.source, this.distinct, this.limit, this.offset, append(this.where, v...), this.to, this.order}
+	return &sel{this.source, this.distinct, this.limit, this.offset, append(this.where, v...), this.to, this.order, this.distincton, this.comment}
+}
+
+// OrWhere appends a group of expressions joined with OR to the where clause,
+// which is joined with any other expressions using AND. With no arguments,
+// the where clause is reset
+func (this *sel) OrWhere(v ...interface{}) SQSelect {
+	if len(v) == 0 {
+		return this.Where()
+	}
+	where := append(make([]interface{}, 0, len(this.where)+1), this.where...)
+	return &sel{this.source, this.distinct, this.limit, this.offset, append(where, or(v)), this.to, this.order, this.distincton, this.comment}
 }
 
 func (this *sel) To(v ...SQExpr) SQSelect {
 	if len(v) == 0 {
 		// Reset to clause
-		return &sel{this.source, this.distinct, this.limit, this.offset, this.where, nil, this.order}
+		return &sel{this.source, this.distinct, this.limit, this.offset, this.where, nil, this.order, this.distincton, this.comment}
 	}
 	// To clause with an expression
-	return &sel{this.source, this.distinct, this.limit, this.offset, this.where, append(this.to, v...), this.order}
+	return &sel{this.source, this.distinct, this.limit, this.offset, this.where, append(this.to, v...), this.order, this.distincton, this.comment}
 }
 
 func (this *sel) Order(v ...SQSource) SQSelect {
+	order := make([]interface{}, len(v))
+	for i, v := range v {
+		order[i] = v
+	}
+	return this.OrderBy(order...)
+}
+
+// OrderBy appends sources, expressions or column ordinals (ie, 2) to the
+// order clause
+func (this *sel) OrderBy(v ...interface{}) SQSelect {
 	if len(v) == 0 {
 		// Reset order clause
-		return &sel{this.source, this.distinct, this.limit, this.offset, this.where, this.to, nil}
+		return &sel{this.source, this.distinct, this.limit, this.offset, this.where, this.to, nil, this.distincton, this.comment}
 	}
 	// Append order clause
-	return &sel{this.source, this.distinct, this.limit, this.offset, this.where, this.to, append(this.order, v...)}
+	order := append(append(make([]interface{}, 0, len(this.order)+len(v)), this.order...), v...)
+	return &sel{this.source, this.distinct, this.limit, this.offset, this.where, this.to, order, this.distincton, this.comment}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -112,14 +153,30 @@ func (this *sel) Query() string {
 		tokens = append(tokens, token)
 	}
 
-	// Where clause
-	if len(this.where) > 0 {
+	// Where clause. For distinct on, the where clause is applied within a
+	// subquery which chooses the first row in each group, so that any parameters
+	// are only bound once
+	if len(this.distincton) > 0 && len(this.source) > 0 {
+		group := &sel{this.source, false, 0, 0, this.where, []SQExpr{Q("MIN(rowid)")}, nil, nil, ""}
+		token := "rowid IN (" + group.Query() + " GROUP BY "
+		for i, expr := range this.distincton {
+			if i > 0 {
+				token += ","
+			}
+			token += fmt.Sprint(expr)
+		}
+		tokens = append(tokens, "WHERE", token+")")
+	} else if len(this.where) > 0 {
 		tokens = append(tokens, "WHERE")
 		for i, expr := range this.where {
 			if i > 0 {
 				tokens = append(tokens, "AND")
 			}
-			tokens = append(tokens, fmt.Sprint(V(expr)))
+			if group, ok := expr.(or); ok && len(group) > 1 && len(this.where) > 1 {
+				tokens = append(tokens, "("+group.String()+")")
+			} else {
+				tokens = append(tokens, fmt.Sprint(V(expr)))
+			}
 		}
 	}
 
@@ -130,20 +187,35 @@ func (this *sel) Query() string {
 			if i > 0 {
 				token += ","
 			}
-			token += fmt.Sprint(expr)
+			token += fmt.Sprint(V(expr))
 		}
 		tokens = append(tokens, token)
 	}
 
-	// Add offset and limit
+	// Add offset and limit. OFFSET requires a LIMIT clause, so a negative
+	// limit is used for all rows after the offset. The LIMIT...OFFSET form is
+	// used rather than "LIMIT offset,limit" which reverses the operands
 	if this.limit == 0 && this.offset > 0 {
 		tokens = append(tokens, "LIMIT -1 OFFSET", fmt.Sprint(this.offset))
 	} else if this.limit > 0 && this.offset == 0 {
 		tokens = append(tokens, "LIMIT", fmt.Sprint(this.limit))
 	} else if this.limit > 0 && this.offset > 0 {
-		tokens = append(tokens, "LIMIT", fmt.Sprint(this.offset)+","+fmt.Sprint(this.limit))
+		tokens = append(tokens, "LIMIT", fmt.Sprint(this.limit), "OFFSET", fmt.Sprint(this.offset))
+	}
+
+	// Add comment
+	if v := comment(this.comment); v != "" {
+		tokens = append(tokens, v)
 	}
 
 	// Return the query
 	return strings.Join(tokens, " ")
 }
+
+func (v or) String() string {
+	tokens := make([]string, len(v))
+	for i, expr := range v {
+		tokens[i] = fmt.Sprint(V(expr))
+	}
+	return strings.Join(tokens, " OR ")
+}
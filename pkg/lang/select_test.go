@@ -22,17 +22,43 @@ func Test_Select_000(t *testing.T) {
 		{S(N("a").WithAlias("aa"), N("b").WithAlias("bb")), "SELECT * FROM a AS aa,b AS bb"},
 		{S(N("a")).WithLimitOffset(1, 0), "SELECT * FROM a LIMIT 1"},
 		{S(N("a")).WithLimitOffset(0, 1), "SELECT * FROM a LIMIT -1 OFFSET 1"},
-		{S(N("a")).WithLimitOffset(1, 1), "SELECT * FROM a LIMIT 1,1"},
+		{S(N("a")).WithLimitOffset(0, 0), "SELECT * FROM a"},
+		{S(N("a")).WithLimitOffset(1, 1), "SELECT * FROM a LIMIT 1 OFFSET 1"},
+		{S(N("a")).WithLimitOffset(10, 5), "SELECT * FROM a LIMIT 10 OFFSET 5"},
+		{S(N("a")).WithLimitOffset(0, 5), "SELECT * FROM a LIMIT -1 OFFSET 5"},
 		{S(N("a")).Where(nil), "SELECT * FROM a WHERE NULL"},
 		{S(N("a")).Where(nil, nil), "SELECT * FROM a WHERE NULL AND NULL"},
 		{S(N("a")).Where(N("a")), "SELECT * FROM a WHERE a"},
 		{S(N("a")).Where(N("b"), N("c")), "SELECT * FROM a WHERE b AND c"},
 		{S(N("a")).Where(P, P), "SELECT * FROM a WHERE ? AND ?"},
+		{S(N("a")).Where(N("b").IsNull(), N("c").IsNotNull()), "SELECT * FROM a WHERE b IS NULL AND c IS NOT NULL"},
+		{S(N("a")).Where(N("b").IsDistinctFrom(P)), "SELECT * FROM a WHERE b IS NOT ?"},
+		{S(N("a")).Where(N("b").Glob(P), N("c").Glob(P).Not()), "SELECT * FROM a WHERE b GLOB ? AND c NOT GLOB ?"},
+		{S(N("a")).Where(N("a").Match(P)), "SELECT * FROM a WHERE a MATCH ?"},
 		{S(N("a")).Where(P).Where(P), "SELECT * FROM a WHERE ? AND ?"},
 		{S(N("a")).Where(V("foo"), V(true)), "SELECT * FROM a WHERE 'foo' AND TRUE"},
 		{S(N("a")).Where(V("foo"), V(false)), "SELECT * FROM a WHERE 'foo' AND FALSE"},
+		{S(N("a")).OrWhere(N("b"), N("c")), "SELECT * FROM a WHERE b OR c"},
+		{S(N("a")).OrWhere(N("b")).Where(N("c")), "SELECT * FROM a WHERE b AND c"},
+		{S(N("a")).Where(N("a")).OrWhere(N("b"), N("c")), "SELECT * FROM a WHERE a AND (b OR c)"},
+		{S(N("a")).OrWhere(N("b"), N("c")).OrWhere(N("d"), P), "SELECT * FROM a WHERE (b OR c) AND (d OR ?)"},
+		{S(N("a")).OrWhere(N("b").IsNull(), Q("c>", P)).Where(N("d")), "SELECT * FROM a WHERE (b IS NULL OR c>?) AND d"},
+		{S(N("a")).Where(N("a")).OrWhere(N("b"), N("c")).OrWhere(), "SELECT * FROM a"},
+		{S(N("a")).OrWhere(N("b"), N("c")).Where(), "SELECT * FROM a"},
+		{S(N("foo")).To(N("a")).OrWhere(N("b"), N("c")).Where(N("d")).DistinctOn(N("a")), "SELECT a FROM foo WHERE rowid IN (SELECT MIN(rowid) FROM foo WHERE (b OR c) AND d GROUP BY a)"},
+		{S(N("foo")).WithComment("request 1"), "SELECT * FROM foo /* request 1 */"},
+		{S(N("foo")).WithComment("a\r\nb */ DROP TABLE foo; --").Where(P).WithLimitOffset(1, 0), "SELECT * FROM foo WHERE ? LIMIT 1 /* a b * / DROP TABLE foo; -- */"},
+		{S(N("foo")).WithComment("request 1").WithComment(""), "SELECT * FROM foo"},
+		{S(N("foo")).To(N("a")).DistinctOn(N("a")).WithComment("c"), "SELECT a FROM foo WHERE rowid IN (SELECT MIN(rowid) FROM foo GROUP BY a) /* c */"},
 		{S(N("foo")).Order(N("a")).Order(N("b")), "SELECT * FROM foo ORDER BY a,b"},
 		{S(N("foo")).Order(N("a"), N("b").WithDesc()), "SELECT * FROM foo ORDER BY a,b DESC"},
+		{S(N("foo")).OrderBy(2, N("name").WithDesc()), "SELECT * FROM foo ORDER BY 2,name DESC"},
+		{S(N("foo")).OrderBy(Q("length(a) DESC"), 1), "SELECT * FROM foo ORDER BY length(a) DESC,1"},
+		{S(N("foo")).Order(N("a")).OrderBy(Q("b COLLATE NOCASE")).Order(N("c")), "SELECT * FROM foo ORDER BY a,b COLLATE NOCASE,c"},
+		{S(N("foo")).OrderBy(1).OrderBy(), "SELECT * FROM foo"},
+		{S(N("foo")).DistinctOn(N("a")), "SELECT * FROM foo WHERE rowid IN (SELECT MIN(rowid) FROM foo GROUP BY a)"},
+		{S(N("foo")).DistinctOn(N("a"), N("b")).Order(N("a")), "SELECT * FROM foo WHERE rowid IN (SELECT MIN(rowid) FROM foo GROUP BY a,b) ORDER BY a"},
+		{S(N("foo")).To(N("a")).Where(Q("b>", P)).DistinctOn(N("a")), "SELECT a FROM foo WHERE rowid IN (SELECT MIN(rowid) FROM foo WHERE b>? GROUP BY a)"},
 	}
 
 	for i, test := range tests {
@@ -67,6 +93,10 @@ func Test_Select_002(t *testing.T) {
 	}{
 		{S(J(N("a"), N("b"))), `SELECT * FROM a CROSS JOIN b`},
 		{S(J(N("a"), N("b")).Join(Q("a=b"))), `SELECT * FROM a JOIN b ON a=b`},
+		{S(J(N("a"), N("b")).Join(Q("a.id=b.a"))).To(N("a").All(), N("id").WithSchema("b")), `SELECT a.*,b.id FROM a JOIN b ON a.id=b.a`},
+		{S(J(N("a").WithAlias("x"), N("b").WithAlias("y")).Join(Q("x.id=y.a"))).To(N("a").WithAlias("x").All(), N("b").WithAlias("y").All()), `SELECT x.*,y.* FROM a AS x JOIN b AS y ON x.id=y.a`},
+		{S(N("a").WithSchema("main")).To(N("a").WithSchema("main").All()), `SELECT main.a.* FROM main.a`},
+		{S(N("select")).To(N("select").All()), `SELECT "select".* FROM "select"`},
 	}
 
 	for i, test := range tests {
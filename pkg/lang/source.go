@@ -12,10 +12,16 @@ import (
 // TYPES
 
 type source struct {
-	name   string
-	schema string
-	alias  string
-	desc   bool
+	name    string
+	schema  string
+	alias   string
+	desc    bool
+	collate string
+}
+
+// all is a source which selects all columns of a table (ie, "a.*")
+type all struct {
+	*source
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -23,7 +29,7 @@ type source struct {
 
 // N defines a table name or column name
 func N(s string) SQSource {
-	return &source{s, "", "", false}
+	return &source{s, "", "", false, ""}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -42,15 +48,15 @@ func (this *source) Alias() string {
 }
 
 func (this *source) WithName(name string) SQSource {
-	return &source{name, this.schema, this.alias, this.desc}
+	return &source{name, this.schema, this.alias, this.desc, this.collate}
 }
 
 func (this *source) WithSchema(schema string) SQSource {
-	return &source{this.name, schema, this.alias, this.desc}
+	return &source{this.name, schema, this.alias, this.desc, this.collate}
 }
 
 func (this *source) WithAlias(alias string) SQSource {
-	return &source{this.name, this.schema, alias, this.desc}
+	return &source{this.name, this.schema, alias, this.desc, this.collate}
 }
 
 func (this *source) WithType(decltype string) SQColumn {
@@ -58,7 +64,20 @@ func (this *source) WithType(decltype string) SQColumn {
 }
 
 func (this *source) WithDesc() SQSource {
-	return &source{this.name, this.schema, this.alias, true}
+	return &source{this.name, this.schema, this.alias, true, this.collate}
+}
+
+// WithCollate sets the collating sequence for the source, for ordering
+// and index columns (ie, "a COLLATE NOCASE")
+func (this *source) WithCollate(name string) SQSource {
+	return &source{this.name, this.schema, this.alias, this.desc, name}
+}
+
+// All returns a source which selects all columns of the table in a join, which
+// renders as "a.*". The alias of the table is used if set, so that
+// N("a").WithAlias("x").All() renders as "x.*"
+func (this *source) All() SQSource {
+	return &all{this}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -68,6 +87,39 @@ func (this *source) Or(v interface{}) SQExpr {
 	return &e{this, v, "OR"}
 }
 
+///////////////////////////////////////////////////////////////////////////////
+// COMPARISONS
+
+// IsNull returns a comparison which is true when the source is NULL
+func (this *source) IsNull() SQComparison {
+	return &e{this, nil, "IS"}
+}
+
+// IsNotNull returns a comparison which is true when the source is not NULL
+func (this *source) IsNotNull() SQComparison {
+	return &e{this, nil, "IS NOT"}
+}
+
+// IsDistinctFrom returns a comparison which treats NULL values as equal,
+// unlike the <> operator. For example, N("a").IsDistinctFrom(P) renders
+// as "a IS NOT ?"
+func (this *source) IsDistinctFrom(v interface{}) SQComparison {
+	return &e{this, v, "IS NOT"}
+}
+
+// Glob returns a comparison which is true when the source matches a
+// case-sensitive glob pattern. For example, N("a").Glob(P) renders
+// as "a GLOB ?"
+func (this *source) Glob(v interface{}) SQComparison {
+	return &e{this, v, "GLOB"}
+}
+
+// Match returns a comparison for a full-text search on a virtual table.
+// For example, N("a").Match(P) renders as "a MATCH ?"
+func (this *source) Match(v interface{}) SQComparison {
+	return &e{this, v, "MATCH"}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -81,6 +133,9 @@ func (this *source) String() string {
 	if this.alias != "" {
 		tokens = append(tokens, " AS ", QuoteIdentifier(this.alias))
 	}
+	if this.collate != "" {
+		tokens = append(tokens, " COLLATE ", QuoteIdentifier(this.collate))
+	}
 	if this.desc {
 		tokens = append(tokens, " DESC")
 	}
@@ -90,3 +145,18 @@ func (this *source) String() string {
 func (this *source) Query() string {
 	return this.String()
 }
+
+func (this *all) String() string {
+	switch {
+	case this.alias != "":
+		return QuoteIdentifier(this.alias) + ".*"
+	case this.schema != "":
+		return QuoteIdentifier(this.schema) + "." + QuoteIdentifier(this.name) + ".*"
+	default:
+		return QuoteIdentifier(this.name) + ".*"
+	}
+}
+
+func (this *all) Query() string {
+	return this.String()
+}
@@ -21,6 +21,24 @@ func Test_Source_000(t *testing.T) {
 		{N("insert").WithSchema("main").WithAlias("b"), `main."insert" AS b`},
 		{N("x").WithType("TEXT"), `x TEXT`},
 		{N("x").WithDesc(), `x DESC`},
+		{N("x").WithCollate("NOCASE"), `x COLLATE NOCASE`},
+		{N("x").WithDesc().WithCollate("RTRIM"), `x COLLATE RTRIM DESC`},
+		{N("a").IsNull(), `a IS NULL`},
+		{N("a").IsNotNull(), `a IS NOT NULL`},
+		{N("a").WithSchema("main").WithAlias("b").IsNull(), `main.a IS NULL`},
+		{N("a").IsDistinctFrom(P), `a IS NOT ?`},
+		{N("a").IsDistinctFrom(N("b").WithSchema("excluded")), `a IS NOT excluded.b`},
+		{N("a").IsDistinctFrom("b"), `a IS NOT 'b'`},
+		{N("a").Glob(P), `a GLOB ?`},
+		{N("a").Glob("*.txt"), `a GLOB '*.txt'`},
+		{N("a").Match(P), `a MATCH ?`},
+		{N("fts").WithSchema("main").Match("word"), `main.fts MATCH 'word'`},
+		{N("a").Glob(P).Not(), `a NOT GLOB ?`},
+		{N("a").Match(P).Not(), `a NOT MATCH ?`},
+		{N("a").Match(P).Not().Not(), `a MATCH ?`},
+		{N("a").IsNull().Not(), `a IS NOT NULL`},
+		{N("a").IsNotNull().Not(), `a IS NULL`},
+		{N("a").IsDistinctFrom(P).Not(), `a IS ?`},
 	}
 
 	for _, test := range tests {
@@ -29,7 +29,7 @@ func (this *source) CreateTrigger(table string, st ...SQStatement) SQTrigger {
 	if len(st) == 0 {
 		return nil
 	} else {
-		return &trigger{source{this.name, this.schema, "", false}, false, false, table, "AFTER", "INSERT", st}
+		return &trigger{source{this.name, this.schema, "", false, ""}, false, false, table, "AFTER", "INSERT", st}
 	}
 }
 
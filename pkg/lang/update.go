@@ -17,6 +17,7 @@ type update struct {
 	conflict string
 	where    []interface{}
 	columns  []string
+	comment  string
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -24,39 +25,44 @@ type update struct {
 
 // Update values in a table with a name and defined column names
 func (this *source) Update(columns ...string) SQUpdate {
-	return &update{&source{this.name, this.schema, "", false}, "", nil, columns}
+	return &update{&source{this.name, this.schema, "", false, ""}, "", nil, columns, ""}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // PROPERTIES
 
 func (this *update) WithAbort() SQUpdate {
-	return &update{this.source, "OR ABORT", this.where, this.columns}
+	return &update{this.source, "OR ABORT", this.where, this.columns, this.comment}
 }
 
 func (this *update) WithFail() SQUpdate {
-	return &update{this.source, "OR FAIL", this.where, this.columns}
+	return &update{this.source, "OR FAIL", this.where, this.columns, this.comment}
 }
 
 func (this *update) WithIgnore() SQUpdate {
-	return &update{this.source, "OR IGNORE", this.where, this.columns}
+	return &update{this.source, "OR IGNORE", this.where, this.columns, this.comment}
 }
 
 func (this *update) WithReplace() SQUpdate {
-	return &update{this.source, "OR REPLACE", this.where, this.columns}
+	return &update{this.source, "OR REPLACE", this.where, this.columns, this.comment}
 }
 
 func (this *update) WithRollback() SQUpdate {
-	return &update{this.source, "OR ROLLBACK", this.where, this.columns}
+	return &update{this.source, "OR ROLLBACK", this.where, this.columns, this.comment}
+}
+
+// WithComment appends a comment to the statement
+func (this *update) WithComment(v string) SQUpdate {
+	return &update{this.source, this.conflict, this.where, this.columns, v}
 }
 
 func (this *update) Where(v ...interface{}) SQUpdate {
 	if len(v) == 0 {
 		// Reset where clause
-		return &update{this.source, this.conflict, nil, this.columns}
+		return &update{this.source, this.conflict, nil, this.columns, this.comment}
 	}
 	// Where clause with an expression
-	return &update{this.source, this.conflict, append(this.where, v...), this.columns}
+	return &update{this.source, this.conflict, append(this.where, v...), this.columns, this.comment}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -95,6 +101,11 @@ func (this *update) Query() string {
 		}
 	}
 
+	// Add comment
+	if v := comment(this.comment); v != "" {
+		tokens = append(tokens, v)
+	}
+
 	// Return the query
 	return strings.Join(tokens, " ")
 }
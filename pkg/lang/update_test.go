@@ -23,6 +23,8 @@ func Test_Update_000(t *testing.T) {
 		{N("foo").Update("bar", "baz"), `UPDATE foo SET bar=?, baz=?`},
 		{N("foo").Update("bar").Where(Q("baz IS NULL")), `UPDATE foo SET bar=? WHERE baz IS NULL`},
 		{N("foo").Update("bar").Where(Q("baz IS NULL"), Q("baz IS NOT NULL")), `UPDATE foo SET bar=? WHERE baz IS NULL AND baz IS NOT NULL`},
+		{N("foo").Update("bar").WithComment("request 1").Where(Q("baz IS NULL")), `UPDATE foo SET bar=? WHERE baz IS NULL /* request 1 */`},
+		{N("foo").Update("bar").WithComment("a\nb").WithIgnore(), `UPDATE OR IGNORE foo SET bar=? /* a b */`},
 	}
 
 	for i, test := range tests {
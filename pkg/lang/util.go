@@ -26,3 +26,14 @@ func sliceJoin(slice interface{}, sep string, fn func(elem interface{}) string)
 	}
 	return strings.Join(result, sep)
 }
+
+// comment returns a string as an SQL comment, or an empty string if the
+// comment is empty. Newlines are replaced with spaces and the comment
+// terminator is broken up, so the comment cannot end early
+func comment(v string) string {
+	v = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ", "*/", "* /").Replace(v)
+	if v = strings.TrimSpace(v); v == "" {
+		return ""
+	}
+	return "/* " + v + " */"
+}
@@ -10,6 +10,7 @@ import (
 
 var (
 	regexpBareIdentifier = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+	regexpDeclType       = regexp.MustCompile(`^([A-Za-z]+)\s*(\(\s*[+-]?\d+\s*(,\s*[+-]?\d+\s*)?\))?$`)
 )
 
 /////////////////////////////////////////////////////////////////////
@@ -54,10 +55,11 @@ func QuoteIdentifiers(v ...string) string {
 
 // QuoteDeclType returns a supported type or quotes type
 // TEXT => TEXT
+// DECIMAL(10,2) => DECIMAL(10,2)
 // TIMESTAMP => TIMESTAMP
 // some other type => "some other type"
 func QuoteDeclType(v string) string {
-	if IsType(v) {
+	if IsDeclType(v) {
 		return v
 	}
 	if isBareIdentifier(v) {
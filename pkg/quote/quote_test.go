@@ -68,3 +68,20 @@ func Test_Quote_004(t *testing.T) {
 		}
 	}
 }
+
+func Test_Quote_005(t *testing.T) {
+	var tests = []struct{ from, to string }{
+		{"TEXT", `TEXT`},
+		{"NUMERIC", `NUMERIC`},
+		{"DECIMAL(10,2)", `DECIMAL(10,2)`},
+		{"VARCHAR(255)", `VARCHAR(255)`},
+		{"TIMESTAMP", `TIMESTAMP`},
+		{"some other", `"some other"`},
+		{"DECIMAL(10,", `"DECIMAL(10,"`},
+	}
+	for i, test := range tests {
+		if QuoteDeclType(test.from) != test.to {
+			t.Errorf("%d: Expected %s, got %s", i, test.to, QuoteDeclType(test.from))
+		}
+	}
+}
@@ -11,7 +11,7 @@ import (
 // GLOBALS
 
 const (
-	t = `TEXT BLOB FLOAT INTEGER BOOL`
+	t = `TEXT BLOB FLOAT INTEGER BOOL NUMERIC REAL DECIMAL DOUBLE INT BIGINT SMALLINT TINYINT VARCHAR CHARACTER NCHAR NVARCHAR CLOB BOOLEAN DATE DATETIME TIMESTAMP`
 )
 
 var (
@@ -27,7 +27,7 @@ var (
 func quoteInit() {
 	reservedOnce.Do(func() {
 		reservedWords = make(map[string]bool, sqlite3.KeywordCount())
-		reservedTypes = make(map[string]bool, 5)
+		reservedTypes = make(map[string]bool, len(strings.Fields(t)))
 		for i := 0; i < sqlite3.KeywordCount(); i++ {
 			k := strings.ToUpper(sqlite3.KeywordName(i))
 			reservedWords[k] = true
@@ -63,6 +63,16 @@ func ReservedWords() []string {
 	return result
 }
 
+// IsDeclType returns true if the given string is a sqlite type, optionally
+// with a size or precision and scale (ie, VARCHAR(255) or DECIMAL(10,2))
+func IsDeclType(k string) bool {
+	if m := regexpDeclType.FindStringSubmatch(k); m == nil {
+		return false
+	} else {
+		return IsType(strings.ToUpper(m[1]))
+	}
+}
+
 // Types returns a list of sqlite types
 func Types() []string {
 	return strings.Fields(t)
@@ -93,6 +93,36 @@ func (cache *ConnCache) Close() error {
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// busy returns true if any cached statement is in the middle of execution
+func (cache *ConnCache) busy() bool {
+	var busy bool
+	cache.Map.Range(func(key, value interface{}) bool {
+		busy = value.(*sqlite3.StatementEx).IsBusy()
+		return !busy
+	})
+	return busy
+}
+
+// purge finalizes and removes all cached statements, which are prepared
+// again when next used
+func (cache *ConnCache) purge() error {
+	cache.Mutex.Lock()
+	defer cache.Mutex.Unlock()
+
+	var result error
+	cache.Map.Range(func(key, value interface{}) bool {
+		if err := value.(*sqlite3.StatementEx).Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+		cache.Map.Delete(key)
+		return true
+	})
+	atomic.StoreUint32(&cache.n, 0)
+
+	// Return any errors
+	return result
+}
+
 func (cache *ConnCache) store(key string, st *sqlite3.StatementEx) {
 	cache.Map.Store(key, st)
 	if n := atomic.AddUint32(&cache.n, 1); n > cache.cap {
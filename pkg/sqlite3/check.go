@@ -0,0 +1,83 @@
+package sqlite3
+
+import (
+	"fmt"
+	"strconv"
+
+	// Import namespaces
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// FKViolation is a row which violates a foreign key constraint, as
+// reported by PRAGMA foreign_key_check
+type FKViolation struct {
+	Table  string // Table containing the violating row
+	RowId  int64  // Rowid of the violating row, or zero for WITHOUT ROWID tables
+	Parent string // Table referred to by the foreign key
+	Key    int    // Index of the foreign key constraint in the table
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	integrityCheckOk = "ok"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (v FKViolation) String() string {
+	str := "<fkviolation"
+	str += fmt.Sprintf(" table=%q", v.Table)
+	if v.RowId != 0 {
+		str += fmt.Sprint(" rowid=", v.RowId)
+	}
+	str += fmt.Sprintf(" parent=%q", v.Parent)
+	str += fmt.Sprint(" key=", v.Key)
+	return str + ">"
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// IntegrityCheck runs PRAGMA integrity_check and returns a list of problems
+// found in the database, or an empty list if there were none
+func (this *Conn) IntegrityCheck() ([]string, error) {
+	result := []string{}
+	if err := this.Exec(Q("PRAGMA integrity_check"), func(row, _ []string) bool {
+		if row[0] != integrityCheckOk {
+			result = append(result, row[0])
+		}
+		return false
+	}); err != nil {
+		return nil, err
+	}
+	// Return success
+	return result, nil
+}
+
+// ForeignKeyCheck runs PRAGMA foreign_key_check and returns the rows which
+// violate foreign key constraints, or an empty list if there were none
+func (this *Conn) ForeignKeyCheck() ([]FKViolation, error) {
+	result := []FKViolation{}
+	if err := this.Exec(Q("PRAGMA foreign_key_check"), func(row, _ []string) bool {
+		// columns are "table" "rowid" "parent" "fkid"
+		v := FKViolation{Table: row[0], Parent: row[2]}
+		if rowid, err := strconv.ParseInt(row[1], 10, 64); err == nil {
+			v.RowId = rowid
+		}
+		if key, err := strconv.ParseInt(row[3], 10, 32); err == nil {
+			v.Key = int(key)
+		}
+		result = append(result, v)
+		return false
+	}); err != nil {
+		return nil, err
+	}
+	// Return success
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package sqlite3_test
+
+import (
+	"testing"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Check_001(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Check clean database
+	if problems, err := conn.(*Conn).IntegrityCheck(); err != nil {
+		t.Error(err)
+	} else if len(problems) != 0 {
+		t.Error("Unexpected problems:", problems)
+	}
+	if violations, err := conn.(*Conn).ForeignKeyCheck(); err != nil {
+		t.Error(err)
+	} else if len(violations) != 0 {
+		t.Error("Unexpected violations:", violations)
+	}
+}
+
+func Test_Check_002(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Create parent and child tables, insert a child row with no parent
+	// while foreign key constraints are disabled
+	if err := conn.(*Conn).SetForeignKeyConstraints(false); err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range []SQStatement{
+		N("parent").CreateTable(C("id").WithType("INTEGER").WithPrimary()),
+		N("child").CreateTable(C("parent_id").WithType("INTEGER")).WithForeignKey(N("parent").ForeignKey("id"), "parent_id"),
+		Q("INSERT INTO child (parent_id) VALUES (100)"),
+	} {
+		if err := conn.Exec(st, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Check violation is reported
+	if violations, err := conn.(*Conn).ForeignKeyCheck(); err != nil {
+		t.Error(err)
+	} else if len(violations) != 1 {
+		t.Error("Unexpected violations:", violations)
+	} else if v := violations[0]; v.Table != "child" || v.Parent != "parent" || v.RowId != 1 {
+		t.Error("Unexpected violation:", v)
+	} else {
+		t.Log(v)
+	}
+}
@@ -0,0 +1,74 @@
+package sqlite3
+
+import (
+	// Packages
+	multierror "github.com/hashicorp/go-multierror"
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Flags which are not carried over to a cloned connection
+	cloneMaskFlags = SQFlag(sqlite3.SQLITE_OPEN_READONLY | sqlite3.SQLITE_OPEN_SHAREDCACHE)
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Clone returns a new in-memory connection with a copy of all the schemas
+// on the connection, including temporary and attached schemas. The clone
+// uses a private cache, so it is fully independent of the original
+// connection and any other connections. The clone needs to be closed
+// when no longer required. It cannot be performed within a transaction.
+func (conn *Conn) Clone() (*Conn, error) {
+	if !conn.ConnEx.Autocommit() {
+		return nil, ErrOutOfOrder.With("Clone cannot be performed in a transaction")
+	}
+
+	// Open a private in-memory database
+	flags := conn.f&^cloneMaskFlags | SQFlag(sqlite3.SQLITE_OPEN_CREATE|sqlite3.SQLITE_OPEN_READWRITE|sqlite3.SQLITE_OPEN_PRIVATECACHE)
+	clone, err := OpenPath(defaultMemory, flags)
+	if err != nil {
+		return nil, err
+	} else {
+		clone.loc = conn.loc
+		clone.timeout = conn.timeout
+	}
+
+	// Copy each schema in turn, attaching in-memory databases to the
+	// clone for attached schemas
+	for _, schema := range conn.Schemas() {
+		if schema != DefaultSchema && schema != tempSchema {
+			if err := clone.Attach(schema, ""); err != nil {
+				return nil, multierror.Append(err, clone.Close())
+			}
+		}
+		if err := conn.backupTo(clone, schema); err != nil {
+			return nil, multierror.Append(err, clone.Close())
+		}
+	}
+
+	// Return success
+	return clone, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// backupTo copies a schema to the same schema on the destination connection
+func (conn *Conn) backupTo(dest *Conn, schema string) error {
+	backup, err := conn.ConnEx.OpenBackup(dest.ConnEx.Conn, schema, schema)
+	if err != nil {
+		return err
+	}
+	if err := backup.Step(-1); err != nil && err != sqlite3.SQLITE_DONE {
+		return multierror.Append(err, backup.Finish())
+	}
+	return backup.Finish()
+}
@@ -0,0 +1,79 @@
+package sqlite3_test
+
+import (
+	"testing"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Clone_001(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Populate the main schema and an attached schema
+	if err := conn.(*Conn).Attach("other", ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range []SQStatement{
+		N("test").CreateTable(C("a").WithType("INTEGER")),
+		Q("INSERT INTO test (a) VALUES (1), (2), (3)"),
+		N("test").WithSchema("other").CreateTable(C("b")),
+		Q("INSERT INTO other.test (b) VALUES ('x')"),
+	} {
+		if err := conn.Exec(st, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Clone the connection
+	clone, err := conn.(*Conn).Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clone.Close()
+	if n := clone.Count("main", "test"); n != 3 {
+		t.Error("Expected 3 rows in clone, got", n)
+	}
+	if n := clone.Count("other", "test"); n != 1 {
+		t.Error("Expected 1 row in attached schema of clone, got", n)
+	}
+
+	// Mutate the clone, and check the original is unchanged
+	for _, st := range []SQStatement{
+		Q("DELETE FROM test WHERE a=1"),
+		Q("INSERT INTO other.test (b) VALUES ('y')"),
+		N("extra").CreateTable(C("c")),
+	} {
+		if err := clone.Exec(st, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := conn.Count("main", "test"); n != 3 {
+		t.Error("Expected 3 rows in original, got", n)
+	}
+	if n := conn.Count("other", "test"); n != 1 {
+		t.Error("Expected 1 row in attached schema of original, got", n)
+	}
+	if tables := conn.Tables("main"); len(tables) != 1 {
+		t.Error("Unexpected tables in original", tables)
+	}
+
+	// A new connection from the pool does not see the clone
+	conn2 := pool.Get()
+	defer pool.Put(conn2)
+	if n := conn2.Count("main", "test"); n != 3 {
+		t.Error("Expected 3 rows from pool connection, got", n)
+	}
+}
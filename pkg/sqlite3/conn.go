@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	// Modules
 	multierror "github.com/hashicorp/go-multierror"
@@ -32,6 +33,15 @@ type Conn struct {
 	c       chan struct{}
 	f       SQFlag
 	ctx     context.Context
+	loc     *time.Location
+
+	// Query timeout, and deadline for the current query
+	timeout  time.Duration
+	deadline time.Time
+	expired  bool
+
+	// Connection is to a read-only replica in a pool
+	replica bool
 }
 
 type Txn struct {
@@ -46,6 +56,11 @@ type TxnFunc func(SQTransaction) error
 ////////////////////////////////////////////////////////////////////////////////
 // GLOBALS
 
+const (
+	// Number of virtual machine instructions between progress handler calls
+	progressOps = 100
+)
+
 var (
 	counter int64
 )
@@ -93,9 +108,15 @@ func OpenPath(path string, flags SQFlag) (*Conn, error) {
 		return nil, err
 	} else {
 		conn.ConnEx = c
-		conn.f = flags
 	}
 
+	// Reflect the access mode of the main database in the flags, which may
+	// be read-only when the file is write-protected
+	if conn.ConnEx.Readonly(DefaultSchema) {
+		flags = flags&^SQFlag(sqlite3.SQLITE_OPEN_CREATE|sqlite3.SQLITE_OPEN_READWRITE) | SQFlag(sqlite3.SQLITE_OPEN_READONLY)
+	}
+	conn.f = flags
+
 	// Set cache to default size
 	if flags&SQLITE_OPEN_CACHE != 0 {
 		conn.SetCap(defaultCapacity)
@@ -163,7 +184,33 @@ func (conn *Conn) Exec(st SQStatement, fn SQExecFunc) error {
 	if st == nil {
 		return ErrBadParameter.With("Exec")
 	}
-	return conn.ConnEx.Exec(st.Query(), sqlite3.ExecFunc(fn))
+
+	// Set the query deadline, installing a progress handler when outside
+	// of a transaction
+	if conn.timeout > 0 {
+		conn.setDeadline()
+		if conn.ctx == nil {
+			conn.SetProgressHandler(progressOps, conn.progress)
+			defer conn.SetProgressHandler(0, nil)
+			defer conn.clearDeadline()
+		}
+	}
+
+	return conn.queryError(conn.ConnEx.Exec(st.Query(), sqlite3.ExecFunc(fn)))
+}
+
+// IsReadonly prepares a statement without executing it, and returns true if
+// the statement makes no direct changes to the content of the database
+func (conn *Conn) IsReadonly(st SQStatement) (bool, error) {
+	if st == nil {
+		return false, ErrBadParameter.With("IsReadonly")
+	}
+	s, err := conn.ConnEx.Prepare(st.Query())
+	if err != nil {
+		return false, err
+	}
+	defer s.Close()
+	return s.IsReadonly(), nil
 }
 
 // Execute SQL statement outside of transaction - currently not implemented
@@ -171,6 +218,12 @@ func (conn *Conn) Query(st SQStatement, v ...interface{}) (SQResults, error) {
 	return nil, ErrNotImplemented.With("Query")
 }
 
+// Execute SQL statement outside of transaction with a row limit - currently
+// not implemented
+func (conn *Conn) QueryLimited(st SQStatement, maxRows int, v ...interface{}) (SQResults, error) {
+	return nil, ErrNotImplemented.With("QueryLimited")
+}
+
 // Perform a transaction, rollback if error is returned
 func (conn *Conn) Do(ctx context.Context, flag SQFlag, fn func(SQTransaction) error) error {
 	conn.Mutex.Lock()
@@ -192,14 +245,24 @@ func (conn *Conn) Do(ctx context.Context, flag SQFlag, fn func(SQTransaction) er
 		}
 	}
 
-	// Transaction flags (UGLY!)
+	// Transaction flags (UGLY!). A read-only transaction is deferred, so
+	// that no lock is taken until the first read
 	v := sqlite3.SQLITE_TXN_DEFAULT
-	if flag.Is(SQLITE_TXN_EXCLUSIVE) {
+	readonly := flag.Is(SQLITE_TXN_READONLY)
+	if !readonly && flag.Is(SQLITE_TXN_EXCLUSIVE) {
 		v = sqlite3.SQLITE_TXN_EXCLUSIVE
-	} else if flag.Is(SQLITE_TXN_IMMEDIATE) {
+	} else if !readonly && flag.Is(SQLITE_TXN_IMMEDIATE) {
 		v = sqlite3.SQLITE_TXN_IMMEDIATE
 	}
 
+	// Prevent writes in a read-only transaction
+	if readonly {
+		if err := conn.ConnEx.Exec("PRAGMA query_only=1", nil); err != nil {
+			return err
+		}
+		defer conn.ConnEx.Exec("PRAGMA query_only=0", nil)
+	}
+
 	// Begin transaction
 	if err := conn.ConnEx.Begin(v); err != nil {
 		return err
@@ -208,14 +271,18 @@ func (conn *Conn) Do(ctx context.Context, flag SQFlag, fn func(SQTransaction) er
 	// Perform transaction
 	var result error
 	if fn != nil {
+		if ctx == nil {
+			ctx = context.Background()
+		}
 		conn.ctx = ctx
-		conn.SetProgressHandler(100, func() bool {
-			return ctx != nil && ctx.Err() != nil
-		})
+		conn.SetProgressHandler(progressOps, conn.progress)
 		if err := fn(&Txn{Conn: conn, f: flag}); err != nil {
 			result = multierror.Append(result, err)
+		} else if conn.expired {
+			result = multierror.Append(result, context.DeadlineExceeded)
 		}
 		conn.SetProgressHandler(0, nil)
+		conn.clearDeadline()
 		conn.ctx = nil
 	}
 
@@ -223,6 +290,13 @@ func (conn *Conn) Do(ctx context.Context, flag SQFlag, fn func(SQTransaction) er
 	if result == nil {
 		if err := conn.ConnEx.Commit(); err != nil {
 			result = multierror.Append(result, err)
+			// A commit which fails on deferred foreign key constraints
+			// leaves the transaction open
+			if !conn.ConnEx.Autocommit() {
+				if err := conn.ConnEx.Rollback(); err != nil {
+					result = multierror.Append(result, err)
+				}
+			}
 		}
 	} else {
 		if err := conn.ConnEx.Rollback(); err != nil {
@@ -241,9 +315,38 @@ func (conn *Conn) Do(ctx context.Context, flag SQFlag, fn func(SQTransaction) er
 	return result
 }
 
+// DoRead executes a read-only transaction, which is deferred so that it does
+// not take the write lock. On a database in WAL mode, read-only transactions
+// on different connections run concurrently with each other and with a writer,
+// when the connections use a private cache (CachePrivate). Any statement which
+// writes to the database returns an error.
+func (conn *Conn) DoRead(ctx context.Context, fn func(SQTransaction) error) error {
+	return conn.Do(ctx, SQLITE_TXN_READONLY, fn)
+}
+
+// DoTxn performs a transaction as Do, beginning the transaction in a mode:
+// sqlite3.SQLITE_TXN_DEFAULT (deferred), SQLITE_TXN_IMMEDIATE or
+// SQLITE_TXN_EXCLUSIVE. An immediate or exclusive transaction takes the write
+// lock when it begins, so that another writer cannot start and the transaction
+// does not fail with SQLITE_BUSY when upgrading from a read to a write.
+func (conn *Conn) DoTxn(ctx context.Context, mode sqlite3.SQTransaction, fn func(SQTransaction) error) error {
+	switch mode {
+	case sqlite3.SQLITE_TXN_DEFAULT:
+		return conn.Do(ctx, SQLITE_TXN_DEFAULT, fn)
+	case sqlite3.SQLITE_TXN_IMMEDIATE:
+		return conn.Do(ctx, SQLITE_TXN_IMMEDIATE, fn)
+	case sqlite3.SQLITE_TXN_EXCLUSIVE:
+		return conn.Do(ctx, SQLITE_TXN_EXCLUSIVE, fn)
+	default:
+		return ErrBadParameter.Withf("DoTxn: %q", mode)
+	}
+}
+
 // Attach database as schema. If path is empty then a new in-memory database
 // is attached. If the path does not exist then it is created if the
-// SQLITE_OPEN_CREATE flag is set.
+// SQLITE_OPEN_CREATE flag is set. A URI filename (ie, "file:ref.db?mode=ro")
+// is passed through with any parameters, and requires the connection to be
+// opened with the SQLITE_OPEN_URI flag.
 func (conn *Conn) Attach(schema, path string) error {
 	if schema == "" || schema == DefaultSchema {
 		return ErrBadParameter.Withf("%q", schema)
@@ -251,15 +354,19 @@ func (conn *Conn) Attach(schema, path string) error {
 	if path == "" {
 		return conn.Attach(schema, defaultMemory)
 	}
-	if strings.HasPrefix(path, "file:") {
-		return ErrBadParameter.Withf("%q: Attach does not support URI filenames", path)
+	if isURI(path) && !conn.Flags().Is(SQFlag(sqlite3.SQLITE_OPEN_URI)) {
+		return ErrBadParameter.Withf("%q: Attach requires SQLITE_OPEN_URI for URI filenames", path)
 	}
 	if !conn.ConnEx.Autocommit() {
 		return ErrOutOfOrder.With("Attach cannot be performed in a transaction")
 	}
 
 	// Create a new database or return an error if it doesn't exist
-	if path != defaultMemory {
+	if isURI(path) {
+		if _, err := url.Parse(path); err != nil {
+			return ErrBadParameter.Withf("%q: %v", path, err)
+		}
+	} else if path != defaultMemory {
 		_, err := os.Stat(path)
 		if os.IsNotExist(err) {
 			err = conn.attachCreate(path)
@@ -274,7 +381,9 @@ func (conn *Conn) Attach(schema, path string) error {
 	return conn.ConnEx.Exec("ATTACH DATABASE "+Quote(path)+" AS "+QuoteIdentifier(schema), nil)
 }
 
-// Detach database
+// Detach database. Cached prepared statements, which may refer to the
+// schema, are finalized first. Returns an error if any statement is in the
+// middle of execution
 func (conn *Conn) Detach(schema string) error {
 	if schema == "" || schema == DefaultSchema {
 		return ErrBadParameter.Withf("%q", schema)
@@ -282,10 +391,18 @@ func (conn *Conn) Detach(schema string) error {
 	if !conn.ConnEx.Autocommit() {
 		return ErrOutOfOrder.With("Detach cannot be performed in a transaction")
 	}
+	if conn.ConnCache.busy() {
+		return ErrOutOfOrder.Withf("Detach %q cannot be performed with active statements", schema)
+	}
+	if err := conn.ConnCache.purge(); err != nil {
+		return err
+	}
 	return conn.ConnEx.Exec("DETACH DATABASE "+QuoteIdentifier(schema), nil)
 }
 
-// Flags returns the Open Flags
+// Flags returns the Open Flags. The flags include SQLITE_OPEN_READONLY when
+// the main database is read-only, and SQLITE_OPEN_MEMORY for an in-memory
+// database
 func (c *Conn) Flags() SQFlag {
 	return c.f
 }
@@ -295,6 +412,50 @@ func (c *Conn) Counter() int64 {
 	return c.counter
 }
 
+// SetLocation sets the timezone for time values returned from queries. Time
+// values are always stored in the UTC timezone, and when cast to time.Time
+// on reading are converted to this location. If nil, time values are
+// returned in the UTC timezone.
+func (c *Conn) SetLocation(loc *time.Location) {
+	c.loc = loc
+}
+
+// SetQueryTimeout sets the maximum duration of each query or statement
+// execution, after which the statement is aborted and context.DeadlineExceeded
+// is returned. A zero duration disables the timeout.
+func (c *Conn) SetQueryTimeout(timeout time.Duration) {
+	if timeout < 0 {
+		timeout = 0
+	}
+	c.timeout = timeout
+}
+
+// QueryTimeout returns the maximum duration of each query, or zero
+func (c *Conn) QueryTimeout() time.Duration {
+	return c.timeout
+}
+
+// Location returns the timezone for time values returned from queries
+func (c *Conn) Location() *time.Location {
+	if c.loc == nil {
+		return time.UTC
+	}
+	return c.loc
+}
+
+// CompileOptions returns the compile-time options used to build the
+// library, without the "SQLITE_" prefix
+func (c *Conn) CompileOptions() []string {
+	return sqlite3.CompileOptions()
+}
+
+// HasCompileOption returns true if the library was built with the
+// compile-time option, such as ENABLE_FTS5 or ENABLE_SESSION. The "SQLITE_"
+// prefix is optional
+func (c *Conn) HasCompileOption(name string) bool {
+	return sqlite3.CompileOptionUsed(name)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS - TRANSACTIONS
 
@@ -304,20 +465,48 @@ func (txn *Txn) Query(st SQStatement, v ...interface{}) (SQResults, error) {
 		return nil, ErrBadParameter.With("Query")
 	}
 
+	// Set the query deadline
+	if txn.Conn.timeout > 0 {
+		txn.Conn.setDeadline()
+	}
+
+	// Expand slice arguments bound to "IN (?)" parameters
+	q, v := expandSlices(st.Query(), v)
+
 	// Get a results object
-	r, err := txn.Conn.ConnCache.Prepare(txn.Conn.ConnEx, st.Query())
+	r, err := txn.Conn.ConnCache.Prepare(txn.Conn.ConnEx, q)
 	if err != nil {
 		return nil, err
+	} else {
+		r.loc = txn.Conn.loc
 	}
 
 	// Execute first query
 	if err := r.NextQuery(v...); err != nil {
-		return nil, err
+		return nil, txn.Conn.queryError(err)
 	} else {
 		return r, nil
 	}
 }
 
+// QueryLimited executes a statement as Query, returning at most maxRows rows
+// of results from each query, which protects against reading every row of a
+// large table. Once the limit is reached, Truncated returns true and Err
+// returns ErrRowLimit if there were further rows, so the caller can either
+// use the rows read or treat the results as an error.
+func (txn *Txn) QueryLimited(st SQStatement, maxRows int, v ...interface{}) (SQResults, error) {
+	if maxRows <= 0 {
+		return nil, ErrBadParameter.Withf("QueryLimited: %v", maxRows)
+	}
+	results, err := txn.Query(st, v...)
+	if err != nil {
+		return nil, err
+	}
+	r := results.(*Results)
+	r.limit = maxRows
+	return r, nil
+}
+
 // Flags returns the Open Flags or'd with Transaction Flags
 func (t *Txn) Flags() SQFlag {
 	return t.f | t.Conn.f
@@ -340,3 +529,37 @@ func (conn *Conn) attachCreate(path string) error {
 		return nil
 	}
 }
+
+// progress is called periodically during statement execution, and returns
+// true to abort when the context is done or the query deadline has passed
+func (conn *Conn) progress() bool {
+	if conn.ctx != nil && conn.ctx.Err() != nil {
+		return true
+	}
+	if !conn.deadline.IsZero() && time.Now().After(conn.deadline) {
+		conn.expired = true
+		return true
+	}
+	return false
+}
+
+// setDeadline sets the deadline for the current query from the query timeout
+func (conn *Conn) setDeadline() {
+	conn.deadline = time.Now().Add(conn.timeout)
+	conn.expired = false
+}
+
+// clearDeadline removes any query deadline
+func (conn *Conn) clearDeadline() {
+	conn.deadline = time.Time{}
+	conn.expired = false
+}
+
+// queryError returns context.DeadlineExceeded if a statement was aborted
+// because the query deadline passed, or the error otherwise
+func (conn *Conn) queryError(err error) error {
+	if err != nil && conn.expired {
+		return context.DeadlineExceeded
+	}
+	return err
+}
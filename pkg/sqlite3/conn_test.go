@@ -0,0 +1,1023 @@
+package sqlite3_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	// Packages
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+
+	// Namespace Imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Conn_001(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Load timezones
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	newyork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// Insert a time in one zone
+	ts := time.Date(2021, 10, 1, 12, 0, 0, 0, berlin)
+	conn.(*Conn).SetLocation(newyork)
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if _, err := txn.Query(N("test").CreateTable(C("ts").WithType("TIMESTAMP"))); err != nil {
+			return err
+		}
+		if _, err := txn.Query(N("test").Insert("ts"), ts); err != nil {
+			return err
+		}
+
+		// Check storage format is UTC
+		if rs, err := txn.Query(S(N("test")).To(N("ts"))); err != nil {
+			return err
+		} else if row := rs.Next(); row == nil {
+			t.Error("Expected a row")
+		} else if row[0] != "2021-10-01T10:00:00Z" {
+			t.Errorf("Unexpected stored value: %q", row[0])
+		}
+
+		// Read back in another zone
+		rs, err := txn.Query(S(N("test")).To(N("ts")))
+		if err != nil {
+			return err
+		}
+		row := rs.Next(reflect.TypeOf(time.Time{}))
+		if row == nil {
+			t.Error("Expected a row")
+		} else if v, ok := row[0].(time.Time); !ok {
+			t.Errorf("Unexpected type: %T", row[0])
+		} else if !v.Equal(ts) {
+			t.Errorf("Expected %v, got %v", ts, v)
+		} else if v.Location() != newyork {
+			t.Errorf("Unexpected location: %v", v.Location())
+		} else {
+			t.Log(v)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Conn_002(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Insert rows 1 to 10
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO test (a) VALUES (1),(2),(3),(4),(5),(6),(7),(8),(9),(10)"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check limit and offset, first and last row returned and count
+	tests := []struct {
+		limit, offset      uint
+		first, last, count int64
+	}{
+		{0, 0, 1, 10, 10},
+		{3, 0, 1, 3, 3},
+		{0, 7, 8, 10, 3},
+		{3, 5, 6, 8, 3},
+		{5, 8, 9, 10, 2},
+	}
+	for _, test := range tests {
+		q := S(N("test")).To(N("a")).Order(N("a")).WithLimitOffset(test.limit, test.offset)
+		if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+			r, err := txn.Query(q)
+			if err != nil {
+				return err
+			}
+			var first, last, count int64
+			for {
+				row := r.Next()
+				if row == nil {
+					break
+				}
+				if count == 0 {
+					first = row[0].(int64)
+				}
+				last = row[0].(int64)
+				count++
+			}
+			if first != test.first || last != test.last || count != test.count {
+				t.Errorf("%q: unexpected rows first=%v last=%v count=%v", q, first, last, count)
+			}
+			return nil
+		}); err != nil {
+			t.Error(q, err)
+		}
+	}
+}
+
+func Test_Conn_003(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Insert rows with duplicate keys
+	if err := conn.Exec(N("test").CreateTable(C("key"), C("value").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO test (key, value) VALUES ('a',1),('b',2),('a',3),('c',4),('b',5),('c',6)"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Return one row per key, with the first row in each group
+	q := S(N("test")).To(N("key"), N("value")).Where(Q("value>", P)).DistinctOn(N("key")).Order(N("key"))
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		r, err := txn.Query(q, 1)
+		if err != nil {
+			return err
+		}
+		result := map[string]int64{}
+		for {
+			row := r.Next()
+			if row == nil {
+				break
+			}
+			if _, exists := result[row[0].(string)]; exists {
+				t.Error("Duplicate key", row)
+			}
+			result[row[0].(string)] = row[1].(int64)
+		}
+		if !reflect.DeepEqual(result, map[string]int64{"a": 3, "b": 2, "c": 4}) {
+			t.Error("Unexpected result", result)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Conn_004(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Create and populate a table
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO test (a) VALUES (1), (2), (3), (4)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := conn.(*Conn).Changes(); n != 4 {
+		t.Error("Unexpected changes", n)
+	}
+	total := conn.(*Conn).TotalChanges()
+
+	// Update rows via the callback exec
+	if err := conn.Exec(Q("UPDATE test SET a=a+10 WHERE a>2"), func(row, cols []string) bool {
+		return false
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n := conn.(*Conn).Changes(); n != 2 {
+		t.Error("Unexpected changes", n)
+	}
+	if n := conn.(*Conn).TotalChanges(); n != total+2 {
+		t.Error("Unexpected total changes", n)
+	}
+}
+
+func Test_Conn_005(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER"), C("b").WithType("TEXT")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO test (a,b) VALUES (1,'x'), (2,'y'), (3,'z'), (4,'?')"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		q      SQStatement
+		v      []interface{}
+		expect []interface{}
+	}{
+		{Q("SELECT a FROM test WHERE a IN (?) ORDER BY a"), []interface{}{[]int64{1, 3}}, []interface{}{int64(1), int64(3)}},
+		{Q("SELECT a FROM test WHERE a IN (?) ORDER BY a"), []interface{}{[]int64{}}, []interface{}{}},
+		{Q("SELECT a FROM test WHERE b IN ( ? ) AND a>? ORDER BY a"), []interface{}{[]string{"x", "y", "?"}, 1}, []interface{}{int64(2), int64(4)}},
+		{Q("SELECT a FROM test WHERE b='?' AND a NOT IN (?)"), []interface{}{[]int{1, 2}}, []interface{}{int64(4)}},
+		{Q("SELECT a FROM test WHERE a IN (?) AND b IN (?) ORDER BY a"), []interface{}{[]int{1, 2, 3}, []string{"z"}}, []interface{}{int64(3)}},
+	}
+	for i, test := range tests {
+		if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+			r, err := txn.Query(test.q, test.v...)
+			if err != nil {
+				return err
+			}
+			result := []interface{}{}
+			for row := r.Next(); row != nil; row = r.Next() {
+				result = append(result, row[0])
+			}
+			if !reflect.DeepEqual(result, test.expect) {
+				t.Errorf("Test %d: Expected %v, got %v", i, test.expect, result)
+			}
+			return nil
+		}); err != nil {
+			t.Errorf("Test %d: %v", i, err)
+		}
+	}
+}
+
+func Test_Conn_006(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		q        SQStatement
+		readonly bool
+	}{
+		{S(N("test")), true},
+		{N("test").Insert("a"), false},
+		{N("other").CreateTable(C("a")), false},
+	}
+	for _, test := range tests {
+		if v, err := conn.(*Conn).IsReadonly(test.q); err != nil {
+			t.Error(err)
+		} else if v != test.readonly {
+			t.Errorf("%q: Expected readonly %v", test.q, test.readonly)
+		}
+	}
+
+	// The statements are not executed
+	if tables := conn.Tables(""); len(tables) != 1 {
+		t.Error("Unexpected tables", tables)
+	}
+}
+
+func Test_Conn_007(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Attach a schema and prepare a cached statement against it
+	if err := conn.(*Conn).Attach("other", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(N("test").WithSchema("other").CreateTable(C("a").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+	query := func() error {
+		return conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+			_, err := txn.Query(S(N("test").WithSchema("other")))
+			return err
+		})
+	}
+	if err := query(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Detach the schema while a statement has rows remaining
+	var r SQResults
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if _, err := txn.Query(N("test").WithSchema("other").Insert("a"), 1); err != nil {
+			return err
+		} else if _, err := txn.Query(N("test").WithSchema("other").Insert("a"), 2); err != nil {
+			return err
+		}
+		r, err = txn.Query(S(N("test").WithSchema("other")))
+		if err == nil && r.Next() == nil {
+			t.Error("Expected a row")
+		}
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.(*Conn).Detach("other"); !errors.Is(err, ErrOutOfOrder) {
+		t.Error("Expected ErrOutOfOrder detaching with an active statement, got", err)
+	}
+	for row := r.Next(); row != nil; row = r.Next() {
+	}
+
+	// Detach the schema, and the statement now fails
+	if err := conn.(*Conn).Detach("other"); err != nil {
+		t.Fatal(err)
+	}
+	if err := query(); err == nil {
+		t.Error("Expected error querying a detached schema")
+	}
+
+	// Attach the schema again, and the statement succeeds
+	if err := conn.(*Conn).Attach("other", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(N("test").WithSchema("other").CreateTable(C("a").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := query(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Conn_008(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	if conn == nil {
+		t.Fatal("Unexpected nil connection")
+	}
+	defer pool.Put(conn)
+
+	// Create tables, a view and triggers
+	for _, st := range []SQStatement{
+		N("a").CreateTable(C("v")),
+		N("b").CreateTable(C("v")),
+		Q("CREATE VIEW a_view AS SELECT v FROM a"),
+		Q("CREATE TRIGGER a_insert AFTER INSERT ON a BEGIN INSERT INTO b (v) VALUES (NEW.v); END"),
+		Q("CREATE TRIGGER b_delete AFTER DELETE ON b BEGIN DELETE FROM a WHERE v=OLD.v; END"),
+	} {
+		if err := conn.Exec(st, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if views := conn.Views(""); len(views) != 1 || views[0] != "a_view" {
+		t.Error("Unexpected views", views)
+	}
+	if triggers := conn.Triggers("", ""); len(triggers) != 2 {
+		t.Error("Unexpected triggers", triggers)
+	}
+	if triggers := conn.Triggers("main", "a"); len(triggers) != 1 || triggers[0] != "a_insert" {
+		t.Error("Unexpected triggers", triggers)
+	}
+	if triggers := conn.Triggers("main", "a_view"); len(triggers) != 0 {
+		t.Error("Unexpected triggers", triggers)
+	}
+}
+
+func Test_Conn_009(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	if conn == nil {
+		t.Fatal("Unexpected nil connection")
+	}
+	defer pool.Put(conn)
+
+	// Create tables, where inserting into "a" inserts two rows into "b"
+	for _, st := range []SQStatement{
+		N("a").CreateTable(C("v")),
+		N("b").CreateTable(C("v")),
+		Q("CREATE TRIGGER a_insert AFTER INSERT ON a BEGIN INSERT INTO b (v) VALUES (NEW.v), (NEW.v); END"),
+	} {
+		if err := conn.Exec(st, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Insert rows and check the last insert id
+	for i := int64(1); i <= 3; i++ {
+		if err := conn.Exec(Q("INSERT INTO a (v) VALUES (", i, ")"), nil); err != nil {
+			t.Fatal(err)
+		}
+		if rowid := conn.LastInsertId(); rowid != i {
+			t.Errorf("Expected last insert id %v, got %v", i, rowid)
+		}
+	}
+
+	// The trigger inserts six rows into "b" but the last insert id
+	// is restored when the trigger completes
+	if n := conn.Count("", "b"); n != 6 {
+		t.Error("Unexpected count", n)
+	}
+	if rowid := conn.LastInsertId(); rowid != 3 {
+		t.Error("Unexpected last insert id", rowid)
+	}
+
+	// Insert into "b" directly, and set the last insert id explicitly
+	if err := conn.Exec(Q("INSERT INTO b (v) VALUES (0)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if rowid := conn.LastInsertId(); rowid != 7 {
+		t.Error("Unexpected last insert id", rowid)
+	}
+	conn.SetLastInsertId(100)
+	if rowid := conn.LastInsertId(); rowid != 100 {
+		t.Error("Unexpected last insert id", rowid)
+	}
+}
+
+func Test_Conn_010(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	path := filepath.Join(tmpdir, "test.sqlite")
+
+	// Read-write connection creates the database
+	rw, err := OpenPath(path, DefaultFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rw.Close()
+	if !rw.Flags().Is(SQFlag(sqlite3.SQLITE_OPEN_READWRITE)) || rw.Flags().Is(SQFlag(sqlite3.SQLITE_OPEN_READONLY)) {
+		t.Error("Unexpected flags for read-write connection", rw.Flags())
+	}
+	if filename := rw.Filename(DefaultSchema); filepath.Base(filename) != "test.sqlite" {
+		t.Error("Unexpected filename", filename)
+	}
+
+	// Read-only connection
+	ro, err := OpenPath(path, SQFlag(sqlite3.SQLITE_OPEN_READONLY))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ro.Close()
+	if ro.Flags().Is(SQFlag(sqlite3.SQLITE_OPEN_READWRITE)) || !ro.Flags().Is(SQFlag(sqlite3.SQLITE_OPEN_READONLY)) {
+		t.Error("Unexpected flags for read-only connection", ro.Flags())
+	}
+	if ro.Filename(DefaultSchema) != rw.Filename(DefaultSchema) {
+		t.Error("Unexpected filename", ro.Filename(DefaultSchema))
+	}
+
+	// Write-protected file is read-only, even when read-write is requested
+	if os.Getuid() != 0 {
+		if err := os.Chmod(path, 0444); err != nil {
+			t.Fatal(err)
+		}
+		wp, err := OpenPath(path, SQFlag(sqlite3.SQLITE_OPEN_READWRITE))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer wp.Close()
+		if !wp.Flags().Is(SQFlag(sqlite3.SQLITE_OPEN_READONLY)) {
+			t.Error("Expected read-only flag for write-protected file", wp.Flags())
+		}
+	}
+
+	// In-memory connection has no filename
+	mem, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mem.Close()
+	if !mem.Flags().Is(SQFlag(sqlite3.SQLITE_OPEN_MEMORY)) || mem.Filename(DefaultSchema) != "" {
+		t.Error("Unexpected flags or filename for in-memory connection", mem.Flags(), mem.Filename(DefaultSchema))
+	}
+}
+
+func Test_Conn_011(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Create a table and an index with collation and sort order
+	if err := conn.Exec(N("test").CreateTable(C("a"), C("b")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(N("test_ab").CreateIndex("test", N("a").WithCollate("NOCASE"), N("b").WithDesc()), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Index definition includes collation and direction
+	indexes := conn.IndexesForTable("", "test")
+	if len(indexes) != 1 {
+		t.Fatal("Unexpected indexes", indexes)
+	} else if q := indexes[0].Query(); q != "CREATE INDEX main.test_ab ON test (a COLLATE NOCASE,b DESC)" {
+		t.Error("Unexpected index", q)
+	} else if cols := indexes[0].Columns(); !reflect.DeepEqual(cols, []string{"a", "b"}) {
+		t.Error("Unexpected columns", cols)
+	}
+}
+
+func Test_Conn_012(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Create parent and child tables
+	if err := conn.Exec(Q("CREATE TABLE parent (id INTEGER PRIMARY KEY, name TEXT); CREATE TABLE child (parent INTEGER, name TEXT)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO parent VALUES (1, 'a'), (2, 'b'), (3, 'c'); INSERT INTO child VALUES (1, 'x'), (2, 'y'), (3, 'x')"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Select parents with a matching child, where the outer parameter is bound
+	// before the subquery parameter
+	sub := S(N("child")).To(V(1)).Where(Q("child.parent=parent.id"), Q("child.name=", P))
+	var tests = []struct {
+		where    SQComparison
+		expected []interface{}
+	}{
+		{Exists(sub), []interface{}{int64(3)}},
+		{NotExists(sub), []interface{}{int64(2)}},
+	}
+	for _, test := range tests {
+		var result []interface{}
+		if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+			rs, err := txn.Query(S(N("parent")).To(N("id")).Where(Q("parent.id>", P), test.where).Order(N("id")), 1, "x")
+			if err != nil {
+				return err
+			}
+			for row := rs.Next(); row != nil; row = rs.Next() {
+				result = append(result, row[0])
+			}
+			return nil
+		}); err != nil {
+			t.Error(err)
+		} else if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%v: Expected %v, got %v", test.where, test.expected, result)
+		}
+	}
+}
+
+func Test_Conn_013(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if options := conn.CompileOptions(); len(options) == 0 {
+		t.Error("Expected compile options")
+	}
+	for _, name := range []string{"ENABLE_FTS5", "SQLITE_ENABLE_SESSION", "THREADSAFE"} {
+		if !conn.HasCompileOption(name) {
+			t.Error("Expected compile option", name)
+		}
+	}
+	if conn.HasCompileOption("ENABLE_NOT_AN_OPTION") {
+		t.Error("Unexpected compile option")
+	}
+}
+
+type TestBindStruct struct {
+	Id     int64     `sqlite:"id"`
+	Name   string    `sqlite:"name"`
+	Score  float64   `sqlite:"score"`
+	Notes  *string   `sqlite:"notes"`
+	Ignore time.Time `sqlite:"ignore"`
+	Hidden string    `sqlite:"-"`
+}
+
+func Test_Conn_014(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Insert rows from structs, with a NULL notes field
+	notes := "notes"
+	rows := []TestBindStruct{
+		{1, "a", 1.5, &notes, time.Now(), "hidden"},
+		{2, "b", 2.5, nil, time.Time{}, ""},
+	}
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if _, err := txn.Query(Q("CREATE TABLE test (id INTEGER, name TEXT, score REAL, notes TEXT)")); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if _, err := txn.Query(Q("INSERT INTO test VALUES (:id, @name, $score, :notes)"), row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Select rows by binding from a struct pointer
+	for _, row := range rows {
+		if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+			rs, err := txn.Query(Q("SELECT score, notes FROM test WHERE id=:id AND name=:name"), &row)
+			if err != nil {
+				return err
+			}
+			result := rs.Next()
+			if result == nil {
+				t.Error("Expected a row for", row.Id)
+			} else if result[0] != row.Score {
+				t.Errorf("Expected score %v, got %v", row.Score, result[0])
+			} else if row.Notes == nil && result[1] != nil {
+				t.Errorf("Expected NULL notes, got %v", result[1])
+			} else if row.Notes != nil && result[1] != *row.Notes {
+				t.Errorf("Expected notes %q, got %v", *row.Notes, result[1])
+			}
+			return nil
+		}); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func Test_Conn_015(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Create a table then rename the table and a column
+	if err := conn.Exec(N("foo").CreateTable(C("a"), C("b")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(N("foo").AlterTable().RenameTo("bar"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(N("bar").AlterTable().RenameColumn("a", "c"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the new names
+	if tables := conn.Tables(""); !reflect.DeepEqual(tables, []string{"bar"}) {
+		t.Error("Unexpected tables", tables)
+	}
+	var names []string
+	for _, col := range conn.ColumnsForTable("", "bar") {
+		names = append(names, col.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"c", "b"}) {
+		t.Error("Unexpected columns", names)
+	}
+}
+
+func Test_Conn_016(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Create a table with a JSON column
+	if err := conn.Exec(Q("CREATE TABLE test (id INTEGER, meta TEXT); INSERT INTO test VALUES (1, '{\"k\":\"a\",\"n\":1}'), (2, '{\"k\":\"b\",\"n\":2}')"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Select rows by a value in the JSON column
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		rs, err := txn.Query(S(N("test")).To(N("id"), JSONExtract(N("meta"), "$.n").WithAlias("n"), JSONObject("id", N("id"), "k", P)).Where(Q(JSONExtract(N("meta"), "$.k"), "=", P)), "x", "b")
+		if err != nil {
+			return err
+		}
+		row := rs.Next()
+		expected := []interface{}{int64(2), int64(2), `{"id":2,"k":"x"}`}
+		if !reflect.DeepEqual(row, expected) {
+			t.Errorf("Expected %v, got %v", expected, row)
+		} else if row := rs.Next(); row != nil {
+			t.Error("Unexpected row", row)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Conn_017(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Create a table with constraints, an index and a trigger
+	ddl := []string{
+		"CREATE TABLE test (id INTEGER PRIMARY KEY, name TEXT NOT NULL UNIQUE, parent INTEGER REFERENCES test(id) ON DELETE CASCADE, CHECK (id > 0))",
+		"CREATE INDEX test_parent ON test (parent)",
+		"CREATE TRIGGER test_trigger AFTER DELETE ON test BEGIN DELETE FROM other WHERE id=old.id; END",
+	}
+	if err := conn.Exec(Q("CREATE TABLE other (id INTEGER)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range ddl {
+		if err := conn.Exec(Q(st), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The table is returned first, then the index and trigger
+	if sql, err := conn.TableDDL("", "test"); err != nil {
+		t.Error(err)
+	} else if expected := strings.Join(ddl, ";\n") + ";\n"; sql != expected {
+		t.Errorf("Expected %q, got %q", expected, sql)
+	}
+
+	// A missing table returns an error
+	if _, err := conn.TableDDL("", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+}
+
+func Test_Conn_018(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Create a large table with an index and a small table
+	if err := conn.Exec(Q("CREATE TABLE large (a INTEGER); CREATE INDEX large_a ON large (a); CREATE TABLE small (a INTEGER)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i+1 FROM n WHERE i < 20000) INSERT INTO large SELECT i FROM n"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO small VALUES (1), (2), (3)"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without statistics, the approximate count stops at the limit
+	if n := conn.Count("", "large"); n != 20000 {
+		t.Error("Unexpected count", n)
+	}
+	if n := conn.CountApprox("", "large"); n <= 0 || n >= 20000 {
+		t.Error("Unexpected approximate count", n)
+	}
+	if n, m := conn.Count("", "small"), conn.CountApprox("", "small"); n != 3 || m != 3 {
+		t.Error("Unexpected counts", n, m)
+	}
+
+	// With statistics, the approximate count matches the exact count
+	if err := conn.Exec(Q("ANALYZE"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := conn.CountApprox("", "large"); n != conn.Count("", "large") {
+		t.Error("Unexpected approximate count", n)
+	}
+
+	// Counting stops at the minimum number of rows
+	if !conn.CountAtLeast("", "large", 20000) {
+		t.Error("Expected at least 20000 rows")
+	} else if conn.CountAtLeast("", "large", 20001) {
+		t.Error("Expected fewer than 20001 rows")
+	} else if !conn.CountAtLeast("", "small", 0) {
+		t.Error("Expected at least zero rows")
+	}
+
+	// Missing tables return -1 or false
+	if n := conn.CountApprox("", "missing"); n != -1 {
+		t.Error("Unexpected approximate count", n)
+	} else if conn.CountAtLeast("", "missing", 0) {
+		t.Error("Unexpected result for missing table")
+	}
+}
+
+func Test_Conn_019(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.sqlite")
+
+	// Open two connections to the same database file, and return busy
+	// errors from the second connection without waiting
+	var conns []*Conn
+	for i := 0; i < 2; i++ {
+		conn, err := OpenPath(path, DefaultFlags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+	if err := conns[0].Exec(N("test").CreateTable(C("a")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conns[1].SetBusyTimeout(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// An immediate transaction prevents a second writer from starting, but
+	// not a reader
+	if err := conns[0].DoTxn(context.Background(), sqlite3.SQLITE_TXN_IMMEDIATE, func(SQTransaction) error {
+		if err := conns[1].DoTxn(context.Background(), sqlite3.SQLITE_TXN_IMMEDIATE, func(SQTransaction) error {
+			return nil
+		}); !IsBusy(err) {
+			t.Error("Expected a busy error, got", err)
+		}
+		if err := conns[1].DoTxn(context.Background(), sqlite3.SQLITE_TXN_DEFAULT, func(txn SQTransaction) error {
+			_, err := txn.Query(S(N("test")))
+			return err
+		}); err != nil {
+			t.Error(err)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+
+	// The second writer starts once the transaction has ended
+	if err := conns[1].DoTxn(context.Background(), sqlite3.SQLITE_TXN_EXCLUSIVE, func(SQTransaction) error {
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+
+	// Other modes are rejected
+	if err := conns[1].DoTxn(context.Background(), "OTHER", nil); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+}
+
+func Test_Conn_020(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.SetForeignKeyConstraints(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("CREATE TABLE parent (id INTEGER PRIMARY KEY)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("CREATE TABLE child (id INTEGER PRIMARY KEY, parent INTEGER REFERENCES parent(id))"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Foreign keys cannot be deferred outside of a transaction
+	if err := conn.SetDeferForeignKeys(true); !errors.Is(err, ErrOutOfOrder) {
+		t.Error("Expected ErrOutOfOrder, got", err)
+	}
+
+	// Without deferring, the child row cannot be inserted before the parent
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		_, err := txn.Query(Q("INSERT INTO child VALUES (1, 1)"))
+		return err
+	}); err == nil {
+		t.Error("Expected a foreign key error")
+	}
+
+	// When deferred, the check passes at commit if the parent row exists
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := conn.SetDeferForeignKeys(true); err != nil {
+			return err
+		}
+		if v, err := conn.DeferForeignKeys(); err != nil {
+			return err
+		} else if !v {
+			t.Error("Expected foreign keys to be deferred")
+		}
+		if _, err := txn.Query(Q("INSERT INTO child VALUES (1, 1)")); err != nil {
+			return err
+		}
+		_, err := txn.Query(Q("INSERT INTO parent VALUES (1)"))
+		return err
+	}); err != nil {
+		t.Error(err)
+	}
+
+	// When deferred, the check fails at commit if the parent row does not exist
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := conn.SetDeferForeignKeys(true); err != nil {
+			return err
+		}
+		if _, err := txn.Query(Q("INSERT INTO child VALUES (2, 2)")); err != nil {
+			t.Error("Unexpected error before commit", err)
+		}
+		return nil
+	}); err == nil {
+		t.Error("Expected a foreign key error on commit")
+	}
+	if n := conn.Count("", "child"); n != 1 {
+		t.Error("Expected one row, got", n)
+	}
+
+	// The setting is reset when the transaction ends
+	if v, err := conn.DeferForeignKeys(); err != nil {
+		t.Error(err)
+	} else if v {
+		t.Error("Expected foreign keys not to be deferred")
+	}
+}
+
+func Test_Conn_021(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.Exec(Q("CREATE TABLE test (a INTEGER)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("CREATE TRIGGER test_insert AFTER INSERT ON test WHEN new.a < 3 BEGIN INSERT INTO test VALUES (new.a + 1); END"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// A trigger does not fire itself unless recursive triggers are enabled
+	for _, enable := range []bool{false, true} {
+		if err := conn.SetRecursiveTriggers(enable); err != nil {
+			t.Fatal(err)
+		}
+		if v, err := conn.RecursiveTriggers(); err != nil {
+			t.Error(err)
+		} else if v != enable {
+			t.Errorf("Expected recursive triggers %v, got %v", enable, v)
+		}
+		if err := conn.Exec(Q("DELETE FROM test"), nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.Exec(Q("INSERT INTO test VALUES (1)"), nil); err != nil {
+			t.Fatal(err)
+		}
+		expected := int64(2)
+		if enable {
+			expected = 3
+		}
+		if n := conn.Count("", "test"); n != expected {
+			t.Errorf("Expected %v rows, got %v", expected, n)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+
+	// Packages
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type sqldriver struct{}
+
+// sqlconn is a connection in a database/sql pool
+type sqlconn struct {
+	*Conn
+}
+
+// sqlstmt is a prepared statement on a connection in a database/sql pool
+type sqlstmt struct {
+	*sqlite3.StatementEx
+}
+
+type sqltx struct {
+	*sqlite3.ConnEx
+}
+
+type sqlrows struct {
+	*sqlite3.Results
+}
+
+type sqlresult struct {
+	rowid   int64
+	changes int64
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// DriverName is the name of the database/sql driver for this package
+	DriverName = "go-sqlite"
+)
+
+func init() {
+	sql.Register(DriverName, sqldriver{})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Open a connection for a database/sql pool, with a path to a database file
+// or ":memory:". The database is created if it does not exist
+func (sqldriver) Open(path string) (driver.Conn, error) {
+	conn, err := OpenPath(path, DefaultFlags)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlconn{conn}, nil
+}
+
+// FromConn calls a function with the connection underlying a database/sql
+// connection, so that statements and transactions from this package can be
+// used with a connection from an existing database/sql pool. The pool must
+// have been opened with the DriverName driver, or else ErrBadParameter is
+// returned.
+//
+// The connection is owned by the database/sql pool. It is only valid within
+// the function, must not be closed, and must not be used by the database/sql
+// connection until the function has returned.
+func FromConn(conn *sql.Conn, fn func(*Conn) error) error {
+	if conn == nil || fn == nil {
+		return ErrBadParameter.With("FromConn")
+	}
+	return conn.Raw(func(v interface{}) error {
+		if conn, ok := v.(*sqlconn); !ok {
+			return ErrBadParameter.Withf("FromConn: %T", v)
+		} else {
+			return fn(conn.Conn)
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// CONNECTION
+
+func (c *sqlconn) Prepare(query string) (driver.Stmt, error) {
+	st, err := c.ConnEx.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlstmt{st}, nil
+}
+
+func (c *sqlconn) Begin() (driver.Tx, error) {
+	if err := c.ConnEx.Begin(sqlite3.SQLITE_TXN_DEFAULT); err != nil {
+		return nil, err
+	}
+	return &sqltx{c.ConnEx}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// STATEMENT
+
+// NumInput returns -1 as the number of placeholders is not checked
+func (s *sqlstmt) NumInput() int {
+	return -1
+}
+
+// Exec executes each statement in the query, binding the arguments to the
+// first statement
+func (s *sqlstmt) Exec(args []driver.Value) (driver.Result, error) {
+	var r []*sqlite3.Results
+	var err error
+	if len(args) > 0 {
+		r, err = s.ExecAll(values(args))
+	} else {
+		r, err = s.ExecAll()
+	}
+	if err != nil {
+		return nil, err
+	} else if len(r) == 0 {
+		return sqlresult{}, nil
+	} else {
+		last := r[len(r)-1]
+		return sqlresult{last.LastInsertId(), int64(last.RowsAffected())}, nil
+	}
+}
+
+// Query executes the first statement in the query, and returns the rows
+func (s *sqlstmt) Query(args []driver.Value) (driver.Rows, error) {
+	r, err := s.StatementEx.Exec(0, values(args)...)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlrows{r}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// ROWS
+
+func (r *sqlrows) Columns() []string {
+	result := make([]string, r.ColumnCount())
+	for i := range result {
+		result[i] = r.ColumnName(i)
+	}
+	return result
+}
+
+func (r *sqlrows) Next(dest []driver.Value) error {
+	row := r.Results.Next()
+	if row == nil {
+		return io.EOF
+	}
+	for i := range dest {
+		dest[i] = row[i]
+	}
+	return nil
+}
+
+// Close does nothing, as the statement is reset when it is next executed
+// or closed
+func (r *sqlrows) Close() error {
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// RESULT
+
+func (r sqlresult) LastInsertId() (int64, error) {
+	return r.rowid, nil
+}
+
+func (r sqlresult) RowsAffected() (int64, error) {
+	return r.changes, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func values(args []driver.Value) []interface{} {
+	result := make([]interface{}, len(args))
+	for i, arg := range args {
+		result[i] = arg
+	}
+	return result
+}
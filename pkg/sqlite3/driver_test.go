@@ -0,0 +1,98 @@
+package sqlite3_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Driver_001(t *testing.T) {
+	db, err := sql.Open(DriverName, filepath.Join(t.TempDir(), "test.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Write rows with the database/sql connection
+	if _, err := conn.ExecContext(context.Background(), "CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := conn.ExecContext(context.Background(), "INSERT INTO foo (id, name) VALUES (?, ?)", 1, "bar"); err != nil {
+		t.Fatal(err)
+	} else if n, _ := r.RowsAffected(); n != 1 {
+		t.Error("Unexpected rows affected", n)
+	}
+
+	// Read and write rows on the same connection with statements
+	if err := FromConn(conn, func(c *Conn) error {
+		return c.Do(context.Background(), 0, func(txn SQTransaction) error {
+			if _, err := txn.Query(N("foo").Insert("id", "name"), 2, "baz"); err != nil {
+				return err
+			}
+			if n := txn.Count("main", "foo"); n != 2 {
+				t.Error("Unexpected number of rows", n)
+			}
+			return nil
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The database/sql connection remains open, and reads the rows
+	var name string
+	if err := conn.QueryRowContext(context.Background(), "SELECT name FROM foo WHERE id=?", 2).Scan(&name); err != nil {
+		t.Error(err)
+	} else if name != "baz" {
+		t.Error("Unexpected name", name)
+	}
+}
+
+func Test_Driver_002(t *testing.T) {
+	db, err := sql.Open(DriverName, filepath.Join(t.TempDir(), "test.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE foo (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rolled back transaction does not write rows
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec("INSERT INTO foo (id) VALUES (1)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM foo").Scan(&n); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Error("Unexpected number of rows", n)
+	}
+
+	// FromConn requires a connection and a function
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := FromConn(conn, nil); err == nil {
+		t.Error("Expected an error")
+	}
+}
@@ -2,6 +2,7 @@ package sqlite3
 
 import (
 	// Import namespaces
+	. "github.com/djthorpe/go-errors"
 	. "github.com/mutablelogic/go-sqlite/pkg/lang"
 )
 
@@ -28,3 +29,21 @@ func (this *Conn) SetForeignKeyConstraints(enable bool) error {
 	}
 	return this.Exec(Q("PRAGMA foreign_keys=", V(enable)), nil)
 }
+
+// DeferForeignKeys returns true if foreign key constraints are checked when
+// the current transaction commits, rather than after each statement
+func (this *Conn) DeferForeignKeys() (bool, error) {
+	v, err := this.pragmaInt(Q("PRAGMA defer_foreign_keys"))
+	return v != 0, err
+}
+
+// SetDeferForeignKeys defers checking foreign key constraints until the
+// current transaction commits, so that rows can be inserted or deleted in
+// any order. The setting is reset when the transaction ends, and returns
+// an error outside of a transaction
+func (this *Conn) SetDeferForeignKeys(enable bool) error {
+	if this.ConnEx.Autocommit() {
+		return ErrOutOfOrder.With("SetDeferForeignKeys: not in a transaction")
+	}
+	return this.Exec(Q("PRAGMA defer_foreign_keys=", V(enable)), nil)
+}
@@ -0,0 +1,67 @@
+package sqlite3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite/pkg/quote"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// FormatRow renders a row of values for logging and output. NULL values are
+// rendered as NULL, text is quoted, blobs are rendered as x'..' and times are
+// rendered in RFC3339 format
+func FormatRow(values []interface{}) string {
+	tokens := make([]string, len(values))
+	for i, v := range values {
+		tokens[i] = formatValue(v)
+	}
+	return "(" + strings.Join(tokens, ", ") + ")"
+}
+
+// FormatRows renders rows of values for logging and output, with one row
+// on each line
+func FormatRows(rows [][]interface{}) string {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = FormatRow(row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// formatValue renders a single value
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return Quote(v)
+	case []byte:
+		return "x" + Quote(hex.EncodeToString(v))
+	case Blob:
+		return "x" + Quote(hex.EncodeToString(v))
+	case time.Time:
+		return Quote(v.Format(time.RFC3339))
+	case bool:
+		if v {
+			return "TRUE"
+		} else {
+			return "FALSE"
+		}
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,35 @@
+package sqlite3_test
+
+import (
+	"testing"
+	"time"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Format_001(t *testing.T) {
+	ts := time.Date(2021, 9, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		row    []interface{}
+		expect string
+	}{
+		{[]interface{}{}, `()`},
+		{[]interface{}{nil}, `(NULL)`},
+		{[]interface{}{int64(-10), uint8(2)}, `(-10, 2)`},
+		{[]interface{}{1.5, float32(0.25)}, `(1.5, 0.25)`},
+		{[]interface{}{"it's"}, `('it''s')`},
+		{[]interface{}{[]byte{0x00, 0xFF}, Blob{0x0A}}, `(x'00ff', x'0a')`},
+		{[]interface{}{ts}, `('2021-09-01T12:30:00Z')`},
+		{[]interface{}{true, false}, `(TRUE, FALSE)`},
+		{[]interface{}{int64(1), "a", nil}, `(1, 'a', NULL)`},
+	}
+	for _, test := range tests {
+		if v := FormatRow(test.row); v != test.expect {
+			t.Errorf("Got %v, expected %v", v, test.expect)
+		}
+	}
+	if v := FormatRows([][]interface{}{{int64(1)}, {nil}}); v != "(1)\n(NULL)" {
+		t.Errorf("Unexpected rows %q", v)
+	}
+}
@@ -0,0 +1,110 @@
+package sqlite3
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	// Packages
+	multierror "github.com/hashicorp/go-multierror"
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Migrate compares the columns of a class with the existing table in a schema,
+// and returns statements which add any new columns to the table. SQLite cannot
+// alter columns in place, so columns which have been removed from the class,
+// columns which have changed and new primary key columns are reported as
+// errors. The statements are returned even when an error is returned, so that
+// new columns can still be added.
+func (c *Conn) Migrate(class SQClass, schema string) ([]SQStatement, error) {
+	if class == nil {
+		return nil, ErrBadParameter.With("Migrate")
+	}
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	// Check for the existing table
+	name := class.Name()
+	if !inList(c.Tables(schema), name, false) {
+		return nil, ErrNotFound.Withf("Migrate: Table %q not found in schema %q", name, schema)
+	}
+
+	// Index the existing columns by name, which is case-insensitive
+	existing := c.ColumnsForTable(schema, name)
+	cols := make(map[string]SQColumn, len(existing))
+	for _, col := range existing {
+		cols[strings.ToLower(col.Name())] = col
+	}
+
+	// Add new columns and report changed columns
+	var result []SQStatement
+	var errs error
+	source := N(name).WithSchema(schema)
+	for _, col := range class.Columns() {
+		key := strings.ToLower(col.Name())
+		other, exists := cols[key]
+		delete(cols, key)
+		switch {
+		case !exists && col.Primary() != "":
+			errs = multierror.Append(errs, ErrNotModified.Withf("Migrate: Cannot add primary key column %q", col.Name()))
+		case !exists:
+			result = append(result, source.AlterTable().AddColumn(col))
+		case !strings.EqualFold(col.Type(), other.Type()) || col.Nullable() != other.Nullable() || (col.Primary() == "") != (other.Primary() == ""):
+			errs = multierror.Append(errs, ErrNotModified.Withf("Migrate: Cannot alter column %q in place (%v => %v)", col.Name(), other, col))
+		}
+	}
+
+	// Report removed columns, in table order
+	for _, col := range existing {
+		if _, exists := cols[strings.ToLower(col.Name())]; exists {
+			errs = multierror.Append(errs, ErrNotModified.Withf("Migrate: Column %q not defined in class", col.Name()))
+		}
+	}
+
+	// Return statements and any errors
+	return result, errs
+}
+
+// WithSchemaLock performs a schema migration in an immediate transaction,
+// which serializes migrations between connections and processes sharing a
+// database file. While another connection holds the write lock, the
+// connection waits until the lock is released or the context is cancelled.
+// The lock is released when fn returns, and the transaction is rolled back
+// if fn returns an error or the context is cancelled. As another process may
+// already have performed the migration, fn should check the schema before
+// making any changes.
+func (c *Conn) WithSchemaLock(ctx context.Context, fn func(SQTransaction) error) error {
+	if fn == nil {
+		return ErrBadParameter.With("WithSchemaLock")
+	} else if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Wait for the lock until the context is cancelled, rather than
+	// returning an error after the busy timeout
+	if err := c.SetBusyHandler(func(int) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(retryBackoffMin):
+			return true
+		}
+	}); err != nil {
+		return err
+	}
+	defer c.SetBusyTimeout(sqlite3.DefaultBusyTimeout)
+
+	return Retry(ctx, math.MaxInt32, func() error {
+		return c.Do(ctx, SQLITE_TXN_IMMEDIATE, fn)
+	})
+}
@@ -0,0 +1,159 @@
+package sqlite3_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	// Packages
+	sqobj "github.com/mutablelogic/go-sqlite/pkg/sqobj"
+
+	// Namespace Imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+type TestMigrateV1 struct {
+	Id   int    `sqlite:"id,primary"`
+	Name string `sqlite:"name"`
+	Old  string `sqlite:"old"`
+}
+
+type TestMigrateV2 struct {
+	Id    int    `sqlite:"id,primary"`
+	Name  string `sqlite:"name,notnull"`
+	Email string `sqlite:"email"`
+	Count int    `sqlite:"count"`
+}
+
+func Test_Migrate_001(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Unknown table is not found
+	v1 := sqobj.MustRegisterClass(N("migrate"), TestMigrateV1{})
+	if _, err := conn.(*Conn).Migrate(v1, ""); !errors.Is(err, ErrNotFound) {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+
+	// Create the table from the first version, which needs no migration
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		return v1.Create(txn, "main")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if st, err := conn.(*Conn).Migrate(v1, "main"); err != nil {
+		t.Error(err)
+	} else if len(st) != 0 {
+		t.Error("Unexpected statements", st)
+	}
+
+	// Second version adds columns, changes and removes others
+	v2 := sqobj.MustRegisterClass(N("migrate"), TestMigrateV2{})
+	st, err := conn.(*Conn).Migrate(v2, "main")
+	if !errors.Is(err, ErrNotModified) {
+		t.Error("Expected ErrNotModified, got", err)
+	} else {
+		t.Log(err)
+	}
+	if len(st) != 2 {
+		t.Fatal("Unexpected statements", st)
+	} else if q := st[0].Query(); q != `ALTER TABLE main.migrate ADD COLUMN email TEXT` {
+		t.Errorf("Unexpected statement %q", q)
+	} else if q := st[1].Query(); q != `ALTER TABLE main.migrate ADD COLUMN count INTEGER` {
+		t.Errorf("Unexpected statement %q", q)
+	}
+
+	// Apply the statements and check the columns
+	for _, st := range st {
+		if err := conn.Exec(st, nil); err != nil {
+			t.Error(err)
+		}
+	}
+	if cols := conn.(*Conn).ColumnsForTable("main", "migrate"); len(cols) != 5 {
+		t.Error("Unexpected columns", cols)
+	}
+}
+
+func Test_Migrate_002(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.sqlite")
+
+	// Open two connections to the same database file, as two processes would
+	var conns []*Conn
+	for i := 0; i < 2; i++ {
+		conn, err := OpenPath(path, DefaultFlags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+
+	// Both connections contend to create the table, and only one does
+	var wg sync.WaitGroup
+	var n int32
+	for _, conn := range conns {
+		wg.Add(1)
+		go func(conn *Conn) {
+			defer wg.Done()
+			if err := conn.WithSchemaLock(context.Background(), func(txn SQTransaction) error {
+				for _, name := range txn.Tables("") {
+					if name == "migrated" {
+						return nil
+					}
+				}
+				atomic.AddInt32(&n, 1)
+				time.Sleep(50 * time.Millisecond)
+				_, err := txn.Query(N("migrated").CreateTable(C("a")))
+				return err
+			}); err != nil {
+				t.Error(err)
+			}
+		}(conn)
+	}
+	wg.Wait()
+	if n != 1 {
+		t.Error("Expected one migration, got", n)
+	}
+
+	// While the lock is held, waiting for the lock stops when the context
+	// is cancelled
+	locked, release := make(chan struct{}), make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := conns[0].WithSchemaLock(context.Background(), func(txn SQTransaction) error {
+			close(locked)
+			<-release
+			return nil
+		}); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-locked
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := conns[1].WithSchemaLock(ctx, func(txn SQTransaction) error {
+		t.Error("Unexpected lock")
+		return nil
+	}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected context.DeadlineExceeded, got", err)
+	}
+	close(release)
+	wg.Wait()
+}
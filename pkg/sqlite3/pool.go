@@ -1,8 +1,13 @@
 package sqlite3
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -23,29 +28,100 @@ import (
 
 // PoolConfig is the starting configuration for a pool
 type PoolConfig struct {
-	Max     int32             `yaml:"max"`       // The maximum number of connections in the pool
-	Schemas map[string]string `yaml:"databases"` // Schema names mapped onto path for database file
-	Create  bool              `yaml:"create"`    // When false, do not allow creation of new file-based databases
-	Auth    SQAuth            // Authentication and Authorization interface
-	Trace   TraceFunc         // Trace function
-	Flags   SQFlag            // Flags for opening connections
+	Max     int32                   `yaml:"max"`       // The maximum number of connections in the pool
+	WarmUp  int32                   `yaml:"warmup"`    // The number of connections opened with the pool
+	Schemas map[string]string       `yaml:"databases"` // Schema names mapped onto path for database file
+	Create  bool                    `yaml:"create"`    // When false, do not allow creation of new file-based databases
+	Limits  map[sqlite3.SQLimit]int `yaml:"limits"`    // Run-time limits applied to each connection
+	Auth    SQAuth                  // Authentication and Authorization interface
+	Trace   TraceFunc               // Trace function
+	Flags   SQFlag                  // Flags for opening connections
+
+	// ForeignKeys enables foreign key enforcement on each connection
+	ForeignKeys bool `yaml:"foreignkeys"`
+
+	// QueryTimeout is the maximum duration of each query on a connection,
+	// or zero for no timeout
+	QueryTimeout time.Duration `yaml:"timeout"`
+
+	// Cache and threading modes, which override the flags when set
+	Cache     CacheMode     `yaml:"cache"`
+	Threading ThreadingMode `yaml:"threading"`
+
+	// TempStore sets where temporary tables and indexes are stored, MmapSize
+	// sets the maximum number of bytes of each database file which are
+	// memory-mapped, and CacheSizeKB sets the page cache size in kibibytes
+	// for each schema. Zero values leave the SQLite defaults unchanged
+	TempStore   TempStore `yaml:"tempstore"`
+	MmapSize    int64     `yaml:"mmapsize"`
+	CacheSizeKB int       `yaml:"cachesize"`
+
+	// OnConnect is called for each new connection, before it enters the pool
+	OnConnect ConnectFunc
+
+	// Replicas are read-only copies of the database for the default schema,
+	// which are opened in turn by GetReadOnly
+	Replicas []string `yaml:"replicas"`
 }
 
 // Pool is a connection pool object
 type Pool struct {
-	cfg   PoolConfig   // The configuration for the pool
-	pool  sync.Pool    // The pool of connections
-	errs  chan<- error // Errors are sent to this channel
-	n     int32        // The number of connections in the pool
-	drain int32        // Pool is draining (boolean)
+	cfg      PoolConfig   // The configuration for the pool
+	pool     sync.Pool    // The pool of connections
+	replicas sync.Pool    // The pool of read-only replica connections
+	errs     chan<- error // Errors are sent to this channel
+	n        int32        // The number of connections in the pool
+	drain    int32        // Pool is draining (boolean)
+	next     uint32       // The next replica to open
 }
 
 // TraceFunc is a function that is called when a statement is executed or prepared
 type TraceFunc func(c *Conn, q string, delta time.Duration)
 
+// ConnectFunc is a function that is called when a new connection is created,
+// after databases are attached. If an error is returned the connection is closed
+type ConnectFunc func(c *Conn) error
+
+// CacheMode determines whether connections share a cache
+type CacheMode uint
+
+// ThreadingMode determines the mutexes used by connections
+type ThreadingMode uint
+
+// TempStore determines where temporary tables and indexes are stored
+type TempStore uint
+
 ////////////////////////////////////////////////////////////////////////////////
 // GLOBALS
 
+const (
+	CacheDefault CacheMode = iota // Use the cache flags
+	CacheShared                   // Connections share a cache
+	CachePrivate                  // Each connection has a private cache
+)
+
+const (
+	ThreadingDefault     ThreadingMode = iota // Use the threading flags
+	ThreadingMultiThread                      // Connections are not shared between threads (no mutex)
+	ThreadingSerialized                       // Connections can be shared between threads (full mutex)
+)
+
+const (
+	TempStoreDefault TempStore = iota // Use the compile-time default
+	TempStoreFile                     // Temporary tables and indexes are stored in a file
+	TempStoreMemory                   // Temporary tables and indexes are stored in memory
+)
+
+const (
+	cacheFlags     = SQFlag(sqlite3.SQLITE_OPEN_SHAREDCACHE | sqlite3.SQLITE_OPEN_PRIVATECACHE)
+	threadingFlags = SQFlag(sqlite3.SQLITE_OPEN_NOMUTEX | sqlite3.SQLITE_OPEN_FULLMUTEX)
+)
+
+var (
+	// ErrNoConnection is returned from WithConn when no connection is available
+	ErrNoConnection = ErrChannelBlocked.With("No connection available")
+)
+
 var (
 	reSchemaName      = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9_-]+$")
 	defaultPoolConfig = PoolConfig{
@@ -77,6 +153,128 @@ func (cfg PoolConfig) WithTrace(fn TraceFunc) PoolConfig {
 	return cfg
 }
 
+// Set a function which is called for each new connection
+func (cfg PoolConfig) WithConnect(fn ConnectFunc) PoolConfig {
+	cfg.OnConnect = fn
+	return cfg
+}
+
+// Enable foreign key enforcement on each connection
+func (cfg PoolConfig) WithForeignKeys(enable bool) PoolConfig {
+	cfg.ForeignKeys = enable
+	return cfg
+}
+
+// Set the maximum duration of each query on a connection
+func (cfg PoolConfig) WithQueryTimeout(timeout time.Duration) PoolConfig {
+	if timeout >= 0 {
+		cfg.QueryTimeout = timeout
+	}
+	return cfg
+}
+
+// Set whether connections share a cache. A private cache cannot be used
+// with in-memory databases, which are shared between connections
+func (cfg PoolConfig) WithCacheMode(mode CacheMode) PoolConfig {
+	cfg.Cache = mode
+	return cfg
+}
+
+// Set the threading mode for connections
+func (cfg PoolConfig) WithThreadingMode(mode ThreadingMode) PoolConfig {
+	cfg.Threading = mode
+	return cfg
+}
+
+// Set where temporary tables and indexes are stored
+func (cfg PoolConfig) WithTempStore(store TempStore) PoolConfig {
+	cfg.TempStore = store
+	return cfg
+}
+
+// Set the maximum number of bytes of each database file which are
+// memory-mapped, or zero to use the default
+func (cfg PoolConfig) WithMmapSize(n int64) PoolConfig {
+	if n >= 0 {
+		cfg.MmapSize = n
+	}
+	return cfg
+}
+
+// Set the page cache size for each schema in kibibytes, or zero to use
+// the default
+func (cfg PoolConfig) WithCacheSizeKB(n int) PoolConfig {
+	if n >= 0 {
+		cfg.CacheSizeKB = n
+	}
+	return cfg
+}
+
+// OpenFlags returns the flags used to open connections, from the flags, create,
+// cache and threading modes, and allows URI filenames for attached schemas.
+// Returns an error for incompatible combinations
+func (cfg PoolConfig) OpenFlags() (SQFlag, error) {
+	flags := cfg.Flags
+	if flags == 0 {
+		flags = defaultPoolConfig.Flags
+	}
+
+	// Update create flag
+	if cfg.Create {
+		flags |= SQFlag(sqlite3.SQLITE_OPEN_CREATE)
+	} else {
+		flags &^= SQFlag(sqlite3.SQLITE_OPEN_CREATE)
+	}
+
+	// Set cache mode
+	switch cfg.Cache {
+	case CacheDefault:
+		break
+	case CacheShared:
+		flags = flags&^cacheFlags | SQFlag(sqlite3.SQLITE_OPEN_SHAREDCACHE)
+	case CachePrivate:
+		flags = flags&^cacheFlags | SQFlag(sqlite3.SQLITE_OPEN_PRIVATECACHE)
+	default:
+		return 0, ErrBadParameter.Withf("Cache mode %v", cfg.Cache)
+	}
+
+	// Set threading mode
+	switch cfg.Threading {
+	case ThreadingDefault:
+		break
+	case ThreadingMultiThread:
+		flags = flags&^threadingFlags | SQFlag(sqlite3.SQLITE_OPEN_NOMUTEX)
+	case ThreadingSerialized:
+		flags = flags&^threadingFlags | SQFlag(sqlite3.SQLITE_OPEN_FULLMUTEX)
+	default:
+		return 0, ErrBadParameter.Withf("Threading mode %v", cfg.Threading)
+	}
+
+	// Allow URI filenames for attached databases
+	for _, path := range cfg.Schemas {
+		if isURI(path) {
+			flags |= SQFlag(sqlite3.SQLITE_OPEN_URI)
+		}
+	}
+
+	// Check for incompatible flags
+	if flags&cacheFlags == cacheFlags {
+		return 0, ErrBadParameter.With("Shared and private cache flags are both set")
+	} else if flags&threadingFlags == threadingFlags {
+		return 0, ErrBadParameter.With("No mutex and full mutex flags are both set")
+	}
+	if flags&SQFlag(sqlite3.SQLITE_OPEN_PRIVATECACHE) != 0 {
+		for schema, path := range cfg.Schemas {
+			if path == "" || path == defaultMemory {
+				return 0, ErrBadParameter.Withf("Schema %q: In-memory database cannot use a private cache", schema)
+			}
+		}
+	}
+
+	// Return success
+	return flags, nil
+}
+
 // Enable or disable creation of database files
 func (cfg PoolConfig) WithCreate(create bool) PoolConfig {
 	cfg.Create = create
@@ -91,12 +289,38 @@ func (cfg PoolConfig) WithMaxConnections(n int) PoolConfig {
 	return cfg
 }
 
+// Set the number of connections opened with the pool, which is limited to
+// the maximum number of connections
+func (cfg PoolConfig) WithWarmUp(n int) PoolConfig {
+	if n >= 0 {
+		cfg.WarmUp = int32(n)
+	}
+	return cfg
+}
+
 // Add schema to the pool
 func (cfg PoolConfig) WithSchema(name, path string) PoolConfig {
 	cfg.Schemas[name] = path
 	return cfg
 }
 
+// Add a read-only replica of the database for the default schema
+func (cfg PoolConfig) WithReplica(path string) PoolConfig {
+	cfg.Replicas = append(append(make([]string, 0, len(cfg.Replicas)+1), cfg.Replicas...), path)
+	return cfg
+}
+
+// Set a run-time limit for each connection in the pool
+func (cfg PoolConfig) WithLimit(key sqlite3.SQLimit, v int) PoolConfig {
+	limits := make(map[sqlite3.SQLimit]int, len(cfg.Limits)+1)
+	for k, v := range cfg.Limits {
+		limits[k] = v
+	}
+	limits[key] = v
+	cfg.Limits = limits
+	return cfg
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
@@ -111,6 +335,92 @@ func NewPool(path string, errs chan<- error) (*Pool, error) {
 	return OpenPool(cfg, errs)
 }
 
+// ValidateConfig checks a pool configuration without opening or creating any
+// databases. It returns errors for invalid schema names, database files which
+// do not exist when creation is disabled, paths used by more than one schema
+// and invalid run-time limits.
+func ValidateConfig(cfg PoolConfig) error {
+	var result error
+
+	// Check for the default schema
+	if path, exists := cfg.Schemas[DefaultSchema]; !exists || path == "" {
+		result = multierror.Append(result, ErrNotFound.Withf("No default schema %q found", DefaultSchema))
+	}
+
+	// Check schema names and paths, in name order
+	names := make([]string, 0, len(cfg.Schemas))
+	for name := range cfg.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	paths := make(map[string]string, len(names))
+	for _, name := range names {
+		path := cfg.Schemas[name]
+		if !reSchemaName.MatchString(strings.TrimSpace(name)) {
+			result = multierror.Append(result, ErrBadParameter.Withf("Schema %q", name))
+			continue
+		}
+		if path == "" || path == defaultMemory {
+			continue
+		} else if isURI(path) {
+			if name == DefaultSchema {
+				result = multierror.Append(result, ErrBadParameter.Withf("Schema %q: URI filenames are only supported for attached databases: %q", name, path))
+			} else if _, err := url.Parse(path); err != nil {
+				result = multierror.Append(result, ErrBadParameter.Withf("Schema %q: %v", name, err))
+			}
+			continue
+		}
+		if info, err := os.Stat(path); os.IsNotExist(err) {
+			if !cfg.Create {
+				result = multierror.Append(result, ErrNotFound.Withf("Schema %q: Database does not exist: %q", name, path))
+			}
+		} else if err != nil {
+			result = multierror.Append(result, err)
+		} else if info.IsDir() {
+			result = multierror.Append(result, ErrBadParameter.Withf("Schema %q: Not a database file: %q", name, path))
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if other, exists := paths[path]; exists {
+			result = multierror.Append(result, ErrDuplicateEntry.Withf("Schemas %q and %q: %q", other, name, path))
+		} else {
+			paths[path] = name
+		}
+	}
+
+	// Check replicas, which are opened read-only and so need to exist
+	for _, path := range cfg.Replicas {
+		if path == "" || path == defaultMemory || isURI(path) {
+			result = multierror.Append(result, ErrBadParameter.Withf("Replica: %q", path))
+		} else if info, err := os.Stat(path); err != nil {
+			result = multierror.Append(result, ErrNotFound.Withf("Replica does not exist: %q", path))
+		} else if info.IsDir() {
+			result = multierror.Append(result, ErrBadParameter.Withf("Replica is not a database file: %q", path))
+		}
+	}
+
+	// Check flags
+	if _, err := cfg.OpenFlags(); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	// Check run-time limits
+	for key, v := range cfg.Limits {
+		if key < sqlite3.SQLITE_LIMIT_MIN || key > sqlite3.SQLITE_LIMIT_MAX || v < 0 {
+			result = multierror.Append(result, ErrBadParameter.Withf("Limit %v", key))
+		}
+	}
+
+	// Check pragmas
+	if err := cfg.checkPragmas(); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	// Return any errors
+	return result
+}
+
 // OpenPool returns a new pool with the specified configuration
 func OpenPool(config PoolConfig, errs chan<- error) (*Pool, error) {
 	p := new(Pool)
@@ -123,23 +433,26 @@ func OpenPool(config PoolConfig, errs chan<- error) (*Pool, error) {
 		config.Max = maxInt32(config.Max, 1)
 	}
 
-	// Set default flags if not set
-	if config.Flags == 0 {
-		config.Flags = defaultPoolConfig.Flags
-	}
-
-	// Update create flag
-	if config.Create {
-		config.Flags |= SQFlag(sqlite3.SQLITE_OPEN_CREATE)
+	// Set flags from the create, cache and threading modes
+	if flags, err := config.OpenFlags(); err != nil {
+		return nil, err
 	} else {
-		config.Flags &^= SQFlag(sqlite3.SQLITE_OPEN_CREATE)
+		config.Flags = flags
 	}
 
 	// Set up pool
 	p.cfg = config
 	p.errs = errs
 	p.pool = sync.Pool{New: func() interface{} {
-		if conn, errs := p.new(); errs != nil {
+		if conn, errs := p.new(false); errs != nil {
+			p.err(errs)
+			return nil
+		} else {
+			return conn
+		}
+	}}
+	p.replicas = sync.Pool{New: func() interface{} {
+		if conn, errs := p.new(true); errs != nil {
 			p.err(errs)
 			return nil
 		} else {
@@ -147,12 +460,24 @@ func OpenPool(config PoolConfig, errs chan<- error) (*Pool, error) {
 		}
 	}}
 
-	// Create a single connection and put in the pool
-	if conn, errs := p.new(); errs != nil {
-		return nil, errs
-	} else {
-		p.Put(conn)
-		p.n = 0
+	// Create the warm-up connections and put in the pool, which is at least
+	// one connection so that errors in the configuration are returned
+	n := minInt32(maxInt32(config.WarmUp, 1), config.Max)
+	conns := make([]SQConnection, 0, n)
+	for i := int32(0); i < n; i++ {
+		if conn, errs := p.new(false); errs != nil {
+			for _, conn := range conns {
+				if err := conn.(*Conn).Close(); err != nil {
+					errs = multierror.Append(errs, err)
+				}
+			}
+			return nil, errs
+		} else {
+			conns = append(conns, conn)
+		}
+	}
+	for _, conn := range conns {
+		p.pool.Put(conn)
 	}
 
 	// Return success
@@ -166,12 +491,14 @@ func (p *Pool) Close() error {
 	atomic.StoreInt32(&p.drain, 1)
 
 	var result error
-	for {
-		conn := p.pool.Get()
-		if conn == nil {
-			break
-		} else if err := conn.(*Conn).Close(); err != nil {
-			result = multierror.Append(result, err)
+	for _, pool := range []*sync.Pool{&p.pool, &p.replicas} {
+		for {
+			conn := pool.Get()
+			if conn == nil {
+				break
+			} else if err := conn.(*Conn).Close(); err != nil {
+				result = multierror.Append(result, err)
+			}
 		}
 	}
 
@@ -191,9 +518,51 @@ func (p *Pool) String() string {
 	for schema := range p.cfg.Schemas {
 		str += fmt.Sprintf(" <schema %s=%q>", strings.TrimSpace(schema), p.pathForSchema(schema))
 	}
+	for _, path := range p.cfg.Replicas {
+		str += fmt.Sprintf(" <replica %q>", path)
+	}
 	return str + ">"
 }
 
+func (m CacheMode) String() string {
+	switch m {
+	case CacheDefault:
+		return "CacheDefault"
+	case CacheShared:
+		return "CacheShared"
+	case CachePrivate:
+		return "CachePrivate"
+	default:
+		return "[?? Invalid CacheMode value]"
+	}
+}
+
+func (m TempStore) String() string {
+	switch m {
+	case TempStoreDefault:
+		return "TempStoreDefault"
+	case TempStoreFile:
+		return "TempStoreFile"
+	case TempStoreMemory:
+		return "TempStoreMemory"
+	default:
+		return "[?? Invalid TempStore value]"
+	}
+}
+
+func (m ThreadingMode) String() string {
+	switch m {
+	case ThreadingDefault:
+		return "ThreadingDefault"
+	case ThreadingMultiThread:
+		return "ThreadingMultiThread"
+	case ThreadingSerialized:
+		return "ThreadingSerialized"
+	default:
+		return "[?? Invalid ThreadingMode value]"
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
@@ -207,12 +576,47 @@ func (p *Pool) Get() SQConnection {
 	}
 }
 
+// GetReadOnly returns a read-only connection to one of the replicas, which
+// are opened in turn. If there are no replicas, a connection is returned
+// from Get. Writes should use a connection returned from Get
+func (p *Pool) GetReadOnly() SQConnection {
+	if len(p.cfg.Replicas) == 0 {
+		return p.Get()
+	}
+	if conn, ok := p.replicas.Get().(SQConnection); ok {
+		// Increment counter of open connections
+		atomic.AddInt32(&p.n, 1)
+		return conn
+	} else {
+		return nil
+	}
+}
+
 func (p *Pool) Put(conn SQConnection) {
 	if conn != nil {
 		// Decrement counter of open connections
 		atomic.AddInt32(&p.n, -1)
-		p.pool.Put(conn)
+		if conn, ok := conn.(*Conn); ok && conn.replica {
+			p.replicas.Put(conn)
+		} else {
+			p.pool.Put(conn)
+		}
+	}
+}
+
+// WithConn gets a connection from the pool, calls fn with it and returns
+// the connection to the pool, even when fn panics. Returns ErrNoConnection
+// when no connection is available, or the error returned from fn
+func (p *Pool) WithConn(ctx context.Context, fn func(SQConnection) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	conn := p.Get()
+	if conn == nil {
+		return ErrNoConnection
 	}
+	defer p.Put(conn)
+	return fn(conn)
 }
 
 // Return number of "checked out" (used) connections
@@ -237,7 +641,9 @@ func (p *Pool) SetMax(n int) {
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
-func (p *Pool) new() (SQConnection, error) {
+// new opens a connection to the default schema, or to the next replica
+// (read-only) when replica is true
+func (p *Pool) new(replica bool) (SQConnection, error) {
 	// If pool is being drained, return nil
 	if atomic.LoadInt32(&p.drain) != 0 {
 		return nil, nil
@@ -260,12 +666,44 @@ func (p *Pool) new() (SQConnection, error) {
 		flags |= SQFlag(sqlite3.SQLITE_OPEN_CREATE | sqlite3.SQLITE_OPEN_READWRITE)
 	}
 
+	// Open replicas in turn, read-only
+	if replica {
+		if len(p.cfg.Replicas) == 0 {
+			return nil, ErrNotFound.With("No replicas found")
+		}
+		next := atomic.AddUint32(&p.next, 1) - 1
+		defaultPath = p.cfg.Replicas[next%uint32(len(p.cfg.Replicas))]
+		flags = flags&^SQFlag(sqlite3.SQLITE_OPEN_CREATE|sqlite3.SQLITE_OPEN_READWRITE) | SQFlag(sqlite3.SQLITE_OPEN_READONLY)
+	}
+
 	// Perform the open
 	conn, err := OpenPath(defaultPath, flags)
 	if err != nil {
 		return nil, err
+	} else {
+		conn.replica = replica
+	}
+
+	// Enable foreign key enforcement, which must be set outside a transaction
+	if p.cfg.ForeignKeys {
+		if err := conn.SetForeignKeyConstraints(true); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	// Set run-time limits
+	for key, v := range p.cfg.Limits {
+		if key < sqlite3.SQLITE_LIMIT_MIN || key > sqlite3.SQLITE_LIMIT_MAX || v < 0 {
+			conn.Close()
+			return nil, ErrBadParameter.Withf("Limit %v", key)
+		}
+		conn.SetLimit(key, v)
 	}
 
+	// Set query timeout
+	conn.SetQueryTimeout(p.cfg.QueryTimeout)
+
 	// Set trace
 	if p.cfg.Trace != nil {
 		conn.ConnEx.SetTraceHook(func(_ sqlite3.TraceType, a, b unsafe.Pointer) int {
@@ -289,6 +727,13 @@ func (p *Pool) new() (SQConnection, error) {
 		}
 	}
 
+	// Set temporary storage, memory-mapping and cache size
+	if result == nil {
+		if err := p.pragmas(conn); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
 	// Set auth
 	if p.cfg.Auth != nil {
 		conn.SetAuthorizerHook(func(action sqlite3.SQAction, args [4]string) sqlite3.SQAuth {
@@ -303,13 +748,64 @@ func (p *Pool) new() (SQConnection, error) {
 
 	// Check for errors
 	if result != nil {
+		conn.Close()
 		return nil, result
 	}
 
+	// Call the connect function
+	if p.cfg.OnConnect != nil {
+		if err := p.cfg.OnConnect(conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
 	// Success
 	return conn, nil
 }
 
+// pragmas sets temporary storage and memory-mapping for the connection, and
+// the cache size for each schema, which must be attached first
+func (p *Pool) pragmas(conn *Conn) error {
+	if err := p.cfg.checkPragmas(); err != nil {
+		return err
+	}
+	if p.cfg.TempStore != TempStoreDefault {
+		if err := conn.SetTempStore(p.cfg.TempStore); err != nil {
+			return err
+		}
+	}
+	if p.cfg.MmapSize != 0 {
+		if err := conn.SetMmapSize(p.cfg.MmapSize); err != nil {
+			return err
+		}
+	}
+	if p.cfg.CacheSizeKB != 0 {
+		for schema := range p.cfg.Schemas {
+			if err := conn.SetCacheSizeKB(strings.TrimSpace(schema), p.cfg.CacheSizeKB); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkPragmas returns an error if the temporary storage, memory-mapping
+// or cache size are out of range
+func (cfg PoolConfig) checkPragmas() error {
+	var result error
+	if cfg.TempStore > TempStoreMemory {
+		result = multierror.Append(result, ErrBadParameter.Withf("TempStore %v", cfg.TempStore))
+	}
+	if cfg.MmapSize < 0 {
+		result = multierror.Append(result, ErrBadParameter.Withf("MmapSize %v", cfg.MmapSize))
+	}
+	if cfg.CacheSizeKB < 0 {
+		result = multierror.Append(result, ErrBadParameter.Withf("CacheSizeKB %v", cfg.CacheSizeKB))
+	}
+	return result
+}
+
 // err will pass an error to a channel unless channel is blocked
 func (p *Pool) err(err error) {
 	if p.errs != nil {
@@ -2,12 +2,23 @@ package sqlite3_test
 
 import (
 	"context"
+	"errors"
 	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	// Packages
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+
 	// Namespace Imports
+	. "github.com/djthorpe/go-errors"
 	. "github.com/mutablelogic/go-sqlite"
 	. "github.com/mutablelogic/go-sqlite/pkg/lang"
 	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
@@ -70,6 +81,388 @@ func Test_Pool_002(t *testing.T) {
 	cancel()
 }
 
+func Test_Pool_003(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := OpenPool(NewConfig().WithLimit(sqlite3.SQLITE_LIMIT_SQL_LENGTH, 100), errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Check limit is set
+	if v := conn.(*Conn).GetLimit(sqlite3.SQLITE_LIMIT_SQL_LENGTH); v != 100 {
+		t.Error("Unexpected limit", v)
+	}
+
+	// Short statement is accepted, long statement is rejected
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		_, err := txn.Query(Q("SELECT 1"))
+		return err
+	}); err != nil {
+		t.Error(err)
+	}
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		_, err := txn.Query(Q("SELECT ", V(strings.Repeat("x", 100))))
+		return err
+	}); err == nil {
+		t.Error("Expected error for long statement")
+	} else {
+		t.Log(err)
+	}
+}
+
+func Test_Pool_004(t *testing.T) {
+	var lock sync.Mutex
+	seen := make(map[*Conn]bool)
+
+	// Record each new connection
+	errs, cancel := handleErrors(t)
+	pool, err := OpenPool(NewConfig().WithMaxConnections(50).WithConnect(func(conn *Conn) error {
+		lock.Lock()
+		defer lock.Unlock()
+		if seen[conn] {
+			t.Error("Connect called twice for", conn)
+		}
+		seen[conn] = true
+		return nil
+	}), errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Check out connections concurrently, so new connections are created
+	var wg sync.WaitGroup
+	var got sync.Map
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if conn := pool.Get(); conn != nil {
+				got.Store(conn.(*Conn), true)
+				<-time.After(randomDuration(10 * time.Millisecond))
+				pool.Put(conn)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every connection handed out must have been passed to the connect function
+	got.Range(func(key, _ interface{}) bool {
+		lock.Lock()
+		defer lock.Unlock()
+		if !seen[key.(*Conn)] {
+			t.Error("Connect not called for", key)
+		}
+		return true
+	})
+	if len(seen) < 2 {
+		t.Error("Expected more than one connection, got", len(seen))
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Error(err)
+	}
+	cancel()
+}
+
+func Test_Pool_005(t *testing.T) {
+	// Connect function returning an error fails the pool
+	_, err := OpenPool(NewConfig().WithConnect(func(conn *Conn) error {
+		return ErrInternalAppError
+	}), nil)
+	if err == nil {
+		t.Error("Expected error from connect function")
+	}
+}
+
+func Test_Pool_006(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := OpenPool(NewConfig().WithQueryTimeout(10*time.Millisecond), errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	conn := pool.Get()
+	if conn == nil {
+		t.Fatal("Unexpected nil connection")
+	}
+	defer pool.Put(conn)
+
+	// A runaway query is aborted with a timeout rather than a cancellation
+	loop := Q("WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM c) SELECT MAX(x) FROM c")
+	err = conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		_, err := txn.Query(loop)
+		return err
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		t.Error("Expected context.DeadlineExceeded, got", err)
+	}
+
+	// Statements executed outside of a transaction are aborted too
+	if err := conn.Exec(loop, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected context.DeadlineExceeded, got", err)
+	}
+
+	// Connection can be used again
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		r, err := txn.Query(Q("SELECT 1"))
+		if err != nil {
+			return err
+		}
+		if row := r.Next(); len(row) != 1 || row[0] != int64(1) {
+			t.Error("Unexpected row", row)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Pool_007(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	missing := filepath.Join(tmpdir, "missing.sqlite")
+
+	// Default configuration is valid
+	if err := ValidateConfig(NewConfig()); err != nil {
+		t.Error(err)
+	}
+
+	// Invalid schema name
+	if err := ValidateConfig(NewConfig().WithSchema("1bad", ":memory:")); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+
+	// Missing file with and without create
+	if err := ValidateConfig(NewConfig().WithCreate(false).WithSchema("test", missing)); !errors.Is(err, ErrNotFound) {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+	if err := ValidateConfig(NewConfig().WithSchema("test", missing)); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Error("Expected file not to be created")
+	}
+
+	// Duplicate paths
+	if err := ValidateConfig(NewConfig().WithSchema("test", missing).WithSchema("other", missing)); !errors.Is(err, ErrDuplicateEntry) {
+		t.Error("Expected ErrDuplicateEntry, got", err)
+	}
+}
+
+func Test_Pool_008(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	path := filepath.Join(tmpdir, "test.sqlite")
+
+	shared := SQFlag(sqlite3.SQLITE_OPEN_SHAREDCACHE)
+	private := SQFlag(sqlite3.SQLITE_OPEN_PRIVATECACHE)
+	nomutex := SQFlag(sqlite3.SQLITE_OPEN_NOMUTEX)
+	fullmutex := SQFlag(sqlite3.SQLITE_OPEN_FULLMUTEX)
+	tests := []struct {
+		cache     CacheMode
+		threading ThreadingMode
+		set       SQFlag
+		unset     SQFlag
+	}{
+		{CacheDefault, ThreadingDefault, shared, private | nomutex | fullmutex},
+		{CacheShared, ThreadingMultiThread, shared | nomutex, private | fullmutex},
+		{CachePrivate, ThreadingMultiThread, private | nomutex, shared | fullmutex},
+		{CachePrivate, ThreadingSerialized, private | fullmutex, shared | nomutex},
+	}
+	for i, test := range tests {
+		cfg := NewConfig().WithSchema(DefaultSchema, path).WithCacheMode(test.cache).WithThreadingMode(test.threading)
+		flags, err := cfg.OpenFlags()
+		if err != nil {
+			t.Error(i, err)
+			continue
+		} else if flags&test.set != test.set || flags&test.unset != 0 {
+			t.Errorf("%d: Unexpected flags %v", i, sqlite3.OpenFlags(flags))
+		}
+
+		// Check the flags used to open connections
+		pool, err := OpenPool(cfg, nil)
+		if err != nil {
+			t.Error(i, err)
+			continue
+		}
+		if conn := pool.Get(); conn == nil {
+			t.Error(i, "Unexpected nil connection")
+		} else if f := conn.(*Conn).Flags(); f&test.set != test.set || f&test.unset != 0 {
+			t.Errorf("%d: Unexpected connection flags %v", i, sqlite3.OpenFlags(f))
+		} else {
+			pool.Put(conn)
+		}
+		pool.Close()
+	}
+
+	// Incompatible combinations
+	if _, err := NewConfig().WithCacheMode(CachePrivate).OpenFlags(); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter for private cache with in-memory database, got", err)
+	}
+	cfg := NewConfig().WithSchema(DefaultSchema, path)
+	cfg.Flags = DefaultFlags | nomutex | fullmutex
+	if _, err := cfg.OpenFlags(); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter for conflicting flags, got", err)
+	}
+	if _, err := OpenPool(cfg, nil); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter from OpenPool, got", err)
+	}
+	if _, err := NewConfig().WithThreadingMode(ThreadingMode(99)).OpenFlags(); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter for invalid mode, got", err)
+	}
+}
+
+func Test_Pool_009(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	path := filepath.Join(tmpdir, "ref.sqlite")
+
+	// Create a reference database with a row
+	conn, err := OpenPath(path, DefaultFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("CREATE TABLE test (a TEXT); INSERT INTO test VALUES ('hello')"), nil); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	// Attach the reference database read-only
+	cfg := NewConfig().WithSchema("ref", "file:"+path+"?mode=ro")
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := OpenPool(cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	c := pool.Get()
+	if c == nil {
+		t.Fatal("Unexpected nil connection")
+	}
+	defer pool.Put(c)
+	if !c.(*Conn).Flags().Is(SQFlag(sqlite3.SQLITE_OPEN_URI)) {
+		t.Error("Expected SQLITE_OPEN_URI flag")
+	}
+
+	// Reads succeed and writes fail
+	if err := c.Do(context.Background(), 0, func(txn SQTransaction) error {
+		r, err := txn.Query(S(N("test").WithSchema("ref")))
+		if err != nil {
+			return err
+		}
+		if row := r.Next(); len(row) != 1 || row[0] != "hello" {
+			t.Error("Unexpected row", row)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+	if err := c.Do(context.Background(), 0, func(txn SQTransaction) error {
+		_, err := txn.Query(N("test").WithSchema("ref").Insert("a"), "world")
+		return err
+	}); err == nil {
+		t.Error("Expected write to read-only database to fail")
+	}
+
+	// URI filenames are not supported for the main schema
+	if err := ValidateConfig(NewConfig().WithSchema(DefaultSchema, "file:"+path+"?mode=ro")); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+}
+
+func Test_Pool_010(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	// Create a primary and two replicas, with the name in each
+	cfg := NewConfig()
+	for _, name := range []string{"primary", "replica1", "replica2"} {
+		path := filepath.Join(tmpdir, name+".sqlite")
+		conn, err := OpenPath(path, DefaultFlags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.Exec(Q("CREATE TABLE test (name TEXT); INSERT INTO test VALUES ('"+name+"')"), nil); err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+		if name == "primary" {
+			cfg = cfg.WithSchema(DefaultSchema, path)
+		} else {
+			cfg = cfg.WithReplica(path)
+		}
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := OpenPool(cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	// Read-only connections are opened against each replica in turn
+	name := func(conn SQConnection) string {
+		var name string
+		if err := conn.Exec(Q("SELECT name FROM test"), func(row, _ []string) bool {
+			name = row[0]
+			return false
+		}); err != nil {
+			t.Error(err)
+		}
+		return name
+	}
+	a, b := pool.GetReadOnly(), pool.GetReadOnly()
+	if a == nil || b == nil {
+		t.Fatal("Unexpected nil connection")
+	}
+	defer pool.Put(a)
+	defer pool.Put(b)
+	if na, nb := name(a), name(b); na == nb || !strings.HasPrefix(na, "replica") || !strings.HasPrefix(nb, "replica") {
+		t.Error("Unexpected replicas", na, nb)
+	}
+
+	// Writes to a replica fail, and writes use the primary
+	if err := a.Exec(Q("INSERT INTO test VALUES ('write')"), nil); err == nil {
+		t.Error("Expected write to a replica to fail")
+	}
+	c := pool.Get()
+	if c == nil {
+		t.Fatal("Unexpected nil connection")
+	}
+	defer pool.Put(c)
+	if n := name(c); n != "primary" {
+		t.Error("Unexpected name", n)
+	} else if err := c.Exec(Q("INSERT INTO test VALUES ('write')"), nil); err != nil {
+		t.Error(err)
+	}
+	if n := pool.Cur(); n != 3 {
+		t.Error("Unexpected number of connections", n)
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
@@ -99,3 +492,347 @@ func handleErrors(t *testing.T) (chan<- error, context.CancelFunc) {
 	}()
 	return errs, func() { cancel(); wg.Wait() }
 }
+
+func Test_Pool_011(t *testing.T) {
+	var n int32
+	cfg := NewConfig().WithMaxConnections(10).WithConnect(func(*Conn) error {
+		atomic.AddInt32(&n, 1)
+		return nil
+	})
+
+	// Connections are opened with the pool, up to the maximum
+	for _, test := range []struct{ warmup, expected int32 }{{0, 1}, {4, 4}, {20, 10}} {
+		atomic.StoreInt32(&n, 0)
+		pool, err := OpenPool(cfg.WithWarmUp(int(test.warmup)), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := atomic.LoadInt32(&n); v != test.expected {
+			t.Errorf("warmup=%d: Expected %d connections opened, got %d", test.warmup, test.expected, v)
+		} else if pool.Cur() != 0 {
+			t.Errorf("warmup=%d: Expected no connections in use, got %d", test.warmup, pool.Cur())
+		}
+
+		// Getting the warm-up connections does not open any more
+		conns := []SQConnection{}
+		for i := int32(0); i < test.expected; i++ {
+			if conn := pool.Get(); conn == nil {
+				t.Fatal("Unexpected nil connection")
+			} else {
+				conns = append(conns, conn)
+			}
+		}
+		if v := atomic.LoadInt32(&n); v != test.expected {
+			t.Errorf("warmup=%d: Expected %d connections opened, got %d", test.warmup, test.expected, v)
+		} else if pool.Cur() != int(test.expected) {
+			t.Errorf("warmup=%d: Expected %d connections in use, got %d", test.warmup, test.expected, pool.Cur())
+		}
+		for _, conn := range conns {
+			pool.Put(conn)
+		}
+		if err := pool.Close(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// An error opening any connection is returned
+	atomic.StoreInt32(&n, 0)
+	if _, err := OpenPool(cfg.WithWarmUp(4).WithConnect(func(*Conn) error {
+		if atomic.AddInt32(&n, 1) == 3 {
+			return ErrUnexpectedResponse
+		}
+		return nil
+	}), nil); !errors.Is(err, ErrUnexpectedResponse) {
+		t.Error("Expected ErrUnexpectedResponse, got", err)
+	}
+}
+
+func Test_Pool_012(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+
+	// The connection is returned when the function succeeds
+	if err := pool.WithConn(context.Background(), func(conn SQConnection) error {
+		if pool.Cur() != 1 {
+			t.Error("Expected one connection in use, got", pool.Cur())
+		}
+		return conn.Exec(Q("SELECT NULL"), nil)
+	}); err != nil {
+		t.Error(err)
+	} else if pool.Cur() != 0 {
+		t.Error("Expected connection to be returned, got", pool.Cur())
+	}
+
+	// The error is propagated and the connection is returned
+	if err := pool.WithConn(context.Background(), func(conn SQConnection) error {
+		return ErrNotFound
+	}); !errors.Is(err, ErrNotFound) {
+		t.Error("Expected ErrNotFound, got", err)
+	} else if pool.Cur() != 0 {
+		t.Error("Expected connection to be returned, got", pool.Cur())
+	}
+
+	// The connection is returned when the function panics
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Expected panic")
+			}
+		}()
+		pool.WithConn(context.Background(), func(conn SQConnection) error {
+			panic("test")
+		})
+	}()
+	if pool.Cur() != 0 {
+		t.Error("Expected connection to be returned, got", pool.Cur())
+	}
+
+	// A cancelled context returns an error without getting a connection
+	ctx, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	if err := pool.WithConn(ctx, func(conn SQConnection) error {
+		t.Error("Unexpected call")
+		return nil
+	}); !errors.Is(err, context.Canceled) {
+		t.Error("Expected context.Canceled, got", err)
+	}
+
+	// No connections are available once the pool is closed
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.WithConn(context.Background(), func(conn SQConnection) error {
+		t.Error("Unexpected call")
+		return nil
+	}); !errors.Is(err, ErrNoConnection) {
+		t.Error("Expected ErrNoConnection, got", err)
+	}
+}
+
+func Test_Pool_013(t *testing.T) {
+	for _, enable := range []bool{true, false} {
+		errs, cancel := handleErrors(t)
+		pool, err := OpenPool(NewConfig().WithForeignKeys(enable), errs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Insert a child row without a parent row
+		conn := pool.Get()
+		if err := conn.Exec(Q("CREATE TABLE parent (id INTEGER PRIMARY KEY); CREATE TABLE child (parent INTEGER REFERENCES parent (id))"), nil); err != nil {
+			t.Fatal(err)
+		}
+		err = conn.Exec(Q("INSERT INTO child VALUES (1)"), nil)
+		var code sqlite3.SQError
+		if enable && (!errors.As(err, &code) || code&0xFF != sqlite3.SQLITE_CONSTRAINT) {
+			t.Error("Expected SQLITE_CONSTRAINT with foreign keys enabled, got", err)
+		} else if !enable && err != nil {
+			t.Error("Unexpected error with foreign keys disabled:", err)
+		}
+		if v, err := conn.(*Conn).ForeignKeyConstraints(); err != nil {
+			t.Error(err)
+		} else if v != enable {
+			t.Error("Unexpected foreign key enforcement", v)
+		}
+
+		pool.Put(conn)
+		pool.Close()
+		cancel()
+	}
+}
+
+func Test_Pool_014(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	// Connections use a private cache, as a shared cache locks tables
+	errs, cancel := handleErrors(t)
+	pool, err := OpenPool(NewConfig().WithCacheMode(CachePrivate).WithSchema(DefaultSchema, filepath.Join(tmpdir, "test.sqlite")), errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Create a table in WAL mode
+	conn := pool.Get()
+	defer pool.Put(conn)
+	if err := conn.Exec(Q("PRAGMA journal_mode=WAL; CREATE TABLE test (a INTEGER); INSERT INTO test VALUES (1)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	count := func(txn SQTransaction) int64 {
+		if r, err := txn.Query(Q("SELECT COUNT(*) FROM test")); err != nil {
+			t.Error(err)
+		} else if row := r.Next(); len(row) == 1 {
+			return row[0].(int64)
+		}
+		return -1
+	}
+
+	// Start a read transaction which waits until released
+	reader1, reader2 := pool.Get(), pool.Get()
+	defer pool.Put(reader1)
+	defer pool.Put(reader2)
+	var active int32
+	started, release := make(chan struct{}), make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := reader1.DoRead(context.Background(), func(txn SQTransaction) error {
+			atomic.AddInt32(&active, 1)
+			defer atomic.AddInt32(&active, -1)
+			count(txn)
+			close(started)
+			<-release
+
+			// The read transaction sees a snapshot from before the write
+			if n := count(txn); n != 1 {
+				t.Error("Expected one row in snapshot, got", n)
+			}
+			return nil
+		}); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-started
+
+	// A second read transaction and a write run while the first is open
+	if err := reader2.DoRead(context.Background(), func(txn SQTransaction) error {
+		if n := atomic.LoadInt32(&active); n != 1 {
+			t.Error("Expected concurrent read transaction, got", n)
+		}
+		if n := count(txn); n != 1 {
+			t.Error("Expected one row, got", n)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		_, err := txn.Query(Q("INSERT INTO test VALUES (2)"))
+		return err
+	}); err != nil {
+		t.Error(err)
+	}
+	close(release)
+	wg.Wait()
+
+	// Writes fail in a read transaction, and the connection can write afterwards
+	if err := reader2.DoRead(context.Background(), func(txn SQTransaction) error {
+		_, err := txn.Query(Q("INSERT INTO test VALUES (3)"))
+		return err
+	}); err == nil {
+		t.Error("Expected error writing in a read transaction")
+	}
+	if err := reader2.Do(context.Background(), 0, func(txn SQTransaction) error {
+		_, err := txn.Query(Q("INSERT INTO test VALUES (4)"))
+		return err
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Pool_015(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	defer cancel()
+
+	// Open a pool with a file-based database, as memory-mapping is not used
+	// for in-memory databases
+	cfg := NewConfig().
+		WithSchema(DefaultSchema, filepath.Join(t.TempDir(), "test.sqlite")).
+		WithSchema("aux", filepath.Join(t.TempDir(), "aux.sqlite")).
+		WithTempStore(TempStoreMemory).
+		WithMmapSize(1 << 20).
+		WithCacheSizeKB(4096)
+	pool, err := OpenPool(cfg, errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	conn := pool.Get()
+	defer pool.Put(conn)
+	if v, err := conn.(*Conn).TempStore(); err != nil {
+		t.Error(err)
+	} else if v != TempStoreMemory {
+		t.Error("Unexpected temp store", v)
+	}
+	if v, err := conn.(*Conn).MmapSize(); err != nil {
+		t.Error(err)
+	} else if v != 1<<20 {
+		t.Error("Unexpected mmap size", v)
+	}
+	for _, schema := range []string{DefaultSchema, "aux"} {
+		if v, err := conn.(*Conn).CacheSizeKB(schema); err != nil {
+			t.Error(err)
+		} else if v != 4096 {
+			t.Error("Unexpected cache size", schema, v)
+		}
+	}
+
+	// Values out of range are rejected
+	for _, cfg := range []PoolConfig{
+		NewConfig().WithTempStore(TempStoreMemory + 1),
+		func() PoolConfig { cfg := NewConfig(); cfg.MmapSize = -1; return cfg }(),
+		func() PoolConfig { cfg := NewConfig(); cfg.CacheSizeKB = -1; return cfg }(),
+	} {
+		if err := ValidateConfig(cfg); !errors.Is(err, ErrBadParameter) {
+			t.Error("Expected ErrBadParameter, got", err)
+		} else if _, err := OpenPool(cfg, errs); err == nil {
+			t.Error("Expected an error opening the pool")
+		}
+	}
+}
+
+func Test_Pool_016(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	defer cancel()
+
+	// Attach schemas which are not in alphabetical order
+	cfg := NewConfig()
+	for _, schema := range []string{"zeta", "alpha", "beta"} {
+		cfg = cfg.WithSchema(schema, filepath.Join(t.TempDir(), schema+".sqlite"))
+	}
+	pool, err := OpenPool(cfg, errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// The temp schema is listed once a temporary table exists
+	if err := conn.Exec(Q("CREATE TEMP TABLE t (a)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if schemas := conn.Schemas(); !reflect.DeepEqual(schemas, []string{"main", "alpha", "beta", "zeta", "temp"}) {
+		t.Error("Unexpected schemas", schemas)
+	}
+
+	// Tables are in alphabetical order
+	for _, table := range []string{"c", "a", "b"} {
+		if err := conn.Exec(N(table).WithSchema("beta").CreateTable(C("a")), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tables := conn.Tables("beta"); !reflect.DeepEqual(tables, []string{"a", "b", "c"}) {
+		t.Error("Unexpected tables", tables)
+	}
+
+	// Modules are in alphabetical order
+	modules := conn.Modules("fts")
+	if len(modules) == 0 {
+		t.Error("Expected fts modules")
+	} else if !sort.StringsAreSorted(modules) {
+		t.Error("Unexpected module order", modules)
+	}
+}
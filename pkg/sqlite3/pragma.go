@@ -0,0 +1,106 @@
+package sqlite3
+
+import (
+	"strconv"
+
+	// Import namespaces
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// TempStore returns where temporary tables and indexes are stored
+func (this *Conn) TempStore() (TempStore, error) {
+	v, err := this.pragmaInt(Q("PRAGMA temp_store"))
+	if err != nil {
+		return TempStoreDefault, err
+	}
+	return TempStore(v), nil
+}
+
+// SetTempStore sets where temporary tables and indexes are stored
+func (this *Conn) SetTempStore(store TempStore) error {
+	if store > TempStoreMemory {
+		return ErrBadParameter.Withf("SetTempStore: %v", store)
+	}
+	return this.Exec(Q("PRAGMA temp_store=", V(uint(store))), nil)
+}
+
+// MmapSize returns the maximum number of bytes of the main database file
+// which are memory-mapped. In-memory databases always return zero
+func (this *Conn) MmapSize() (int64, error) {
+	return this.pragmaInt(Q("PRAGMA mmap_size"))
+}
+
+// SetMmapSize sets the maximum number of bytes of each database file which
+// are memory-mapped, or zero to disable memory-mapping. The size is limited
+// by the maximum set when SQLite is compiled
+func (this *Conn) SetMmapSize(n int64) error {
+	if n < 0 {
+		return ErrBadParameter.Withf("SetMmapSize: %v", n)
+	}
+	return this.Exec(Q("PRAGMA mmap_size=", V(n)), nil)
+}
+
+// CacheSizeKB returns the page cache size for a schema in kibibytes. When
+// the cache size is set as a number of pages, it is converted using the
+// page size of the schema
+func (this *Conn) CacheSizeKB(schema string) (int, error) {
+	if schema == "" {
+		return this.CacheSizeKB(DefaultSchema)
+	}
+	n, err := this.pragmaInt(Q("PRAGMA ", N(schema), ".cache_size"))
+	if err != nil {
+		return 0, err
+	} else if n < 0 {
+		return int(-n), nil
+	}
+	size, err := this.pragmaInt(Q("PRAGMA ", N(schema), ".page_size"))
+	if err != nil {
+		return 0, err
+	}
+	return int(n * size / 1024), nil
+}
+
+// SetCacheSizeKB sets the page cache size for a schema in kibibytes
+func (this *Conn) SetCacheSizeKB(schema string, n int) error {
+	if schema == "" {
+		return this.SetCacheSizeKB(DefaultSchema, n)
+	} else if n <= 0 {
+		return ErrBadParameter.Withf("SetCacheSizeKB: %v", n)
+	}
+	return this.Exec(Q("PRAGMA ", N(schema), ".cache_size=", V(-n)), nil)
+}
+
+// RecursiveTriggers returns true if triggers can fire other triggers,
+// including themselves
+func (this *Conn) RecursiveTriggers() (bool, error) {
+	v, err := this.pragmaInt(Q("PRAGMA recursive_triggers"))
+	return v != 0, err
+}
+
+// SetRecursiveTriggers allows triggers to fire other triggers, including
+// themselves. Rows deleted by REPLACE conflict resolution also fire delete
+// triggers when enabled
+func (this *Conn) SetRecursiveTriggers(enable bool) error {
+	return this.Exec(Q("PRAGMA recursive_triggers=", V(enable)), nil)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// pragmaInt returns the integer value returned by a pragma
+func (this *Conn) pragmaInt(st SQStatement) (int64, error) {
+	var result int64
+	var err error
+	if err := this.Exec(st, func(row, _ []string) bool {
+		result, err = strconv.ParseInt(row[0], 10, 64)
+		return false
+	}); err != nil {
+		return 0, err
+	}
+	return result, err
+}
@@ -0,0 +1,104 @@
+package sqlite3
+
+import (
+	"errors"
+	"io"
+
+	// Packages
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// PreparedStatement is a statement which is prepared once on a connection,
+// and can then be bound and executed many times
+type PreparedStatement struct {
+	conn *Conn
+	st   *sqlite3.StatementEx
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Prepare a statement on the connection, which can be bound and executed
+// repeatedly without preparing again. Unlike Query, slice arguments are not
+// expanded for "IN (?)" parameters. The statement needs to be closed when no
+// longer required, before the connection is closed.
+func (conn *Conn) Prepare(st SQStatement) (*PreparedStatement, error) {
+	if st == nil {
+		return nil, ErrBadParameter.With("Prepare")
+	}
+
+	// The statement is marked as cached so that closing results does
+	// not release it
+	s, err := conn.ConnEx.PrepareCached(st.Query(), true)
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedStatement{conn, s}, nil
+}
+
+// Close releases the prepared statement
+func (p *PreparedStatement) Close() error {
+	return p.st.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (p *PreparedStatement) String() string {
+	return "<prepared " + p.st.String() + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Bind parameters to the statement, replacing any previously bound parameters.
+// If the statement text contains more than one statement, the parameters are
+// bound to the first statement
+func (p *PreparedStatement) Bind(v ...interface{}) error {
+	return p.st.Bind(0, v...)
+}
+
+// Exec executes all the statements with the bound parameters, and returns the
+// results of the last statement executed, which can be used to retrieve
+// LastInsertId and RowsAffected
+func (p *PreparedStatement) Exec() (SQResults, error) {
+	r, err := p.Query()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if err := r.(*Results).NextQuery(); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, p.conn.queryError(err)
+		}
+	}
+
+	// Return success
+	return r, nil
+}
+
+// Query executes the first statement with the bound parameters, and returns
+// the results for reading rows. Use NextQuery on the results to execute any
+// further statements
+func (p *PreparedStatement) Query() (SQResults, error) {
+	r := NewResults(p.st)
+	r.loc = p.conn.loc
+	if err := r.NextQuery(); err != nil {
+		return nil, p.conn.queryError(err)
+	}
+	return r, nil
+}
+
+// Reset the statement so it releases any locks on the database, and
+// clear any bound parameters
+func (p *PreparedStatement) Reset() error {
+	return p.st.Reset()
+}
@@ -0,0 +1,152 @@
+package sqlite3_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Prepared_001(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER"), C("b")), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Prepare an insert and execute it many times
+	insert, err := conn.Prepare(N("test").Insert("a", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer insert.Close()
+	for i := 1; i <= 100; i++ {
+		if err := insert.Bind(i, fmt.Sprint("row ", i)); err != nil {
+			t.Fatal(err)
+		}
+		if r, err := insert.Exec(); err != nil {
+			t.Fatal(err)
+		} else if r.RowsAffected() != 1 || r.LastInsertId() != int64(i) {
+			t.Error("Unexpected results", r.RowsAffected(), r.LastInsertId())
+		}
+	}
+
+	// Too many parameters is an error
+	if err := insert.Bind(1, 2, 3); err == nil {
+		t.Error("Expected error binding too many parameters")
+	}
+
+	// Prepare a select and query it with different parameters
+	sel, err := conn.Prepare(S(N("test")).To(N("b")).Where(Q("a=", P)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sel.Close()
+	for _, i := range []int{1, 50, 100} {
+		if err := sel.Bind(i); err != nil {
+			t.Fatal(err)
+		}
+		r, err := sel.Query()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if row := r.Next(); len(row) != 1 || row[0] != fmt.Sprint("row ", i) {
+			t.Error("Unexpected row", row)
+		}
+		if row := r.Next(); row != nil {
+			t.Error("Unexpected row", row)
+		}
+	}
+
+	// Reset clears the parameters
+	if err := sel.Reset(); err != nil {
+		t.Error(err)
+	} else if r, err := sel.Query(); err != nil {
+		t.Error(err)
+	} else if row := r.Next(); row != nil {
+		t.Error("Unexpected row after reset", row)
+	}
+
+	// Prepared statements can be executed within a transaction
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		for i := 101; i <= 110; i++ {
+			if err := insert.Bind(i, nil); err != nil {
+				return err
+			}
+			if _, err := insert.Exec(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+	if n := conn.Count("main", "test"); n != 110 {
+		t.Error("Expected 110 rows, got", n)
+	}
+}
+
+func Benchmark_Prepared_001(b *testing.B) {
+	conn, err := New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER"), C("b")), nil); err != nil {
+		b.Fatal(err)
+	}
+	insert, err := conn.Prepare(N("test").Insert("a", "b"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer insert.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	if err := conn.Do(context.Background(), 0, func(SQTransaction) error {
+		for i := 0; i < b.N; i++ {
+			if err := insert.Bind(i, "value"); err != nil {
+				return err
+			}
+			if _, err := insert.Exec(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Prepared_002(b *testing.B) {
+	conn, err := New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER"), C("b")), nil); err != nil {
+		b.Fatal(err)
+	}
+	insert := N("test").Insert("a", "b")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		for i := 0; i < b.N; i++ {
+			if _, err := txn.Query(insert, i, "value"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,70 @@
+package sqlite3
+
+import (
+	"regexp"
+	"sync"
+
+	// Modules
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// regexpCache holds compiled patterns for the regexp function
+type regexpCache struct {
+	sync.Mutex
+	re map[string]*regexp.Regexp
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum number of compiled patterns cached for each connection
+	regexpCacheSize = 32
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// EnableRegexp registers the regexp(pattern, text) function on the connection,
+// so that the REGEXP operator can be used in statements (ie, "col REGEXP ?").
+// Patterns use Go regular expression syntax, and an invalid pattern returns an
+// error when the statement is executed. A NULL argument returns NULL.
+func (conn *Conn) EnableRegexp() error {
+	cache := &regexpCache{re: make(map[string]*regexp.Regexp, regexpCacheSize)}
+	return conn.ConnEx.CreateScalarFunction("regexp", 2, true, func(ctx *sqlite3.Context, args []*sqlite3.Value) {
+		if args[0].Type() == sqlite3.SQLITE_NULL || args[1].Type() == sqlite3.SQLITE_NULL {
+			ctx.ResultNull()
+		} else if re, err := cache.compile(args[0].Text()); err != nil {
+			ctx.Err(err.Error())
+		} else if re.MatchString(args[1].Text()) {
+			ctx.ResultInt32(1)
+		} else {
+			ctx.ResultInt32(0)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// compile returns a compiled pattern from the cache, or compiles and caches
+// the pattern. When the cache is full, it is emptied.
+func (cache *regexpCache) compile(pattern string) (*regexp.Regexp, error) {
+	cache.Lock()
+	defer cache.Unlock()
+	if re, exists := cache.re[pattern]; exists {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(cache.re) >= regexpCacheSize {
+		cache.re = make(map[string]*regexp.Regexp, regexpCacheSize)
+	}
+	cache.re[pattern] = re
+	return re, nil
+}
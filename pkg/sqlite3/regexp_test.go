@@ -0,0 +1,84 @@
+package sqlite3_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Regexp_001(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// REGEXP is an error until the function is registered
+	if err := conn.Exec(Q("SELECT 'a' REGEXP 'a'"), nil); err == nil {
+		t.Error("Expected error before EnableRegexp")
+	}
+	if err := conn.(*Conn).EnableRegexp(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a table with rows
+	if err := conn.Exec(N("test").CreateTable(C("a")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO test (a) VALUES ('apple'), ('banana'), ('cherry'), (NULL)"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Match rows with different patterns, more patterns than are cached
+	var tests = []struct {
+		pattern  string
+		expected []interface{}
+	}{
+		{"^a", []interface{}{"apple"}},
+		{"an+a$", []interface{}{"banana"}},
+		{"^a", []interface{}{"apple"}},
+		{"e", []interface{}{"apple", "cherry"}},
+		{"^z", nil},
+	}
+	for i := 0; i < 40; i++ {
+		tests = append(tests, struct {
+			pattern  string
+			expected []interface{}
+		}{fmt.Sprintf("a{0,%d}n", i+1), []interface{}{"banana"}})
+	}
+	tests = append(tests, tests[0], tests[1])
+	for _, test := range tests {
+		var rows []interface{}
+		if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+			r, err := txn.Query(Q("SELECT a FROM test WHERE a REGEXP ? ORDER BY a"), test.pattern)
+			if err != nil {
+				return err
+			}
+			for row := r.Next(); row != nil; row = r.Next() {
+				rows = append(rows, row[0])
+			}
+			return nil
+		}); err != nil {
+			t.Error(err)
+		} else if !reflect.DeepEqual(rows, test.expected) {
+			t.Errorf("%q: Expected %q, got %q", test.pattern, test.expected, rows)
+		}
+	}
+
+	// An invalid pattern is an error
+	if err := conn.Exec(Q("SELECT a FROM test WHERE a REGEXP '('"), nil); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
@@ -1,15 +1,21 @@
 package sqlite3
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
+	"time"
 
 	// Packages
 	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
 
 	// Namespace imports
+	. "github.com/djthorpe/go-errors"
 	. "github.com/mutablelogic/go-sqlite"
 	. "github.com/mutablelogic/go-sqlite/pkg/lang"
 )
@@ -21,9 +27,31 @@ import (
 type Results struct {
 	st      *sqlite3.StatementEx
 	results *sqlite3.Results
-	n       uint // next statement to execute
+	n       uint           // next statement to execute
+	loc     *time.Location // timezone for time values
+	limit   int            // maximum number of rows, or zero for no limit
+	count   int            // number of rows read
+	done    bool           // true when checked for rows beyond the limit
+	trunc   bool           // true when there were rows beyond the limit
 }
 
+// Blob is a BLOB value returned by NextMap, which is encoded in JSON as
+// a base64 data URI string
+type Blob []byte
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// ErrRowLimit is returned by Err when results have more rows than
+	// the limit set by QueryLimited
+	ErrRowLimit = errors.New("row limit exceeded")
+)
+
+const (
+	blobPrefix = "data:application/octet-stream;base64,"
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
@@ -38,7 +66,7 @@ func (r *Results) Close() error {
 	if !r.st.Cached() {
 		return r.st.Close()
 	} else {
-		return r.st.Close()
+		return nil
 	}
 }
 
@@ -68,18 +96,90 @@ func (r *Results) NextQuery(v ...interface{}) error {
 	} else {
 		r.results = results
 		r.n++
+		r.count, r.done, r.trunc = 0, false, false
 		// TODO: Set Columns, etc.
 		return nil
 	}
 }
 
-// Return a row from the results, or return io.EOF if all results have been consumed
+// Return a row from the results, or return io.EOF if all results have been consumed.
+// Time values are converted to the location set on the connection. When a limit
+// is set, nil is returned once the limit is reached.
 func (r *Results) Next(t ...reflect.Type) []interface{} {
 	if r.results == nil {
 		return nil
+	}
+
+	// Check for a further row once the limit is reached, without returning it
+	if r.limit > 0 && r.count >= r.limit {
+		if !r.done {
+			r.done = true
+			r.trunc = r.results.Next() != nil
+		}
+		return nil
+	}
+
+	row := r.results.Next(t...)
+	if row == nil {
+		return nil
 	} else {
-		return r.results.Next(t...)
+		r.count++
+	}
+	if r.loc != nil {
+		for i, v := range row {
+			if v, ok := v.(time.Time); ok && !v.IsZero() {
+				row[i] = v.In(r.loc)
+			}
+		}
+	}
+	return row
+}
+
+// NextMap returns a row from the results as a map of column name to value, or
+// nil if all results have been consumed. NULL values are present in the map as
+// nil, and BLOB values are copied and returned as Blob.
+func (r *Results) NextMap() map[string]interface{} {
+	row := r.Next()
+	if row == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(row))
+	for i, v := range row {
+		result[r.results.ColumnName(i)] = jsonValue(v)
+	}
+	return result
+}
+
+// NextJSON returns a row from the results as a JSON object, with keys in column
+// order, or io.EOF if all results have been consumed. NULL values are encoded as
+// null, BLOB values as base64 data URI strings and times in RFC3339 format.
+func (r *Results) NextJSON() (json.RawMessage, error) {
+	row := r.Next()
+	if row == nil {
+		return nil, io.EOF
 	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, v := range row {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if key, err := json.Marshal(r.results.ColumnName(i)); err != nil {
+			return nil, err
+		} else {
+			buf.Write(key)
+		}
+		buf.WriteByte(':')
+		if value, err := json.Marshal(jsonValue(v)); err != nil {
+			return nil, err
+		} else {
+			buf.Write(value)
+		}
+	}
+	buf.WriteByte('}')
+
+	// Return success
+	return buf.Bytes(), nil
 }
 
 func (r *Results) ExpandedSQL() string {
@@ -120,6 +220,23 @@ func (r *Results) Columns() []SQColumn {
 	return cols
 }
 
+// Truncated returns true if the results of the current query had more rows
+// than the limit set by QueryLimited, once the rows up to the limit have
+// been read
+func (r *Results) Truncated() bool {
+	return r.trunc
+}
+
+// Err returns ErrRowLimit if the results of the current query had more rows
+// than the limit set by QueryLimited, once the rows up to the limit have
+// been read, or nil otherwise
+func (r *Results) Err() error {
+	if r.trunc {
+		return fmt.Errorf("%w: %d rows", ErrRowLimit, r.limit)
+	}
+	return nil
+}
+
 func (r *Results) ColumnSource(i int) (string, string, string) {
 	if r.results == nil {
 		return "", "", ""
@@ -130,3 +247,44 @@ func (r *Results) ColumnSource(i int) (string, string, string) {
 	}
 	return schema, table, name
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// JSON
+
+// MarshalJSON encodes the blob as a base64 data URI string
+func (b Blob) MarshalJSON() ([]byte, error) {
+	return json.Marshal(blobPrefix + base64.StdEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON decodes a base64 data URI string into a blob
+func (b *Blob) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(v, blobPrefix) {
+		return ErrBadParameter.Withf("Invalid blob: %q", v)
+	}
+	if data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, blobPrefix)); err != nil {
+		return err
+	} else {
+		*b = data
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// jsonValue returns a value which can be safely encoded as JSON, copying
+// any transient BLOB values
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []byte:
+		blob := make(Blob, len(v))
+		copy(blob, v)
+		return blob
+	default:
+		return v
+	}
+}
@@ -0,0 +1,168 @@
+package sqlite3_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Results_001(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Insert a row with a blob, a NULL and a timestamp
+	blob := []byte{0x00, 0x01, 0xFE, 0xFF}
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := conn.Exec(N("test").CreateTable(
+		C("a").WithType("INTEGER"), C("b").WithType("BLOB"), C("c").WithType("TEXT"), C("d").WithType("TIMESTAMP"),
+	), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var row map[string]interface{}
+	var raw json.RawMessage
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if _, err := txn.Query(N("test").Insert("a", "b", "c", "d"), 42, blob, nil, now); err != nil {
+			return err
+		}
+		r, err := txn.Query(S(N("test")).To(N("a"), N("b"), N("c"), N("d")))
+		if err != nil {
+			return err
+		}
+		row = r.NextMap()
+		if r.NextMap() != nil {
+			t.Error("Expected one row")
+		}
+		r, err = txn.Query(S(N("test")).To(N("a"), N("b"), N("c"), N("d")))
+		if err != nil {
+			return err
+		}
+		raw, err = r.NextJSON()
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check map
+	if v, exists := row["c"]; !exists || v != nil {
+		t.Error("Expected NULL value for c", row)
+	}
+	if v, ok := row["b"].(Blob); !ok || !bytes.Equal(v, blob) {
+		t.Error("Unexpected value for b", row)
+	}
+
+	// Check JSON, and round-trip it
+	t.Log(string(raw))
+	var result struct {
+		A int64     `json:"a"`
+		B Blob      `json:"b"`
+		C *string   `json:"c"`
+		D time.Time `json:"d"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.A != 42 {
+		t.Error("Unexpected value for a", result.A)
+	}
+	if !bytes.Equal(result.B, blob) {
+		t.Error("Unexpected value for b", result.B)
+	}
+	if result.C != nil {
+		t.Error("Unexpected value for c", result.C)
+	}
+	if !result.D.Equal(now) {
+		t.Error("Unexpected value for d", result.D)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		t.Fatal(err)
+	} else if v, exists := fields["c"]; !exists || string(v) != "null" {
+		t.Error("Expected null for c", string(raw))
+	}
+}
+
+func Test_Results_003(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		for i := 0; i < 10; i++ {
+			if _, err := txn.Query(N("test").Insert("a"), i); err != nil {
+				return err
+			}
+		}
+
+		// The limit must be positive
+		if _, err := txn.QueryLimited(S(N("test")), 0); err == nil {
+			t.Error("Expected an error")
+		}
+
+		// Rows are truncated at the limit, and the results report that
+		// there were further rows
+		r, err := txn.QueryLimited(S(N("test")), 5)
+		if err != nil {
+			return err
+		}
+		var n int
+		for row := r.Next(); row != nil; row = r.Next() {
+			n++
+		}
+		if n != 5 {
+			t.Error("Expected 5 rows, got", n)
+		}
+		if !r.Truncated() {
+			t.Error("Expected results to be truncated")
+		}
+		if err := r.Err(); !errors.Is(err, ErrRowLimit) {
+			t.Error("Expected ErrRowLimit, got", err)
+		}
+
+		// Results with no more rows than the limit are not truncated
+		for _, limit := range []int{10, 20} {
+			r, err := txn.QueryLimited(S(N("test")), limit)
+			if err != nil {
+				return err
+			}
+			var n int
+			for row := r.NextMap(); row != nil; row = r.NextMap() {
+				n++
+			}
+			if n != 10 {
+				t.Error("Expected 10 rows, got", n)
+			}
+			if r.Truncated() {
+				t.Error("Unexpected truncated results with limit", limit)
+			}
+			if err := r.Err(); err != nil {
+				t.Error(err)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
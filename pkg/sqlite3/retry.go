@@ -0,0 +1,75 @@
+package sqlite3
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	// Packages
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+
+	// Namespace imports
+	. "github.com/mutablelogic/go-sqlite"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// The initial and maximum delay between attempts in Retry
+	retryBackoffMin = 10 * time.Millisecond
+	retryBackoffMax = time.Second
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// IsBusy returns true if the error is caused by the database being busy or a
+// table being locked by another connection (SQLITE_BUSY or SQLITE_LOCKED,
+// including extended error codes), in which case the operation can be retried
+func IsBusy(err error) bool {
+	var code sqlite3.SQError
+	if !errors.As(err, &code) {
+		return false
+	}
+	switch code & 0xFF {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	default:
+		return false
+	}
+}
+
+// Retry calls fn up to attempts times, while it returns an error for which
+// IsBusy is true, waiting with exponential backoff between attempts. Any other
+// error is returned immediately, and the error from the last attempt is
+// returned if all attempts fail. Cancelling the context stops any further
+// attempts and returns the context error.
+func Retry(ctx context.Context, attempts int, fn func() error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	delay := retryBackoffMin
+	for i := 1; ; i++ {
+		err := fn()
+		if err == nil || i >= attempts || !IsBusy(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+			if delay *= 2; delay > retryBackoffMax {
+				delay = retryBackoffMax
+			}
+		}
+	}
+}
+
+// DoWithRetry performs a transaction as Do, and retries the transaction up
+// to attempts times while it fails because the database is busy or locked
+func (conn *Conn) DoWithRetry(ctx context.Context, attempts int, flag SQFlag, fn func(SQTransaction) error) error {
+	return Retry(ctx, attempts, func() error {
+		return conn.Do(ctx, flag, fn)
+	})
+}
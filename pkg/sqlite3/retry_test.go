@@ -0,0 +1,128 @@
+package sqlite3_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	// Packages
+	multierror "github.com/hashicorp/go-multierror"
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+
+	// Namespace Imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Retry_001(t *testing.T) {
+	var tests = []struct {
+		err  error
+		busy bool
+	}{
+		{nil, false},
+		{sqlite3.SQLITE_BUSY, true},
+		{sqlite3.SQLITE_LOCKED, true},
+		{sqlite3.SQLITE_BUSY | 2<<8, true},   // SQLITE_BUSY_SNAPSHOT
+		{sqlite3.SQLITE_LOCKED | 1<<8, true}, // SQLITE_LOCKED_SHAREDCACHE
+		{sqlite3.SQLITE_BUSY.With("test"), true},
+		{multierror.Append(nil, sqlite3.SQLITE_LOCKED), true},
+		{sqlite3.SQLITE_CONSTRAINT, false},
+		{ErrBadParameter, false},
+	}
+	for _, test := range tests {
+		if IsBusy(test.err) != test.busy {
+			t.Errorf("%v: Expected IsBusy=%v", test.err, test.busy)
+		}
+	}
+}
+
+func Test_Retry_002(t *testing.T) {
+	// A busy error succeeds on the second attempt
+	n := 0
+	if err := Retry(context.Background(), 3, func() error {
+		if n++; n == 1 {
+			return sqlite3.SQLITE_BUSY
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	} else if n != 2 {
+		t.Error("Expected two attempts, got", n)
+	}
+
+	// Other errors return immediately
+	n = 0
+	if err := Retry(context.Background(), 3, func() error {
+		n++
+		return ErrBadParameter
+	}); !errors.Is(err, ErrBadParameter) || n != 1 {
+		t.Error("Expected one attempt with ErrBadParameter, got", n, err)
+	}
+
+	// The last error is returned when all attempts fail
+	n = 0
+	if err := Retry(context.Background(), 3, func() error {
+		n++
+		return sqlite3.SQLITE_LOCKED
+	}); !errors.Is(err, sqlite3.SQLITE_LOCKED) || n != 3 {
+		t.Error("Expected three attempts with SQLITE_LOCKED, got", n, err)
+	}
+
+	// Cancelling the context stops any further attempts
+	n = 0
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := Retry(ctx, 100, func() error {
+		n++
+		return sqlite3.SQLITE_BUSY
+	}); !errors.Is(err, context.DeadlineExceeded) || n != 1 {
+		t.Error("Expected one attempt with context.DeadlineExceeded, got", n, err)
+	}
+}
+
+func Test_Retry_003(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first transaction is rolled back with a busy error, and the
+	// second is committed
+	n := 0
+	if err := conn.(*Conn).DoWithRetry(context.Background(), 3, 0, func(txn SQTransaction) error {
+		n++
+		if _, err := txn.Query(N("test").Insert("a"), n); err != nil {
+			return err
+		} else if n == 1 {
+			return sqlite3.SQLITE_BUSY
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	} else if n != 2 {
+		t.Error("Expected two attempts, got", n)
+	}
+	var rows []string
+	if err := conn.Exec(Q("SELECT a FROM test"), func(row, _ []string) bool {
+		rows = append(rows, row...)
+		return false
+	}); err != nil {
+		t.Error(err)
+	} else if len(rows) != 1 || rows[0] != "2" {
+		t.Error("Unexpected rows", rows)
+	}
+}
@@ -0,0 +1,106 @@
+package sqlite3
+
+import (
+	"context"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/quote"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Savepoints is passed to the function called by DoWithSavepoints, to create
+// named savepoints within the transaction and roll back to them
+type Savepoints struct {
+	conn  *Conn
+	names []string
+}
+
+// SavepointFunc is called within a transaction with the savepoint controller
+type SavepointFunc func(SQTransaction, *Savepoints) error
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// DoWithSavepoints performs a transaction in the same way as Do, but the
+// function can also create savepoints and roll back to them, discarding the
+// changes made since the savepoint without aborting the transaction. If the
+// function returns an error, the whole transaction is rolled back.
+func (conn *Conn) DoWithSavepoints(ctx context.Context, flag SQFlag, fn SavepointFunc) error {
+	if fn == nil {
+		return ErrBadParameter.With("DoWithSavepoints")
+	}
+	return conn.Do(ctx, flag, func(txn SQTransaction) error {
+		return fn(txn, &Savepoints{conn: conn})
+	})
+}
+
+// Savepoint creates a named savepoint
+func (s *Savepoints) Savepoint(name string) error {
+	if name == "" {
+		return ErrBadParameter.With("Savepoint")
+	}
+	if err := s.conn.ConnEx.Exec("SAVEPOINT "+QuoteIdentifier(name), nil); err != nil {
+		return err
+	}
+	s.names = append(s.names, name)
+
+	// Return success
+	return nil
+}
+
+// RollbackTo discards any changes made since the named savepoint was created,
+// and any savepoints created after it. The named savepoint remains, so it can be
+// rolled back to again.
+func (s *Savepoints) RollbackTo(name string) error {
+	i := s.indexOf(name)
+	if i < 0 {
+		return ErrNotFound.With("RollbackTo: ", name)
+	}
+	if err := s.conn.ConnEx.Exec("ROLLBACK TO "+QuoteIdentifier(name), nil); err != nil {
+		return err
+	}
+	s.names = s.names[:i+1]
+
+	// Return success
+	return nil
+}
+
+// Release removes the named savepoint and any savepoints created after it,
+// keeping the changes made since the savepoint as part of the transaction
+func (s *Savepoints) Release(name string) error {
+	i := s.indexOf(name)
+	if i < 0 {
+		return ErrNotFound.With("Release: ", name)
+	}
+	if err := s.conn.ConnEx.Exec("RELEASE "+QuoteIdentifier(name), nil); err != nil {
+		return err
+	}
+	s.names = s.names[:i]
+
+	// Return success
+	return nil
+}
+
+// Names returns the names of the current savepoints, in the order
+// they were created
+func (s *Savepoints) Names() []string {
+	return append([]string{}, s.names...)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// indexOf returns the index of the most recent savepoint with the
+// name, or -1 if not found
+func (s *Savepoints) indexOf(name string) int {
+	for i := len(s.names) - 1; i >= 0; i-- {
+		if s.names[i] == name {
+			return i
+		}
+	}
+	return -1
+}
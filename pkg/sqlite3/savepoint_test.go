@@ -0,0 +1,79 @@
+package sqlite3_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	// Namespace Imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Savepoint_001(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Insert rows, rolling back the work done after savepoint "b" only
+	if err := conn.(*Conn).DoWithSavepoints(context.Background(), 0, func(txn SQTransaction, sp *Savepoints) error {
+		for i, name := range []string{"a", "b", "c"} {
+			if err := sp.Savepoint(name); err != nil {
+				return err
+			}
+			if _, err := txn.Query(N("test").Insert("a"), i+1); err != nil {
+				return err
+			}
+		}
+		if err := sp.RollbackTo("b"); err != nil {
+			return err
+		}
+		if names := sp.Names(); len(names) != 2 || names[1] != "b" {
+			t.Error("Unexpected savepoints", names)
+		}
+		if err := sp.Release("a"); err != nil {
+			return err
+		}
+		if err := sp.RollbackTo("c"); !errors.Is(err, ErrNotFound) {
+			t.Error("Expected ErrNotFound, got", err)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the first row is committed
+	if n := conn.Count("", "test"); n != 1 {
+		t.Error("Unexpected count", n)
+	}
+
+	// Returning an error rolls back the whole transaction
+	if err := conn.(*Conn).DoWithSavepoints(context.Background(), 0, func(txn SQTransaction, sp *Savepoints) error {
+		if _, err := txn.Query(N("test").Insert("a"), 4); err != nil {
+			return err
+		}
+		if err := sp.Savepoint("d"); err != nil {
+			return err
+		}
+		return ErrInternalAppError
+	}); !errors.Is(err, ErrInternalAppError) {
+		t.Error("Expected ErrInternalAppError, got", err)
+	}
+	if n := conn.Count("", "test"); n != 1 {
+		t.Error("Unexpected count", n)
+	}
+}
@@ -4,16 +4,37 @@ import (
 
 	// Namespace imports
 
+	"sort"
 	"strconv"
+	"strings"
 
+	. "github.com/djthorpe/go-errors"
 	. "github.com/mutablelogic/go-sqlite"
 	. "github.com/mutablelogic/go-sqlite/pkg/lang"
 )
 
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ColumnInfo describes a column in a table, including hidden and generated
+// columns which are not returned by ColumnsForTable
+type ColumnInfo struct {
+	Cid       int    // Column index
+	Name      string // Column name
+	Type      string // Declared type, may be empty
+	NotNull   bool   // True if the column has a NOT NULL constraint
+	Default   string // Default value expression, or empty if there is no default
+	Primary   int    // Position in the primary key, or zero if not part of the primary key
+	Hidden    bool   // True for a hidden column of a virtual table
+	Generated bool   // True for a generated column
+	Stored    bool   // True for a stored generated column
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
-// Schemas returns a list of schemas
+// Schemas returns a list of schemas, with the main schema first, the temp
+// schema last and any attached schemas in alphabetical order between
 func (c *Conn) Schemas() []string {
 	schemas := []string{}
 	if err := c.Exec(Q("PRAGMA database_list"), func(row, col []string) bool {
@@ -22,6 +43,12 @@ func (c *Conn) Schemas() []string {
 	}); err != nil {
 		return nil
 	}
+	sort.SliceStable(schemas, func(i, j int) bool {
+		if a, b := schemaOrder(schemas[i]), schemaOrder(schemas[j]); a != b {
+			return a < b
+		}
+		return schemas[i] < schemas[j]
+	})
 	return schemas
 }
 
@@ -33,12 +60,12 @@ func (c *Conn) Filename(schema string) string {
 	return c.ConnEx.Filename(schema)
 }
 
-// Tables returns a list of table names in a schema
+// Tables returns a list of table names in a schema, in alphabetical order
 func (c *Conn) Tables(schema string) []string {
 	if schema == "" {
 		return c.Tables(DefaultSchema)
 	}
-	return c.objectsInSchema(schema, "table")
+	return c.objectsInSchema(schema, "table", "")
 }
 
 // Count returns a count of rows in a table, returns -1 on error
@@ -60,6 +87,50 @@ func (c *Conn) Count(schema, table string) int64 {
 	return result
 }
 
+// CountApprox returns an estimated count of rows in a table, without
+// scanning the whole table. The count is read from the sqlite_stat1 table
+// when the table has been analyzed, or else the rows are counted up to
+// maxApproxCount. Returns -1 on error
+func (c *Conn) CountApprox(schema, table string) int64 {
+	if table == "" {
+		return -1
+	} else if schema == "" {
+		return c.CountApprox(DefaultSchema, table)
+	}
+
+	// Read the largest row count for the table and its indexes, as partial
+	// indexes may contain fewer rows. Ignore errors, which occur when the
+	// schema has not been analyzed
+	result := int64(-1)
+	c.Exec(Q("SELECT stat FROM ", N("sqlite_stat1").WithSchema(schema), " WHERE tbl=", V(table)), func(row, _ []string) bool {
+		if fields := strings.Fields(row[0]); len(fields) > 0 {
+			if v, err := strconv.ParseInt(fields[0], 10, 64); err == nil && v > result {
+				result = v
+			}
+		}
+		return false
+	})
+	if result >= 0 {
+		return result
+	}
+
+	// Count rows up to the limit
+	return c.countLimit(schema, table, maxApproxCount)
+}
+
+// CountAtLeast returns true if a table has at least n rows, without
+// counting any further rows. Returns false on error
+func (c *Conn) CountAtLeast(schema, table string, n int64) bool {
+	if table == "" {
+		return false
+	} else if schema == "" {
+		return c.CountAtLeast(DefaultSchema, table, n)
+	} else if n <= 0 {
+		return c.countLimit(schema, table, 1) >= 0
+	}
+	return c.countLimit(schema, table, n) >= n
+}
+
 // ColumnsForTable returns the columns in a table
 func (c *Conn) ColumnsForTable(schema, table string) []SQColumn {
 	if schema == "" {
@@ -84,6 +155,36 @@ func (c *Conn) ColumnsForTable(schema, table string) []SQColumn {
 	return result
 }
 
+// ColumnsExInfo returns information on all the columns in a table, including
+// hidden columns of virtual tables and generated columns. Returns nil on error
+func (c *Conn) ColumnsExInfo(schema, table string) []ColumnInfo {
+	if schema == "" {
+		return c.ColumnsExInfo(DefaultSchema, table)
+	}
+	result := []ColumnInfo{}
+	if err := c.Exec(Q("PRAGMA ", N(schema), ".table_xinfo(", N(table), ")"), func(row, k []string) bool {
+		// k is "cid" "name" "type" "notnull" "dflt_value" "pk" "hidden"
+		cid, _ := strconv.Atoi(row[0])
+		pk, _ := strconv.Atoi(row[5])
+		hidden, _ := strconv.Atoi(row[6])
+		result = append(result, ColumnInfo{
+			Cid:       cid,
+			Name:      row[1],
+			Type:      row[2],
+			NotNull:   stringToBool(row[3]),
+			Default:   row[4],
+			Primary:   pk,
+			Hidden:    hidden == 1,
+			Generated: hidden == 2 || hidden == 3,
+			Stored:    hidden == 3,
+		})
+		return false
+	}); err != nil {
+		return nil
+	}
+	return result
+}
+
 // ColumnsForIndex returns the indexes associated with a table
 func (c *Conn) ColumnsForIndex(schema, index string) []string {
 	if schema == "" {
@@ -111,14 +212,14 @@ func (c *Conn) IndexesForTable(schema, table string) []SQIndexView {
 	if err := c.ExecEx(Q("PRAGMA ", N(schema), ".index_list(", N(table), ")").Query(), func(row, col []string) bool {
 		// columns are is "seq" "name" "unique" "origin" "partial"
 
-		// Get index column names, abort if error
-		names := c.ColumnsForIndex(schema, row[1])
-		if names == nil {
+		// Get index columns, abort if error
+		columns := c.indexColumns(schema, row[1])
+		if columns == nil {
 			return true
 		}
 
 		// Construct index statement
-		index := N(row[1]).WithSchema(schema).CreateIndex(table, names...)
+		index := N(row[1]).WithSchema(schema).CreateIndex(table, columns...)
 		if schema == tempSchema {
 			index = index.WithTemporary()
 		}
@@ -137,16 +238,61 @@ func (c *Conn) IndexesForTable(schema, table string) []SQIndexView {
 	return result
 }
 
-// Views returns a list of view names in a schema
+// Views returns a list of view names in a schema, in alphabetical order
 func (c *Conn) Views(schema string) []string {
 	if schema == "" {
 		return c.Views(DefaultSchema)
 	}
-	return c.objectsInSchema(schema, "view")
+	return c.objectsInSchema(schema, "view", "")
+}
+
+// Triggers returns a list of trigger names in a schema, in alphabetical
+// order. If the table argument is not empty, only triggers on that table
+// are returned
+func (c *Conn) Triggers(schema, table string) []string {
+	if schema == "" {
+		return c.Triggers(DefaultSchema, table)
+	}
+	return c.objectsInSchema(schema, "trigger", table)
+}
+
+// TableDDL returns the CREATE statements for a table in a schema, as stored
+// in the schema, followed by the statements for indexes and triggers on the
+// table. Each statement is terminated by a semicolon and newline. Indexes
+// which are created automatically for constraints are not included.
+// Returns ErrNotFound if the table does not exist
+func (c *Conn) TableDDL(schema, table string) (string, error) {
+	if schema == "" {
+		return c.TableDDL(DefaultSchema, table)
+	}
+
+	// Set the schema
+	tableName := N("sqlite_master").WithSchema(schema)
+	if schema == tempSchema {
+		tableName = N("sqlite_temp_master").WithSchema(schema)
+	}
+
+	// Get the statements with the table first, then indexes and triggers
+	var result []string
+	var found bool
+	if err := c.Exec(Q("SELECT type, sql FROM ", tableName, " WHERE tbl_name=", V(table), " AND sql IS NOT NULL ORDER BY CASE type WHEN 'table' THEN 0 WHEN 'index' THEN 1 ELSE 2 END, name"), func(row, _ []string) bool {
+		if row[0] == "table" {
+			found = true
+		}
+		result = append(result, row[1]+";\n")
+		return false
+	}); err != nil {
+		return "", err
+	} else if !found {
+		return "", ErrNotFound.Withf("%q", table)
+	}
+
+	// Return success
+	return strings.Join(result, ""), nil
 }
 
-// Modules returns a list of modules in a schema. If an argument is
-// provided, then only modules with those name prefixes are returned.
+// Modules returns a list of modules in alphabetical order. If an argument
+// is provided, then only modules with those name prefixes are returned.
 func (c *Conn) Modules(prefix ...string) []string {
 	// Get the names, return
 	result := []string{}
@@ -158,22 +304,57 @@ func (c *Conn) Modules(prefix ...string) []string {
 	}); err != nil {
 		return nil
 	}
+	sort.Strings(result)
 	return result
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
-func (c *Conn) objectsInSchema(schema, t string) []string {
+// schemaOrder returns the position of a schema when sorting, with the
+// main schema first and the temp schema last
+func schemaOrder(schema string) int {
+	switch schema {
+	case DefaultSchema:
+		return 0
+	case tempSchema:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// countLimit returns a count of rows in a table up to n rows. Returns -1
+// on error
+func (c *Conn) countLimit(schema, table string, n int64) int64 {
+	result := int64(-1)
+	if err := c.Exec(Q("SELECT COUNT(*) FROM (SELECT 1 FROM ", N(table).WithSchema(schema), " LIMIT ", V(n), ")"), func(row, _ []string) bool {
+		if v, err := strconv.ParseInt(row[0], 10, 64); err == nil {
+			result = v
+		}
+		return false
+	}); err != nil {
+		return -1
+	}
+	return result
+}
+
+func (c *Conn) objectsInSchema(schema, t, table string) []string {
 	// Set the schema
 	tableName := N("sqlite_master").WithSchema(schema)
 	if schema == tempSchema {
 		tableName = N("sqlite_temp_master").WithSchema(schema)
 	}
 
+	// Filter by table name
+	var where string
+	if table != "" {
+		where = Q(" AND tbl_name=", V(table)).Query()
+	}
+
 	// Get the names, return
 	var result []string
-	if err := c.Exec(Q("SELECT name FROM ", tableName, " WHERE type=", V(t), " AND name NOT LIKE 'sqlite_%%'"), func(row, _ []string) bool {
+	if err := c.Exec(Q("SELECT name FROM ", tableName, " WHERE type=", V(t), " AND name NOT LIKE 'sqlite_%%'", where, " ORDER BY name"), func(row, _ []string) bool {
 		result = append(result, row[0])
 		return false
 	}); err != nil {
@@ -181,3 +362,28 @@ func (c *Conn) objectsInSchema(schema, t string) []string {
 	}
 	return result
 }
+
+// indexColumns returns the key columns for an index, with the collating
+// sequence and sort order of each column. The default BINARY collating
+// sequence is omitted
+func (c *Conn) indexColumns(schema, index string) []SQSource {
+	result := []SQSource{}
+	if err := c.Exec(Q("PRAGMA ", N(schema), ".index_xinfo(", N(index), ")"), func(row, _ []string) bool {
+		// columns are "seqno" "cid" "name" "desc" "coll" "key"
+		if !stringToBool(row[5]) {
+			return false
+		}
+		column := N(row[2])
+		if row[4] != "" && !strings.EqualFold(row[4], "BINARY") {
+			column = column.WithCollate(row[4])
+		}
+		if stringToBool(row[3]) {
+			column = column.WithDesc()
+		}
+		result = append(result, column)
+		return false
+	}); err != nil {
+		return nil
+	}
+	return result
+}
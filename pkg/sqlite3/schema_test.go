@@ -3,6 +3,7 @@ package sqlite3_test
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -305,7 +306,7 @@ func Test_Schema_007(t *testing.T) {
 	}
 
 	// Create indexes
-	if err := conn.Exec(N("index_a").CreateIndex("table_a", "a", "b"), nil); err != nil {
+	if err := conn.Exec(N("index_a").CreateIndex("table_a", N("a"), N("b")), nil); err != nil {
 		t.Error(err)
 	}
 
@@ -319,3 +320,58 @@ func Test_Schema_007(t *testing.T) {
 		t.Logf("indexes: %q", indexes)
 	}
 }
+
+func Test_Schema_008(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Create a table with generated columns
+	if err := conn.Exec(Q("CREATE TABLE test (a INTEGER PRIMARY KEY, b TEXT NOT NULL DEFAULT 'x', c INTEGER AS (a * 2), d TEXT AS (upper(b)) STORED)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if columns := conn.(*Conn).ColumnsForTable("main", "test"); len(columns) != 2 {
+		t.Error("Expected two columns from ColumnsForTable, got", len(columns))
+	}
+	expected := []ColumnInfo{
+		{Cid: 0, Name: "a", Type: "INTEGER", Primary: 1},
+		{Cid: 1, Name: "b", Type: "TEXT", NotNull: true, Default: "'x'"},
+		{Cid: 2, Name: "c", Type: "INTEGER", Generated: true},
+		{Cid: 3, Name: "d", Type: "TEXT", Generated: true, Stored: true},
+	}
+	if columns := conn.(*Conn).ColumnsExInfo("", "test"); !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, columns)
+	}
+
+	// Create a virtual table, which has hidden columns
+	if err := conn.Exec(Q("CREATE VIRTUAL TABLE search USING fts5(title, body)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	columns := conn.(*Conn).ColumnsExInfo("main", "search")
+	names := make([]string, 0, len(columns))
+	hidden := 0
+	for _, column := range columns {
+		names = append(names, column.Name)
+		if column.Hidden {
+			hidden++
+		}
+	}
+	if len(names) < 2 || names[0] != "title" || names[1] != "body" {
+		t.Error("Unexpected columns", names)
+	} else if hidden == 0 {
+		t.Error("Expected hidden columns", names)
+	}
+
+	// A missing table returns no columns
+	if columns := conn.(*Conn).ColumnsExInfo("main", "missing"); len(columns) != 0 {
+		t.Error("Expected no columns, got", columns)
+	}
+}
@@ -17,6 +17,10 @@ const (
 	DefaultSchema = sqlite3.DefaultSchema
 	defaultMemory = sqlite3.DefaultMemory
 	tempSchema    = "temp"
+
+	// maxApproxCount is the number of rows counted by CountApprox when a
+	// table has not been analyzed
+	maxApproxCount = 10000
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,66 @@
+package sqlite3
+
+import (
+	"context"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Stream executes a query within a transaction and sends each row of results
+// on the returned channel, which is closed when all rows have been sent. Any
+// error, including cancellation of the context, is sent on the error channel,
+// which is closed once the query is complete. The rows channel is unbuffered,
+// so the query proceeds only as fast as rows are consumed.
+func (conn *Conn) Stream(ctx context.Context, st SQStatement, v ...interface{}) (<-chan []interface{}, <-chan error) {
+	rows := make(chan []interface{})
+	errs := make(chan error, 1)
+
+	// Check arguments
+	if ctx == nil || st == nil {
+		errs <- ErrBadParameter.With("Stream")
+		close(rows)
+		close(errs)
+		return rows, errs
+	}
+
+	go func() {
+		defer close(errs)
+		defer close(rows)
+		if err := conn.Do(ctx, 0, func(txn SQTransaction) error {
+			r, err := txn.Query(st, v...)
+			if err != nil {
+				return err
+			}
+			for {
+				row := r.Next()
+				if row == nil {
+					return ctx.Err()
+				}
+				// Copy the row and any blobs, as values are transient
+				v := make([]interface{}, len(row))
+				for i := range row {
+					if data, ok := row[i].([]byte); ok {
+						v[i] = append([]byte{}, data...)
+					} else {
+						v[i] = row[i]
+					}
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case rows <- v:
+				}
+			}
+		}); err != nil {
+			errs <- err
+		}
+	}()
+
+	// Return channels
+	return rows, errs
+}
@@ -0,0 +1,89 @@
+package sqlite3_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+const (
+	streamQuery = "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM c WHERE x < ?) SELECT x FROM c"
+)
+
+func Test_Stream_001(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Consume all rows
+	rows, rerr := conn.(*Conn).Stream(context.Background(), Q(streamQuery), 5000)
+	n := int64(0)
+	for row := range rows {
+		n++
+		if row[0] != n {
+			t.Fatal("Unexpected row", row, "expected", n)
+		}
+	}
+	if err := <-rerr; err != nil {
+		t.Error(err)
+	}
+	if n != 5000 {
+		t.Error("Unexpected number of rows", n)
+	}
+}
+
+func Test_Stream_002(t *testing.T) {
+	errs, cancel := handleErrors(t)
+	pool, err := NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	// Cancel the context part-way through the stream
+	ctx, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	rows, rerr := conn.(*Conn).Stream(ctx, Q(streamQuery), 5000)
+	n := 0
+	for range rows {
+		n++
+		if n == 100 {
+			cancel2()
+		}
+	}
+	if err := <-rerr; !errors.Is(err, context.Canceled) {
+		t.Error("Expected context.Canceled, got", err)
+	}
+	if n >= 5000 {
+		t.Error("Expected stream to be cancelled, got", n, "rows")
+	}
+
+	// Connection can be used again
+	rows, rerr = conn.(*Conn).Stream(context.Background(), Q(streamQuery), 10)
+	n = 0
+	for range rows {
+		n++
+	}
+	if err := <-rerr; err != nil {
+		t.Error(err)
+	} else if n != 10 {
+		t.Error("Unexpected number of rows", n)
+	}
+}
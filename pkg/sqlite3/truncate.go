@@ -0,0 +1,49 @@
+package sqlite3
+
+import (
+	"context"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Truncate deletes all rows from a table and resets any AUTOINCREMENT
+// counter for the table, returning the number of rows deleted. The rows are
+// deleted without a WHERE clause so that SQLite can drop them all at once,
+// unless the table has triggers.
+func (conn *Conn) Truncate(schema, table string) (int64, error) {
+	if table == "" {
+		return 0, ErrBadParameter.With("Truncate")
+	} else if schema == "" {
+		schema = DefaultSchema
+	}
+
+	var n int64
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		r, err := txn.Query(Q("DELETE FROM ", N(table).WithSchema(schema)))
+		if err != nil {
+			return err
+		}
+		n = int64(r.RowsAffected())
+
+		// Reset the counter when any table in the schema uses AUTOINCREMENT
+		r, err = txn.Query(Q("SELECT 1 FROM ", N("sqlite_master").WithSchema(schema), " WHERE type='table' AND name='sqlite_sequence'"))
+		if err != nil {
+			return err
+		} else if r.Next() == nil {
+			return nil
+		}
+		_, err = txn.Query(N("sqlite_sequence").WithSchema(schema).Delete(Q("name=", P)), table)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	// Return success
+	return n, nil
+}
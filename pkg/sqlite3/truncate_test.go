@@ -0,0 +1,64 @@
+package sqlite3_test
+
+import (
+	"testing"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Truncate_001(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Truncate a table without an autoincrement counter
+	if err := conn.Exec(Q("CREATE TABLE plain (a INTEGER)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO plain (a) VALUES (1), (2)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := conn.Truncate("", "plain"); err != nil {
+		t.Error(err)
+	} else if n != 2 {
+		t.Error("Expected 2 rows deleted, got", n)
+	}
+
+	// Populate a table with an autoincrement counter
+	if err := conn.Exec(Q("CREATE TABLE test (id INTEGER PRIMARY KEY AUTOINCREMENT, a TEXT)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO test (a) VALUES ('a'), ('b'), ('c')"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Truncate the table
+	if n, err := conn.Truncate("main", "test"); err != nil {
+		t.Fatal(err)
+	} else if n != 3 {
+		t.Error("Expected 3 rows deleted, got", n)
+	}
+	if n := conn.Count("main", "test"); n != 0 {
+		t.Error("Expected empty table, got", n)
+	}
+
+	// The autoincrement counter is reset
+	var id string
+	if err := conn.Exec(Q("INSERT INTO test (a) VALUES ('d') RETURNING id"), func(row, _ []string) bool {
+		id = row[0]
+		return false
+	}); err != nil {
+		t.Fatal(err)
+	} else if id != "1" {
+		t.Error("Expected id 1 after truncate, got", id)
+	}
+
+	// Table name is required
+	if _, err := conn.Truncate("main", ""); err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -1,9 +1,11 @@
 package sqlite3
 
 import (
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -62,6 +64,14 @@ func intMin(a, b int) int {
 	return b
 }
 
+// minInt32 returns the minimum of two int32 values
+func minInt32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 // maxInt32 returns the maximum of two int32 values
 func maxInt32(a, b int32) int32 {
 	if a > b {
@@ -93,3 +103,96 @@ func durationMax(a, b time.Duration) time.Duration {
 	}
 	return b
 }
+
+// isURI returns true if a database path is a URI filename
+func isURI(path string) bool {
+	return strings.HasPrefix(path, "file:")
+}
+
+// expandSlices expands slice arguments (other than byte slices) which are
+// bound to an "IN (?)" parameter into a list of parameters, one for each
+// element. An empty slice expands to an empty list. The query is returned
+// unchanged when it contains numbered parameters.
+func expandSlices(q string, args []interface{}) (string, []interface{}) {
+	if !hasSlice(args) {
+		return q, args
+	}
+
+	var b strings.Builder
+	var quote byte
+	result := make([]interface{}, 0, len(args))
+	n, last := 0, 0
+	for i := 0; i < len(q); i++ {
+		ch := q[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '[':
+			quote = ']'
+		case ch == '?':
+			if i+1 < len(q) && q[i+1] >= '0' && q[i+1] <= '9' {
+				return q, args
+			} else if n >= len(args) {
+				return q, args
+			}
+			arg := args[n]
+			n++
+			if rv, ok := sliceValue(arg); ok && isInList(q[:i], q[i+1:]) {
+				b.WriteString(q[last:i])
+				for j := 0; j < rv.Len(); j++ {
+					if j > 0 {
+						b.WriteByte(',')
+					}
+					b.WriteByte('?')
+					result = append(result, rv.Index(j).Interface())
+				}
+				last = i + 1
+			} else {
+				result = append(result, arg)
+			}
+		}
+	}
+	b.WriteString(q[last:])
+
+	// Return the expanded query and arguments
+	return b.String(), append(result, args[n:]...)
+}
+
+// hasSlice returns true if any argument can be expanded
+func hasSlice(args []interface{}) bool {
+	for _, arg := range args {
+		if _, ok := sliceValue(arg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sliceValue returns the value of a slice argument which can be expanded
+func sliceValue(arg interface{}) (reflect.Value, bool) {
+	rv := reflect.ValueOf(arg)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return rv, false
+	}
+	return rv, true
+}
+
+// isInList returns true if the text before and after a parameter
+// is "IN (" and ")"
+func isInList(before, after string) bool {
+	before = strings.TrimRightFunc(before, unicode.IsSpace)
+	if !strings.HasSuffix(before, "(") {
+		return false
+	}
+	before = strings.ToUpper(strings.TrimRightFunc(strings.TrimSuffix(before, "("), unicode.IsSpace))
+	if !strings.HasSuffix(before, "IN") {
+		return false
+	} else if before = strings.TrimSuffix(before, "IN"); before != "" && !unicode.IsSpace(rune(before[len(before)-1])) && before[len(before)-1] != ')' {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimLeftFunc(after, unicode.IsSpace), ")")
+}
@@ -0,0 +1,32 @@
+package sqlite3
+
+import (
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite/pkg/quote"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Vacuum rebuilds the main database file, compacting it into a minimal
+// amount of disk space. It cannot be performed within a transaction.
+func (conn *Conn) Vacuum() error {
+	if !conn.ConnEx.Autocommit() {
+		return ErrOutOfOrder.With("Vacuum cannot be performed in a transaction")
+	}
+	return conn.ConnEx.Exec("VACUUM", nil)
+}
+
+// VacuumInto writes a compacted copy of the main database into a new file
+// at path, leaving the original unchanged. The file must not already exist.
+// It cannot be performed within a transaction.
+func (conn *Conn) VacuumInto(path string) error {
+	if path == "" {
+		return ErrBadParameter.With("VacuumInto")
+	}
+	if !conn.ConnEx.Autocommit() {
+		return ErrOutOfOrder.With("VacuumInto cannot be performed in a transaction")
+	}
+	return conn.ConnEx.Exec("VACUUM INTO "+Quote(path), nil)
+}
@@ -0,0 +1,60 @@
+package sqlite3_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	// Namespace Imports
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Vacuum_001(t *testing.T) {
+	errs, cancel := handleErrors(t)
+
+	// Make folder of temp files
+	tmpdir, err := os.MkdirTemp("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	// Create a database with some data
+	pool, err := NewPool(filepath.Join(tmpdir, "test.sqlite"), errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer cancel()
+
+	// Get connection
+	conn := pool.Get()
+	defer pool.Put(conn)
+
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO test (a) VALUES (1), (2), (3)"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Vacuum and vacuum into a copy
+	path := filepath.Join(tmpdir, "copy's.sqlite")
+	if err := conn.(*Conn).Vacuum(); err != nil {
+		t.Error(err)
+	}
+	if err := conn.(*Conn).VacuumInto(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Open the copy and check the data
+	db, err := OpenPath(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if n := db.Count("", "test"); n != 3 {
+		t.Error("Unexpected count", n)
+	}
+}
@@ -0,0 +1,169 @@
+package sqobj
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	// Modules
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+
+	// Import Namespaces
+	. "github.com/djthorpe/go-errors"
+	. "github.com/mutablelogic/go-sqlite"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// CachedClass is a read-through cache for a class, keyed by primary key. All
+// rows are read into memory on first use, and read again after the update
+// hook on the connection reports a change to the table. It is intended for
+// small reference tables which rarely change.
+type CachedClass struct {
+	gen uint64 // incremented on invalidation, accessed atomically
+	SQClass
+	sync.RWMutex
+
+	class    *Class
+	conn     SQConnection
+	key      *sqcolumn
+	cache    map[interface{}]interface{} // nil until the rows are first read
+	cachegen uint64                      // generation when the rows were read
+}
+
+// updatehook is implemented by connections which report changes to rows
+type updatehook interface {
+	SetUpdateHook(sqlite3.UpdateHookFunc) error
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewCachedClass returns a cache for a class with a single primary key column,
+// which reads rows using the connection. The update hook on the connection is
+// replaced in order to invalidate the cache, so only changes made on the same
+// connection are detected.
+func NewCachedClass(conn SQConnection, class *Class) (*CachedClass, error) {
+	this := new(CachedClass)
+	if conn == nil || class == nil {
+		return nil, ErrBadParameter.With("NewCachedClass")
+	} else {
+		this.SQClass = class
+		this.class = class
+		this.conn = conn
+	}
+
+	// Set the primary key
+	for _, col := range class.col {
+		if !col.Primary {
+			continue
+		} else if this.key != nil {
+			return nil, ErrBadParameter.Withf("NewCachedClass: %q has a composite primary key", class.Name())
+		} else {
+			this.key = col
+		}
+	}
+	if this.key == nil {
+		return nil, ErrBadParameter.Withf("NewCachedClass: %q has no primary key", class.Name())
+	}
+
+	// Invalidate the cache on changes to the table
+	if hook, ok := conn.(updatehook); !ok {
+		return nil, ErrNotImplemented.With("NewCachedClass: update hook")
+	} else if err := hook.SetUpdateHook(func(_ sqlite3.SQAction, schema, table string, _ int64) {
+		if table == class.Name() && (class.Schema() == "" || schema == class.Schema()) {
+			this.Invalidate()
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return this, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Get returns the object with the primary key, or nil if there is no such
+// object or the rows could not be read. The rows are read if the cache
+// is empty or has been invalidated.
+func (this *CachedClass) Get(pk interface{}) interface{} {
+	key, ok := this.keyFor(pk)
+	if !ok {
+		return nil
+	}
+
+	// Serve from memory
+	gen := atomic.LoadUint64(&this.gen)
+	this.RLock()
+	if this.cache != nil && this.cachegen == gen {
+		defer this.RUnlock()
+		return this.cache[key]
+	}
+	this.RUnlock()
+
+	// Read the rows without holding the lock, as the update hook which
+	// invalidates the cache is called while the connection is locked
+	cache, err := this.read()
+	if err != nil {
+		return nil
+	}
+
+	// Keep the rows unless the cache was invalidated while reading
+	this.Lock()
+	defer this.Unlock()
+	if atomic.LoadUint64(&this.gen) == gen {
+		this.cache = cache
+		this.cachegen = gen
+	}
+	return cache[key]
+}
+
+// Invalidate marks the cache as stale, so that rows are read again on the
+// next call to Get. It does not block.
+func (this *CachedClass) Invalidate() {
+	atomic.AddUint64(&this.gen, 1)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// keyFor converts a primary key to the type of the primary key field. Numbers
+// are not converted to strings, or strings to numbers
+func (this *CachedClass) keyFor(pk interface{}) (interface{}, bool) {
+	rv := reflect.ValueOf(pk)
+	if !rv.IsValid() {
+		return nil, false
+	} else if rv.Type() == this.key.Type {
+		return pk, true
+	} else if rv.Type().ConvertibleTo(this.key.Type) && (rv.Kind() == reflect.String) == (this.key.Type.Kind() == reflect.String) {
+		return rv.Convert(this.key.Type).Interface(), true
+	} else {
+		return nil, false
+	}
+}
+
+// read returns all objects in the class keyed by primary key
+func (this *CachedClass) read() (map[interface{}]interface{}, error) {
+	result := make(map[interface{}]interface{})
+	if err := this.conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		iter, err := this.class.Read(txn)
+		if err != nil {
+			return err
+		}
+		for {
+			v := reflect.New(this.class.t)
+			if !iter.NextInto(v.Interface()) {
+				break
+			}
+			result[v.Elem().Field(this.key.Field.Index).Interface()] = v.Interface()
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
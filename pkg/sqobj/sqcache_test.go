@@ -0,0 +1,153 @@
+package sqobj_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	// Packages
+	sqlite3 "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+
+	// Namespace imports
+	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+	. "github.com/mutablelogic/go-sqlite/pkg/sqobj"
+)
+
+type TestCountry struct {
+	Code string `sqlite:"code,primary"`
+	Name string `sqlite:"name"`
+}
+
+func Test_Cache_001(t *testing.T) {
+	class := MustRegisterClass(N("country"), TestCountry{})
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Count the reads of the table
+	var reads int
+	db.SetTraceHook(func(_ *sqlite3.Conn, sql string, d time.Duration) {
+		if d < 0 && strings.HasPrefix(sql, "SELECT") && strings.Contains(sql, "country") {
+			reads++
+		}
+	})
+
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := class.Create(txn, ""); err != nil {
+			return err
+		}
+		_, err := class.Insert(txn, TestCountry{"de", "Germany"}, TestCountry{"fr", "France"})
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err := NewCachedClass(db, class)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first call reads the table, the second is a cache hit
+	if v, ok := cache.Get("de").(*TestCountry); !ok || v.Name != "Germany" {
+		t.Error("Unexpected value", v)
+	}
+	if v, ok := cache.Get("fr").(*TestCountry); !ok || v.Name != "France" {
+		t.Error("Unexpected value", v)
+	}
+	if v := cache.Get("uk"); v != nil {
+		t.Error("Unexpected value", v)
+	}
+	if reads != 1 {
+		t.Error("Expected one read, got", reads)
+	}
+
+	// A write invalidates the cache, and the next call reads the table again
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		_, err := class.UpsertKeys(txn, TestCountry{"de", "Deutschland"}, TestCountry{"uk", "United Kingdom"})
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := cache.Get("de").(*TestCountry); !ok || v.Name != "Deutschland" {
+		t.Error("Unexpected value", v)
+	}
+	if v, ok := cache.Get("uk").(*TestCountry); !ok || v.Name != "United Kingdom" {
+		t.Error("Unexpected value", v)
+	}
+	if reads != 2 {
+		t.Error("Expected two reads, got", reads)
+	}
+}
+
+func Test_Cache_002(t *testing.T) {
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// A class without a primary key cannot be cached
+	if _, err := NewCachedClass(db, MustRegisterClass(N("nokey_cache"), TestClassStructNoKey{})); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func Test_Cache_003(t *testing.T) {
+	class := MustRegisterClass(N("country"), TestCountry{})
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := class.Create(txn, ""); err != nil {
+			return err
+		}
+		_, err := class.Insert(txn, TestCountry{"de", "Germany"})
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err := NewCachedClass(db, class)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Call Get while a write is in progress, so that the rows are read
+	// while the write invalidates the cache
+	started, done := make(chan struct{}), make(chan error, 2)
+	go func() {
+		done <- db.Do(context.Background(), 0, func(txn SQTransaction) error {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			_, err := class.UpsertKeys(txn, TestCountry{"de", "Deutschland"})
+			return err
+		})
+	}()
+	<-started
+	go func() {
+		if v, ok := cache.Get("de").(*TestCountry); !ok || v.Name != "Deutschland" {
+			done <- fmt.Errorf("Unexpected value %v", v)
+		} else {
+			done <- nil
+		}
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Error(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timeout, deadlock between write and Get")
+		}
+	}
+}
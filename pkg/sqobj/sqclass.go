@@ -2,11 +2,15 @@ package sqobj
 
 import (
 	"fmt"
+	"log"
 	"reflect"
+	"regexp"
+	"sync"
 
 	// Import Namespaces
 	. "github.com/djthorpe/go-errors"
 	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -19,8 +23,35 @@ type Class struct {
 	// Prepared statements and in-place parameters
 	s map[stkey]SQStatement
 	p []interface{}
+
+	// Class reads from an existing table or view, and cannot be written
+	readonly bool
+
+	// Delete rows in dependent classes before deleting rows in this class
+	cascade bool
+
+	// Warn once when upserts have no conflict target
+	nokey sync.Once
 }
 
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	classLock sync.RWMutex
+	classes   = make(map[reflect.Type]*Class)
+)
+
+const (
+	// Maximum number of bound parameters in an upsert statement, which
+	// is the default value of SQLITE_MAX_VARIABLE_NUMBER
+	upsertMaxParams = 32766
+)
+
+var (
+	reWithoutRowID = regexp.MustCompile(`(?i)\bWITHOUT\s+ROWID\s*;?\s*$`)
+)
+
 ///////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
@@ -57,6 +88,51 @@ func RegisterClass(source SQSource, proto interface{}) (*Class, error) {
 	// Set parameters - used by boundValues to fill in parameters
 	this.p = make([]interface{}, 0, len(this.col)+1)
 
+	// Register the class for the prototype type, for foreign references
+	classLock.Lock()
+	defer classLock.Unlock()
+	classes[this.t] = this
+
+	// Return success
+	return this, nil
+}
+
+// MustRegisterReadOnly registers a read-only SQObject class, panics if an
+// error occurs.
+func MustRegisterReadOnly(source SQSource, proto interface{}) *Class {
+	if cls, err := RegisterReadOnly(source, proto); err != nil {
+		panic(err)
+	} else {
+		return cls
+	}
+}
+
+// RegisterReadOnly registers a SQObject class which reads objects from an
+// existing table or view, returns the class and any errors. The class cannot
+// be created, and any insert, update or delete returns an error.
+func RegisterReadOnly(source SQSource, proto interface{}) (*Class, error) {
+	this := new(Class)
+	this.s = make(map[stkey]SQStatement)
+	this.readonly = true
+
+	// Check name
+	if source.Name() == "" {
+		return nil, ErrBadParameter.Withf("source")
+	} else {
+		this.SQSource = source
+	}
+
+	// Do reflection
+	if r, err := NewReflect(proto); err != nil {
+		return nil, err
+	} else {
+		this.SQReflect = r
+	}
+
+	// A view has no rowid, so read the integer primary key instead if there is one
+	this.norowid = true
+	this.s[SQKeySelect] = sqSelect(this, nil)
+
 	// Return success
 	return this, nil
 }
@@ -70,6 +146,9 @@ func (this *Class) String() string {
 	if schema := this.Schema(); schema != "" {
 		str += fmt.Sprintf(" schema=%q", this.Schema())
 	}
+	if this.readonly {
+		str += " readonly"
+	}
 	str += " " + fmt.Sprint(this.SQReflect)
 	return str + ">"
 }
@@ -82,6 +161,11 @@ func (this *Class) Proto() reflect.Value {
 	return reflect.New(this.t)
 }
 
+// ReadOnly returns true if the class cannot be written
+func (this *Class) ReadOnly() bool {
+	return this.readonly
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
@@ -104,10 +188,56 @@ func (this *Class) WithForeignKey(parent SQClass, parentcols ...string) error {
 	}
 }
 
+// WithCascade sets the class to delete rows in dependent classes, which have
+// a foreign key referencing this class, within the same transaction before
+// rows in this class are deleted. Returns the class
+func (this *Class) WithCascade() SQClass {
+	this.cascade = true
+	return this
+}
+
+// RegisterCodec sets functions which transform the values of a column, so that
+// encode is called before a value is written and decode is called after a value
+// is read (for example, to encrypt a column). NULL values are not transformed.
+// Setting both functions to nil removes the codec from the column.
+func (this *Class) RegisterCodec(name string, encode, decode func(interface{}) (interface{}, error)) error {
+	col, exists := this.colmap[name]
+	if !exists {
+		return ErrNotFound.Withf("RegisterCodec: %q", name)
+	} else if (encode == nil) != (decode == nil) {
+		return ErrBadParameter.Withf("RegisterCodec: %q: Requires both encode and decode", name)
+	}
+	col.Encode, col.Decode = encode, decode
+
+	// Return success
+	return nil
+}
+
+// RegisterParser sets a function which returns an enum value from the text
+// stored for a column. The column should be an integer type with a String
+// method, tagged as text, so that the value returned by String is stored.
+// The value returned by parse is converted to the type of the field.
+func (this *Class) RegisterParser(name string, parse func(string) (interface{}, error)) error {
+	col, exists := this.colmap[name]
+	if !exists {
+		return ErrNotFound.Withf("RegisterParser: %q", name)
+	} else if !col.Text {
+		return ErrBadParameter.Withf("RegisterParser: %q: Not an enum stored as text", name)
+	}
+	col.Parse = parse
+
+	// Return success
+	return nil
+}
+
 // Create creates a table, keys and prepared statements within a transaction. If
 // the flag SQLITE_OPEN_OVERWRITE is set when creating the connection, then tables
 // and indexes are dropped and then re-created.
 func (this *Class) Create(txn SQTransaction, schema string) error {
+	if err := this.writable("Create"); err != nil {
+		return err
+	}
+
 	// If schema then set it
 	if schema != "" {
 		this.SQSource = this.SQSource.WithSchema(schema)
@@ -135,12 +265,16 @@ func (this *Class) Create(txn SQTransaction, schema string) error {
 		}
 	}
 
-	// Prepare statements for insert, update and delete for example
+	// Detect an existing table without rowid
+	this.norowid = isWithoutRowID(txn, this.Schema(), this.Name())
+
+	// Prepare statements for insert, update and delete for example. Statements
+	// which use the primary key are not prepared for a class without one
 	for key, st := range statements {
-		if st := st(this, txn); st == nil {
-			return ErrBadParameter.Withf("Create %q: %q", this.Name(), key)
-		} else {
+		if st := st(this, txn); st != nil {
 			this.s[key] = st
+		} else if !keyed[key] {
+			return ErrBadParameter.Withf("Create %q: %q", this.Name(), key)
 		}
 	}
 
@@ -149,14 +283,26 @@ func (this *Class) Create(txn SQTransaction, schema string) error {
 }
 
 // Insert into a table and return rowids. If any autoincremented fields are zero valued, these are automatically
-// set to NULL on insert
+// set to NULL on insert. For a table without rowid, the integer primary key is returned instead, or -1 if
+// there is no integer primary key
 func (c *Class) Insert(txn SQTransaction, v ...interface{}) ([]int64, error) {
+	return c.InsertWithConflict(txn, SQLITE_CONFLICT_NONE, v...)
+}
+
+// InsertWithConflict inserts into a table with a conflict resolution (ie, "INSERT OR IGNORE")
+// and returns rowids. The rowid is zero for any object which was not inserted
+func (c *Class) InsertWithConflict(txn SQTransaction, conflict SQConflict, v ...interface{}) ([]int64, error) {
+	if err := c.writable("Insert"); err != nil {
+		return nil, err
+	}
 	result := make([]int64, 0, len(v))
 
 	// Retrieve prepared statement
 	st, exists := c.s[SQKeyInsert]
 	if !exists {
 		return nil, ErrOutOfOrder.Withf("Insert: %q", c.Name())
+	} else if conflict != SQLITE_CONFLICT_NONE {
+		st = st.(SQInsert).WithConflictResolution(conflict)
 	}
 
 	// Insert each object
@@ -165,11 +311,19 @@ func (c *Class) Insert(txn SQTransaction, v ...interface{}) ([]int64, error) {
 		if !rv.IsValid() || rv.Type() != c.t {
 			return nil, ErrBadParameter.Withf("Insert: %v", v)
 		}
-		r, err := txn.Query(st, c.boundValues(rv, true, false)...)
+		args, err := c.boundValues(rv, true, false)
 		if err != nil {
 			return nil, err
 		}
-		result = append(result, r.LastInsertId())
+		r, err := txn.Query(st, args...)
+		if err != nil {
+			return nil, err
+		}
+		if r.RowsAffected() > 0 {
+			result = append(result, c.rowid(r, rv))
+		} else {
+			result = append(result, 0)
+		}
 	}
 
 	// Return success
@@ -196,17 +350,28 @@ func (this *Class) Read(txn SQTransaction) (SQIterator, error) {
 }
 
 // Delete from the table based on rowids, returns the number of changes
-// made
+// made. For a table without rowid, the integer primary key is used instead
 func (c *Class) DeleteRows(txn SQTransaction, row []int64) (int, error) {
+	if err := c.writable("DeleteRows"); err != nil {
+		return 0, err
+	}
+
 	// Retrieve prepared statement
 	st, exists := c.s[SQKeyDeleteRows]
 	if !exists {
 		return 0, ErrOutOfOrder.Withf("DeleteRows: %q", c.Name())
+	} else if c.norowid && c.intKey() == nil {
+		return 0, ErrNotImplemented.Withf("DeleteRows: %q: Table without rowid has no integer primary key", c.Name())
 	}
 
 	// Delete each row
 	var n int
 	for _, rowid := range row {
+		if c.cascade {
+			if err := c.deleteDependents(txn, c.rowidExpr(), []interface{}{rowid}, nil); err != nil {
+				return 0, err
+			}
+		}
 		r, err := txn.Query(st, rowid)
 		if err != nil {
 			return 0, err
@@ -220,6 +385,13 @@ func (c *Class) DeleteRows(txn SQTransaction, row []int64) (int, error) {
 
 // Delete keys in table based on primary keys. Returns number of deleted rows
 func (c *Class) DeleteKeys(txn SQTransaction, v ...interface{}) (int, error) {
+	if err := c.writable("DeleteKeys"); err != nil {
+		return 0, err
+	}
+	if len(c.columnNamesForTag(tagPrimary)) == 0 {
+		return 0, ErrNotImplemented.Withf("DeleteKeys: %q has no primary key", c.Name())
+	}
+
 	// Retrieve prepared statement
 	st, exists := c.s[SQKeyDeleteKeys]
 	if !exists {
@@ -233,7 +405,16 @@ func (c *Class) DeleteKeys(txn SQTransaction, v ...interface{}) (int, error) {
 		if !rv.IsValid() || rv.Type() != c.t {
 			return 0, ErrBadParameter.Withf("DeleteKeys: %v", v)
 		}
-		r, err := txn.Query(st, c.boundKeys(rv)...)
+		args, err := c.boundKeys(rv)
+		if err != nil {
+			return 0, err
+		}
+		if c.cascade {
+			if err := c.deleteDependents(txn, c.keyExprs(), args, nil); err != nil {
+				return 0, err
+			}
+		}
+		r, err := txn.Query(st, args...)
 		if err != nil {
 			return 0, err
 		}
@@ -244,8 +425,57 @@ func (c *Class) DeleteKeys(txn SQTransaction, v ...interface{}) (int, error) {
 	return n, nil
 }
 
+// DeleteWhere deletes rows in the table which match all the expressions, which
+// are joined with AND. Returns the number of deleted rows. At least one expression
+// is required, use DeleteAll to delete all rows in the table.
+func (c *Class) DeleteWhere(txn SQTransaction, where ...interface{}) (int, error) {
+	if err := c.writable("DeleteWhere"); err != nil {
+		return 0, err
+	} else if len(where) == 0 {
+		return 0, ErrBadParameter.Withf("DeleteWhere: %q: Missing expression, use DeleteAll", c.Name())
+	}
+	if c.cascade {
+		if err := c.deleteDependents(txn, where, nil, nil); err != nil {
+			return 0, err
+		}
+	}
+	r, err := txn.Query(c.SQSource.Delete(where...))
+	if err != nil {
+		return 0, err
+	}
+
+	// Return success
+	return r.RowsAffected(), nil
+}
+
+// DeleteAll deletes all rows in the table. Returns the number of deleted rows
+func (c *Class) DeleteAll(txn SQTransaction) (int, error) {
+	if err := c.writable("DeleteAll"); err != nil {
+		return 0, err
+	}
+	if c.cascade {
+		if err := c.deleteDependents(txn, nil, nil, nil); err != nil {
+			return 0, err
+		}
+	}
+	r, err := txn.Query(Q("DELETE FROM ", c.SQSource.WithAlias("")))
+	if err != nil {
+		return 0, err
+	}
+
+	// Return success
+	return r.RowsAffected(), nil
+}
+
 // Update objects by primary key, return number of updated rows
 func (c *Class) UpdateKeys(txn SQTransaction, v ...interface{}) (int, error) {
+	if err := c.writable("UpdateKeys"); err != nil {
+		return 0, err
+	}
+	if len(c.columnNamesForTag(tagPrimary)) == 0 {
+		return 0, ErrNotImplemented.Withf("UpdateKeys: %q has no primary key", c.Name())
+	}
+
 	// Retrieve prepared statement
 	st, exists := c.s[SQKeyUpdateKeys]
 	if !exists {
@@ -259,7 +489,11 @@ func (c *Class) UpdateKeys(txn SQTransaction, v ...interface{}) (int, error) {
 		if !rv.IsValid() || rv.Type() != c.t {
 			return 0, ErrBadParameter.Withf("UpdateKeys: %v", v)
 		}
-		r, err := txn.Query(st, c.boundValues(rv, false, true)...)
+		args, err := c.boundValues(rv, false, true)
+		if err != nil {
+			return 0, err
+		}
+		r, err := txn.Query(st, args...)
 		if err != nil {
 			return 0, err
 		}
@@ -271,6 +505,10 @@ func (c *Class) UpdateKeys(txn SQTransaction, v ...interface{}) (int, error) {
 }
 
 func (c *Class) UpsertKeys(txn SQTransaction, v ...interface{}) ([]int64, error) {
+	if err := c.writable("UpsertKeys"); err != nil {
+		return nil, err
+	}
+	c.warnConflict()
 	result := make([]int64, 0, len(v))
 
 	// Retrieve prepared statement
@@ -285,12 +523,18 @@ func (c *Class) UpsertKeys(txn SQTransaction, v ...interface{}) ([]int64, error)
 		if !rv.IsValid() || rv.Type() != c.t {
 			return nil, ErrBadParameter.Withf("UpdateKeys: %v", v)
 		}
-		r, err := txn.Query(st, c.boundValues(rv, true, false)...)
+		args, err := c.boundValues(rv, true, false)
+		if err != nil {
+			return nil, err
+		}
+		r, err := txn.Query(st, args...)
 		if err != nil {
 			return nil, err
 		}
 		if r.RowsAffected() > 0 {
-			if r.LastInsertId() == 0 {
+			if c.norowid {
+				result = append(result, c.rowid(r, rv))
+			} else if r.LastInsertId() == 0 {
 				fmt.Println("TODO: Set last insert id as rows affected (was an update)")
 				result = append(result, -1)
 			} else {
@@ -305,6 +549,95 @@ func (c *Class) UpsertKeys(txn SQTransaction, v ...interface{}) ([]int64, error)
 	return result, nil
 }
 
+// Upsert objects using multi-row INSERT ... ON CONFLICT DO UPDATE statements,
+// so that many objects are written with few statements. The conflict target
+// is the columns tagged as the conflict target, the primary key, or the unique
+// columns of the class if there is no primary key. If there is no conflict
+// target, conflicting rows are ignored. Returns the results of each statement
+// executed.
+func (c *Class) Upsert(txn SQTransaction, v ...interface{}) ([]SQResults, error) {
+	if err := c.writable("Upsert"); err != nil {
+		return nil, err
+	}
+	c.warnConflict()
+	// Determine the number of rows in each statement
+	rows := upsertMaxParams / len(c.col)
+	if rows < 1 {
+		return nil, ErrBadParameter.Withf("Upsert: %q has too many columns", c.Name())
+	}
+	cols := make([]string, len(c.col))
+	for i, col := range c.col {
+		cols[i] = col.Col.Name()
+	}
+
+	// Upsert objects in batches
+	var result []SQResults
+	args := make([]interface{}, 0, rows*len(c.col))
+	for len(v) > 0 {
+		n := len(v)
+		if n > rows {
+			n = rows
+		}
+		args = args[:0]
+		for _, v := range v[:n] {
+			rv := ValueOf(v)
+			if !rv.IsValid() || rv.Type() != c.t {
+				return nil, ErrBadParameter.Withf("Upsert: %v", v)
+			}
+			values, err := c.boundValues(rv, true, false)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, values...)
+		}
+		st := c.withConflict(c.SQSource.Insert(cols...).WithRows(uint(n)))
+		r, err := txn.Query(st, args...)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+		v = v[n:]
+	}
+
+	// Return success
+	return result, nil
+}
+
+// Upsert objects of any registered class, grouping the objects by class so
+// that each class is written with multi-row statements. Returns the results
+// of each statement executed.
+func Upsert(txn SQTransaction, v ...interface{}) ([]SQResults, error) {
+	var order []*Class
+	objs := make(map[*Class][]interface{})
+	for _, v := range v {
+		rv := ValueOf(v)
+		if !rv.IsValid() {
+			return nil, ErrBadParameter.Withf("Upsert: %v", v)
+		}
+		class := classForType(rv.Type())
+		if class == nil {
+			return nil, ErrNotFound.Withf("Upsert: %v", rv.Type())
+		}
+		if _, exists := objs[class]; !exists {
+			order = append(order, class)
+		}
+		objs[class] = append(objs[class], v)
+	}
+
+	// Upsert each class in the order first seen
+	var result []SQResults
+	for _, class := range order {
+		r, err := class.Upsert(txn, objs[class]...)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r...)
+	}
+
+	// Return success
+	return result, nil
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
@@ -312,7 +645,7 @@ func (c *Class) UpsertKeys(txn SQTransaction, v ...interface{}) ([]int64, error)
 // argument is true, then any zero-value column is set to NULL. This is so inserts
 // can be performed. If primarylast is true, then primary values are put behind non-
 // primary values.
-func (this *Class) boundValues(v reflect.Value, autonull bool, primarylast bool) []interface{} {
+func (this *Class) boundValues(v reflect.Value, autonull bool, primarylast bool) ([]interface{}, error) {
 	// Set length of parameters
 	this.p = this.p[:len(this.col)]
 
@@ -322,7 +655,11 @@ func (this *Class) boundValues(v reflect.Value, autonull bool, primarylast bool)
 		for _, col := range this.col {
 			field := v.Field(col.Field.Index)
 			if !col.Primary {
-				this.p[j] = field.Interface()
+				if value, err := col.boundValue(field); err != nil {
+					return nil, err
+				} else {
+					this.p[j] = value
+				}
 				j++
 			}
 		}
@@ -335,38 +672,229 @@ func (this *Class) boundValues(v reflect.Value, autonull bool, primarylast bool)
 		}
 		if autonull && col.Auto && field.IsZero() {
 			this.p[j] = nil
+		} else if value, err := col.boundValue(field); err != nil {
+			return nil, err
 		} else {
-			this.p[j] = field.Interface()
+			this.p[j] = value
 		}
 		j++
 	}
 
 	// Return success
-	return this.p
+	return this.p, nil
 }
 
 // boundKeys returns sqlite-compatible primary keys for a struct value.
-func (this *Class) boundKeys(v reflect.Value) []interface{} {
+func (this *Class) boundKeys(v reflect.Value) ([]interface{}, error) {
 	// Set length of parameters
 	this.p = this.p[:0]
 
 	// Iterate over columns
 	for _, col := range this.col {
 		field := v.Field(col.Field.Index)
-		if col.Primary {
-			this.p = append(this.p, field.Interface())
+		if !col.Primary {
+			continue
+		}
+		if value, err := col.boundValue(field); err != nil {
+			return nil, err
+		} else {
+			this.p = append(this.p, value)
 		}
 	}
 
 	// Return success
-	return this.p
+	return this.p, nil
 }
 
 // unboundValues fills prototype with values from v. The proto is expected to be
 // a pointer to a struct value
-func (this *Class) unboundValues(proto reflect.Value, v []interface{}) {
+func (this *Class) unboundValues(proto reflect.Value, v []interface{}) error {
 	for i, col := range this.col {
 		field := proto.Elem().Field(col.Field.Index)
-		field.Set(reflect.ValueOf(v[i]))
+		if err := col.unboundValue(field, v[i]); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// conflictTarget returns the columns used as the conflict target for an
+// upsert, which are the columns tagged as the conflict target, the primary
+// key columns, or else the first unique column or unique index
+func (this *Class) conflictTarget() []string {
+	if result := this.conflictColumns(); len(result) > 0 {
+		return result
+	}
+	var result []string
+	for _, col := range this.col {
+		if col.Primary {
+			result = append(result, col.Col.Name())
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+	for _, col := range this.col {
+		if col.Unique {
+			return []string{col.Col.Name()}
+		}
+	}
+	if names := this.indexNames(true); len(names) > 0 {
+		return this.idxmap[names[0]].cols
+	}
+	return nil
+}
+
+// withConflict adds the conflict resolution for an upsert to an insert
+// statement. If the class has no conflict target, conflicting rows are ignored
+func (this *Class) withConflict(st SQInsert) SQInsert {
+	if target := this.conflictTarget(); len(target) > 0 {
+		return st.WithConflictUpdate(target...)
+	} else {
+		return st.WithConflictDoNothing()
+	}
+}
+
+// warnConflict logs a warning, once for each class, when an upsert has no
+// conflict target and so cannot update existing rows
+func (this *Class) warnConflict() {
+	if len(this.conflictTarget()) == 0 {
+		this.nokey.Do(func() {
+			log.Printf("sqobj: %q has no primary key or unique index, upserts do nothing on conflict", this.Name())
+		})
+	}
+}
+
+// writable returns an error if the class is read-only
+func (this *Class) writable(op string) error {
+	if this.readonly {
+		return ErrNotImplemented.Withf("%s: %q is a read-only class", op, this.Name())
+	}
+	return nil
+}
+
+// rowid returns the rowid of an inserted row, or for a table without rowid
+// the integer primary key, or -1 if there is no integer primary key
+func (this *Class) rowid(r SQResults, v reflect.Value) int64 {
+	if !this.norowid {
+		return r.LastInsertId()
+	} else if key := this.intKey(); key == nil {
+		return -1
+	} else if field := v.Field(key.Field.Index); field.Kind() >= reflect.Uint && field.Kind() <= reflect.Uint64 {
+		return int64(field.Uint())
+	} else {
+		return field.Int()
+	}
+}
+
+// isWithoutRowID returns true if an existing table is a WITHOUT ROWID table
+func isWithoutRowID(txn SQTransaction, schema, name string) bool {
+	r, err := txn.Query(Q("SELECT sql FROM ", N("sqlite_master").WithSchema(schema), " WHERE type='table' AND name=", P), name)
+	if err != nil {
+		return false
+	}
+	row := r.Next()
+	if len(row) != 1 {
+		return false
+	}
+	sql, _ := row[0].(string)
+	return reWithoutRowID.MatchString(sql)
+}
+
+// rowidExpr returns the expression which matches a row by rowid, or by the
+// integer primary key for a table without rowid
+func (this *Class) rowidExpr() []interface{} {
+	if key := this.intKey(); this.norowid && key != nil {
+		return []interface{}{Q(N(key.Col.Name()), "=", P)}
+	} else {
+		return []interface{}{Q("rowid=", P)}
+	}
+}
+
+// keyExprs returns the expressions which match a row by primary key
+func (this *Class) keyExprs() []interface{} {
+	result := make([]interface{}, 0, len(this.col))
+	for _, c := range this.col {
+		if c.Primary {
+			result = append(result, Q(N(c.Col.Name()), "=", P))
+		}
+	}
+	return result
+}
+
+// deleteDependents deletes rows in classes which have a foreign key referencing
+// rows in this class which match the expressions, and then any rows which depend
+// on those, with the arguments bound to the expressions. Classes which are
+// already being deleted are skipped so that cycles are not followed, as are
+// classes which have not been created in the schema
+func (this *Class) deleteDependents(txn SQTransaction, where []interface{}, args []interface{}, visited map[*Class]bool) error {
+	if visited == nil {
+		visited = make(map[*Class]bool)
 	}
+	visited[this] = true
+	defer delete(visited, this)
+
+	parent := N(this.Name()).WithSchema(this.Schema())
+	for _, child := range dependentClasses(this.Name()) {
+		if visited[child] || !hasElement(txn.Tables(child.Schema()), child.Name()) {
+			continue
+		}
+		for _, fk := range child.fk {
+			if fk.parent != this.Name() {
+				continue
+			}
+
+			// Match child rows to the parent rows
+			parentcols := fk.parentcols
+			if len(parentcols) == 0 {
+				parentcols = this.columnNamesForTag(tagPrimary)
+			}
+			if len(parentcols) != len(fk.cols) {
+				return ErrInternalAppError.Withf("Cascade %q: Foreign key columns do not match %q", child.Name(), this.Name())
+			}
+			source := N(child.Name()).WithSchema(child.Schema())
+			expr := make([]interface{}, 0, len(parentcols)+len(where))
+			for i := range parentcols {
+				expr = append(expr, Q(parent, ".", N(parentcols[i]), "=", source, ".", N(fk.cols[i])))
+			}
+			cond := Exists(S(parent).To(V(1)).Where(append(expr, where...)...))
+
+			// Delete rows which depend on the child rows, then the child rows
+			if err := child.deleteDependents(txn, []interface{}{cond}, args, visited); err != nil {
+				return err
+			}
+			if _, err := txn.Query(child.SQSource.Delete(cond), args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// dependentClasses returns registered classes which have a foreign key
+// referencing the named parent table
+func dependentClasses(parent string) []*Class {
+	classLock.RLock()
+	defer classLock.RUnlock()
+	var result []*Class
+	for _, class := range classes {
+		for _, fk := range class.fk {
+			if fk.parent == parent {
+				result = append(result, class)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// classForType returns a registered class for a struct type, or nil
+func classForType(t reflect.Type) *Class {
+	classLock.RLock()
+	defer classLock.RUnlock()
+	return classes[t]
 }
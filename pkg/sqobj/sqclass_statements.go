@@ -36,6 +36,12 @@ var (
 		SQKeyUpdateKeys: sqUpdateKeys,
 		SQKeyUpsertKeys: sqUpsertKeys,
 	}
+
+	// Statements which require a primary key
+	keyed = map[stkey]bool{
+		SQKeyDeleteKeys: true,
+		SQKeyUpdateKeys: true,
+	}
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -43,8 +49,15 @@ var (
 
 func sqSelect(class *Class, _ SQTransaction) SQStatement {
 	cols := make([]SQExpr, len(class.col)+1)
-	// first row is the rowid
-	cols[0] = N("rowid")
+	// first row is the rowid, or the integer primary key for a table
+	// without rowid
+	if !class.norowid {
+		cols[0] = N("rowid")
+	} else if key := class.intKey(); key != nil {
+		cols[0] = N(key.Col.Name())
+	} else {
+		cols[0] = Q("0")
+	}
 	for i, col := range class.col {
 		cols[i+1] = col.Col.WithAlias("")
 	}
@@ -60,7 +73,14 @@ func sqInsert(class *Class, _ SQTransaction) SQStatement {
 }
 
 func sqDeleteRows(class *Class, _ SQTransaction) SQStatement {
-	return class.SQSource.Delete("rowid=?")
+	if !class.norowid {
+		return class.SQSource.Delete("rowid=?")
+	} else if key := class.intKey(); key != nil {
+		return class.SQSource.Delete(Q(N(key.Col.Name()), "=", P))
+	} else {
+		// DeleteRows returns an error for tables without an integer primary key
+		return class.SQSource.Delete(Q("0"))
+	}
 }
 
 func sqDeleteKeys(class *Class, _ SQTransaction) SQStatement {
@@ -83,17 +103,16 @@ func sqUpdateKeys(class *Class, _ SQTransaction) SQStatement {
 			keys = append(keys, Q(N(c.Col.Name()), "=", P))
 		}
 	}
+	if len(keys) == 0 {
+		return nil
+	}
 	return class.SQSource.Update(values...).Where(keys...)
 }
 
 func sqUpsertKeys(class *Class, _ SQTransaction) SQStatement {
 	cols := make([]string, len(class.col))
-	keys := make([]string, 0, len(class.col))
 	for i, col := range class.col {
 		cols[i] = col.Col.Name()
-		if col.Primary {
-			keys = append(keys, col.Col.Name())
-		}
 	}
-	return class.SQSource.Insert(cols...).WithConflictUpdate(keys...)
+	return class.withConflict(class.SQSource.Insert(cols...))
 }
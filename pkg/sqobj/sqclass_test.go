@@ -2,6 +2,11 @@ package sqobj_test
 
 import (
 	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +15,7 @@ import (
 	"github.com/mutablelogic/go-sqlite/pkg/sqlite3"
 
 	// Namespace imports
+	. "github.com/djthorpe/go-errors"
 	. "github.com/mutablelogic/go-sqlite"
 	. "github.com/mutablelogic/go-sqlite/pkg/lang"
 	. "github.com/mutablelogic/go-sqlite/pkg/sqobj"
@@ -400,3 +406,987 @@ func Test_Class_007(t *testing.T) {
 		return nil
 	})
 }
+
+func Test_Class_008(t *testing.T) {
+	cKey := MustRegisterClass(N("key"), TestClassStructE{})
+
+	db, err := sqlite3.New(sqlite.SQLITE_OPEN_OVERWRITE)
+	if err != nil {
+		t.Error(err)
+	}
+	defer db.Close()
+
+	// Create
+	db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := cKey.Create(txn, "main"); err != nil {
+			t.Error(err)
+			return err
+		}
+
+		// Return success
+		return nil
+	})
+
+	// Insert rows, then delete with a filter
+	db.Do(context.Background(), 0, func(txn sqlite.SQTransaction) error {
+		if _, err := cKey.Insert(txn, TestClassStructE{1, 1, "a"}, TestClassStructE{1, 2, "b"}, TestClassStructE{2, 1, "c"}); err != nil {
+			t.Error(err)
+			return err
+		}
+		if n, err := cKey.DeleteWhere(txn, Q(N("key_a"), "=", 1)); err != nil {
+			t.Error(err)
+			return err
+		} else if n != 2 {
+			t.Error("Expected 2 rows deleted, got", n)
+		}
+		if n := txn.Count("main", "key"); n != 1 {
+			t.Error("Expected 1 row remaining, got", n)
+		}
+		// Return success
+		return nil
+	})
+}
+
+func Test_Class_009(t *testing.T) {
+	cKey := MustRegisterClass(N("key"), TestClassStructE{})
+
+	db, err := sqlite3.New(sqlite.SQLITE_OPEN_OVERWRITE)
+	if err != nil {
+		t.Error(err)
+	}
+	defer db.Close()
+
+	// Create
+	db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := cKey.Create(txn, "main"); err != nil {
+			t.Error(err)
+			return err
+		}
+
+		// Return success
+		return nil
+	})
+
+	// Insert rows, check unconditional delete is rejected, then delete all
+	db.Do(context.Background(), 0, func(txn sqlite.SQTransaction) error {
+		if _, err := cKey.Insert(txn, TestClassStructE{1, 1, "a"}, TestClassStructE{1, 2, "b"}); err != nil {
+			t.Error(err)
+			return err
+		}
+		if _, err := cKey.DeleteWhere(txn); err == nil {
+			t.Error("Expected error for DeleteWhere without expressions")
+		}
+		if n := txn.Count("main", "key"); n != 2 {
+			t.Error("Expected 2 rows remaining, got", n)
+		}
+		if n, err := cKey.DeleteAll(txn); err != nil {
+			t.Error(err)
+			return err
+		} else if n != 2 {
+			t.Error("Expected 2 rows deleted, got", n)
+		}
+		// Return success
+		return nil
+	})
+}
+
+func Test_Class_010(t *testing.T) {
+	cKey := MustRegisterClass(N("key"), TestClassStructE{})
+
+	db, err := sqlite3.New(sqlite.SQLITE_OPEN_OVERWRITE)
+	if err != nil {
+		t.Error(err)
+	}
+	defer db.Close()
+
+	// Create
+	db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := cKey.Create(txn, "main"); err != nil {
+			t.Error(err)
+			return err
+		}
+
+		// Return success
+		return nil
+	})
+
+	// Insert rows, then insert duplicates which are ignored
+	db.Do(context.Background(), 0, func(txn sqlite.SQTransaction) error {
+		if _, err := cKey.Insert(txn, TestClassStructE{1, 1, "a"}, TestClassStructE{1, 2, "b"}); err != nil {
+			t.Error(err)
+			return err
+		}
+		if rowid, err := cKey.InsertWithConflict(txn, SQLITE_CONFLICT_IGNORE, TestClassStructE{1, 1, "c"}, TestClassStructE{2, 1, "d"}); err != nil {
+			t.Error(err)
+			return err
+		} else if len(rowid) != 2 || rowid[0] != 0 || rowid[1] == 0 {
+			t.Error("Unexpected rowids", rowid)
+		}
+		if n := txn.Count("main", "key"); n != 3 {
+			t.Error("Expected 3 rows, got", n)
+		}
+		// Return success
+		return nil
+	})
+
+	// Insert duplicate with abort fails
+	if err := db.Do(context.Background(), 0, func(txn sqlite.SQTransaction) error {
+		_, err := cKey.InsertWithConflict(txn, SQLITE_CONFLICT_ABORT, TestClassStructE{1, 1, "e"})
+		return err
+	}); err == nil {
+		t.Error("Expected error for duplicate insert")
+	}
+}
+
+type TestClassStructF struct {
+	Id   int    `sqlite:"id,primary"`
+	Name string `sqlite:"name"`
+}
+
+type TestClassStructG struct {
+	Name   string            `sqlite:"name,primary"`
+	Parent *TestClassStructF `sqlite:"parent,foreign"`
+	Meta   *TestClassStructA `sqlite:"meta"`
+}
+
+func Test_Class_011(t *testing.T) {
+	cParent := MustRegisterClass(N("parent"), TestClassStructF{})
+	cChild := MustRegisterClass(N("child"), TestClassStructG{})
+	t.Log(cChild)
+
+	db, err := sqlite3.New(sqlite.SQLITE_OPEN_OVERWRITE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Create, insert and read back rows
+	parent := &TestClassStructF{42, "parent"}
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := cParent.Create(txn, "main"); err != nil {
+			return err
+		}
+		if err := cChild.Create(txn, "main"); err != nil {
+			return err
+		}
+		if _, err := cParent.Insert(txn, *parent); err != nil {
+			return err
+		}
+		if _, err := cChild.Insert(txn,
+			TestClassStructG{"a", parent, &TestClassStructA{A: 100}},
+			TestClassStructG{"b", nil, nil},
+		); err != nil {
+			return err
+		}
+
+		// Check stored values
+		r, err := txn.Query(Q("SELECT parent, meta FROM child ORDER BY name"))
+		if err != nil {
+			return err
+		}
+		if row := r.Next(); len(row) != 2 || row[0] != int64(42) || row[1] != `{"A":100}` {
+			t.Error("Unexpected row", row)
+		}
+		if row := r.Next(); len(row) != 2 || row[0] != nil || row[1] != nil {
+			t.Error("Unexpected row", row)
+		}
+
+		// Read back objects
+		iter, err := cChild.Read(txn)
+		if err != nil {
+			return err
+		}
+		if v, ok := iter.Next().(*TestClassStructG); !ok {
+			t.Error("Unexpected object", v)
+		} else if v.Parent == nil || v.Parent.Id != 42 || v.Meta == nil || v.Meta.A != 100 {
+			t.Error("Unexpected object", v)
+		}
+		if v, ok := iter.Next().(*TestClassStructG); !ok {
+			t.Error("Unexpected object", v)
+		} else if v.Parent != nil || v.Meta != nil {
+			t.Error("Unexpected object", v)
+		}
+
+		// Return success
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Class_012(t *testing.T) {
+	type unregistered struct {
+		Id int `sqlite:"id,primary"`
+	}
+	type child struct {
+		Parent *unregistered `sqlite:"parent,foreign"`
+	}
+	if _, err := RegisterClass(N("child"), child{}); err == nil {
+		t.Error("Expected error for unregistered foreign class")
+	}
+}
+
+type TestClassStructH struct {
+	Key   string `sqlite:"key,primary,without rowid"`
+	Value string `sqlite:"value"`
+}
+
+type TestClassStructI struct {
+	Id    int    `sqlite:"id,primary"`
+	Value string `sqlite:"value"`
+}
+
+func Test_Class_013(t *testing.T) {
+	cKey := MustRegisterClass(N("norowid"), TestClassStructH{})
+	if st := cKey.Table(N("norowid"), false); len(st) == 0 {
+		t.Fatal("Unexpected nil returned")
+	} else if q := st[0].Query(); !strings.HasSuffix(q, "WITHOUT ROWID") {
+		t.Error("Unexpected table", q)
+	}
+
+	db, err := sqlite3.New(sqlite.SQLITE_OPEN_OVERWRITE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Write, read and delete objects
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := cKey.Create(txn, "main"); err != nil {
+			return err
+		}
+		if rowid, err := cKey.Insert(txn, TestClassStructH{"a", "1"}, TestClassStructH{"b", "2"}); err != nil {
+			return err
+		} else if len(rowid) != 2 || rowid[0] != -1 || rowid[1] != -1 {
+			t.Error("Unexpected rowids", rowid)
+		}
+		iter, err := cKey.Read(txn)
+		if err != nil {
+			return err
+		}
+		var keys []string
+		for v := iter.Next(); v != nil; v = iter.Next() {
+			keys = append(keys, v.(*TestClassStructH).Key)
+			if iter.RowId() != 0 {
+				t.Error("Unexpected rowid", iter.RowId())
+			}
+		}
+		if strings.Join(keys, ",") != "a,b" {
+			t.Error("Unexpected keys", keys)
+		}
+		if n, err := cKey.DeleteKeys(txn, TestClassStructH{Key: "a"}); err != nil {
+			return err
+		} else if n != 1 {
+			t.Error("Unexpected deleted rows", n)
+		}
+		if _, err := cKey.DeleteRows(txn, []int64{1}); !errors.Is(err, ErrNotImplemented) {
+			t.Error("Expected ErrNotImplemented, got", err)
+		}
+		if n := txn.Count("main", "norowid"); n != 1 {
+			t.Error("Expected 1 row, got", n)
+		}
+
+		// Return success
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Class_014(t *testing.T) {
+	cKey := MustRegisterClass(N("norowid"), TestClassStructI{})
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Detect an existing table without rowid, and use the primary key for identity
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if _, err := txn.Query(Q("CREATE TABLE norowid (id INTEGER PRIMARY KEY, value TEXT) WITHOUT ROWID")); err != nil {
+			return err
+		}
+		if err := cKey.Create(txn, ""); err != nil {
+			return err
+		}
+		if rowid, err := cKey.Insert(txn, TestClassStructI{10, "a"}, TestClassStructI{20, "b"}); err != nil {
+			return err
+		} else if len(rowid) != 2 || rowid[0] != 10 || rowid[1] != 20 {
+			t.Error("Unexpected rowids", rowid)
+		}
+		iter, err := cKey.Read(txn)
+		if err != nil {
+			return err
+		}
+		var rowids []int64
+		for v := iter.Next(); v != nil; v = iter.Next() {
+			if iter.RowId() != int64(v.(*TestClassStructI).Id) {
+				t.Error("Unexpected rowid", iter.RowId())
+			}
+			rowids = append(rowids, iter.RowId())
+		}
+		if n, err := cKey.DeleteRows(txn, rowids); err != nil {
+			return err
+		} else if n != 2 {
+			t.Error("Unexpected deleted rows", n)
+		}
+
+		// Return success
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Class_015(t *testing.T) {
+	type auto struct {
+		Id int `sqlite:"id,auto,without rowid"`
+	}
+	type nokey struct {
+		Id int `sqlite:"id,without rowid"`
+	}
+	if _, err := RegisterClass(N("auto"), auto{}); err == nil {
+		t.Error("Expected error for autoincrement without rowid")
+	}
+	if _, err := RegisterClass(N("nokey"), nokey{}); err == nil {
+		t.Error("Expected error for missing primary key without rowid")
+	}
+}
+
+func Test_Class_016(t *testing.T) {
+	type secret struct {
+		Id    int    `sqlite:"id,auto"`
+		Value string `sqlite:"value"`
+	}
+	rot13 := func(v interface{}) (interface{}, error) {
+		str, ok := v.(string)
+		if !ok {
+			return nil, ErrBadParameter.Withf("Unexpected %T", v)
+		}
+		return strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z':
+				return 'a' + (r-'a'+13)%26
+			case r >= 'A' && r <= 'Z':
+				return 'A' + (r-'A'+13)%26
+			default:
+				return r
+			}
+		}, str), nil
+	}
+	class := MustRegisterClass(N("secret"), secret{})
+	if err := class.RegisterCodec("missing", rot13, rot13); !errors.Is(err, ErrNotFound) {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+	if err := class.RegisterCodec("value", rot13, nil); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+	if err := class.RegisterCodec("value", rot13, rot13); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := class.Create(txn, ""); err != nil {
+			return err
+		}
+		if _, err := class.Insert(txn, secret{Value: "Hello"}); err != nil {
+			return err
+		}
+		if _, err := txn.Query(N("secret").Insert("value"), nil); err != nil {
+			return err
+		}
+
+		// The stored value is encoded
+		r, err := txn.Query(S(N("secret")).To(N("value")).Where(Q("id=1")))
+		if err != nil {
+			return err
+		} else if row := r.Next(); len(row) != 1 || row[0] != "Uryyb" {
+			t.Error("Unexpected stored value", row)
+		}
+
+		// The read value is decoded, and NULL values skip the codec
+		iter, err := class.Read(txn)
+		if err != nil {
+			return err
+		}
+		var values []string
+		for v := iter.Next(); v != nil; v = iter.Next() {
+			values = append(values, v.(*secret).Value)
+		}
+		if len(values) != 2 || values[0] != "Hello" || values[1] != "" {
+			t.Error("Unexpected values", values)
+		}
+
+		// Return success
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Class_017(t *testing.T) {
+	type attrs struct {
+		Id    int               `sqlite:"id,auto"`
+		Attrs map[string]string `sqlite:"attrs,json"`
+	}
+	class := MustRegisterClass(N("attrs"), attrs{})
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := class.Create(txn, ""); err != nil {
+			return err
+		}
+		if _, err := class.Insert(txn, attrs{Attrs: map[string]string{"a": "b"}}, attrs{}); err != nil {
+			return err
+		}
+		iter, err := class.Read(txn)
+		if err != nil {
+			return err
+		}
+		var values []map[string]string
+		for v := iter.Next(); v != nil; v = iter.Next() {
+			values = append(values, v.(*attrs).Attrs)
+		}
+		if len(values) != 2 || values[0]["a"] != "b" || values[1] != nil {
+			t.Error("Unexpected values", values)
+		}
+
+		// Return success
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Class_018(t *testing.T) {
+	type total struct {
+		Name  string `sqlite:"name"`
+		Total int    `sqlite:"total"`
+	}
+	class := MustRegisterReadOnly(N("totals"), total{})
+	if !class.ReadOnly() {
+		t.Error("Expected read-only class")
+	}
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		// Create a table and a view over it
+		for _, st := range []SQStatement{
+			N("sales").CreateTable(C("name"), C("amount").WithType("INTEGER")),
+			Q("INSERT INTO sales (name, amount) VALUES ('a', 1), ('b', 2), ('a', 3)"),
+			Q("CREATE VIEW totals AS SELECT name, SUM(amount) AS total FROM sales GROUP BY name ORDER BY name"),
+		} {
+			if _, err := txn.Query(st); err != nil {
+				return err
+			}
+		}
+
+		// Read from the view
+		iter, err := class.Read(txn)
+		if err != nil {
+			return err
+		}
+		var values []total
+		for v := iter.Next(); v != nil; v = iter.Next() {
+			values = append(values, *v.(*total))
+		}
+		if len(values) != 2 || values[0] != (total{"a", 4}) || values[1] != (total{"b", 2}) {
+			t.Error("Unexpected values", values)
+		}
+
+		// Creating and writing return errors
+		if err := class.Create(txn, ""); !errors.Is(err, ErrNotImplemented) {
+			t.Error("Expected ErrNotImplemented from Create, got", err)
+		}
+		if _, err := class.Insert(txn, total{"c", 1}); !errors.Is(err, ErrNotImplemented) {
+			t.Error("Expected ErrNotImplemented from Insert, got", err)
+		}
+		if _, err := class.UpsertKeys(txn, total{"c", 1}); !errors.Is(err, ErrNotImplemented) {
+			t.Error("Expected ErrNotImplemented from UpsertKeys, got", err)
+		}
+		if _, err := class.DeleteAll(txn); !errors.Is(err, ErrNotImplemented) {
+			t.Error("Expected ErrNotImplemented from DeleteAll, got", err)
+		}
+
+		// Return success
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+type TestClassStructUpsert struct {
+	Key   int    `sqlite:"key,primary"`
+	Value string `sqlite:"value"`
+}
+
+func Test_Class_019(t *testing.T) {
+	class := MustRegisterClass(N("upsert"), TestClassStructUpsert{})
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := class.Create(txn, ""); err != nil {
+			return err
+		}
+		if _, err := class.Insert(txn, TestClassStructUpsert{1, "a"}, TestClassStructUpsert{2, "b"}); err != nil {
+			return err
+		}
+
+		// Mix new rows, conflicting rows and unchanged rows, with enough rows
+		// for more than one statement
+		objs := []interface{}{TestClassStructUpsert{1, "A"}, TestClassStructUpsert{2, "b"}}
+		for i := 3; i <= 20000; i++ {
+			objs = append(objs, &TestClassStructUpsert{i, strconv.Itoa(i)})
+		}
+		r, err := Upsert(txn, objs...)
+		if err != nil {
+			return err
+		}
+		n := 0
+		for _, r := range r {
+			n += r.RowsAffected()
+		}
+		if len(r) != 2 || n != 19999 {
+			t.Error("Unexpected results", len(r), n)
+		}
+
+		// Check values
+		iter, err := class.Read(txn)
+		if err != nil {
+			return err
+		}
+		values := make(map[int]string)
+		for v := iter.Next(); v != nil; v = iter.Next() {
+			values[v.(*TestClassStructUpsert).Key] = v.(*TestClassStructUpsert).Value
+		}
+		if len(values) != 20000 || values[1] != "A" || values[2] != "b" || values[20000] != "20000" {
+			t.Error("Unexpected values", len(values), values[1], values[2], values[20000])
+		}
+
+		// Unregistered types return an error
+		if _, err := Upsert(txn, struct{ A int }{}); !errors.Is(err, ErrNotFound) {
+			t.Error("Expected ErrNotFound, got", err)
+		}
+
+		// Return success
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+type TestClassStructBig struct {
+	Key    int      `sqlite:"key,primary"`
+	Amount *big.Int `sqlite:"amount"`
+	Ratio  *big.Rat `sqlite:"ratio"`
+}
+
+func Test_Class_020(t *testing.T) {
+	class := MustRegisterClass(N("big"), TestClassStructBig{})
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	amount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	ratio := big.NewRat(1, 3)
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := class.Create(txn, ""); err != nil {
+			return err
+		}
+		if _, err := class.Insert(txn, TestClassStructBig{1, amount, ratio}, TestClassStructBig{2, nil, nil}); err != nil {
+			return err
+		}
+
+		// Values are stored as text
+		r, err := txn.Query(Q("SELECT amount, ratio FROM big WHERE key=1"))
+		if err != nil {
+			return err
+		}
+		if row := r.Next(); row == nil || row[0] != amount.String() || row[1] != ratio.String() {
+			t.Error("Unexpected stored values", row)
+		}
+
+		// Values are read back without loss of precision
+		iter, err := class.Read(txn)
+		if err != nil {
+			return err
+		}
+		var values []TestClassStructBig
+		for v := iter.Next(); v != nil; v = iter.Next() {
+			values = append(values, *v.(*TestClassStructBig))
+		}
+		if len(values) != 2 {
+			t.Fatal("Unexpected values", values)
+		}
+		if values[0].Amount == nil || values[0].Amount.Cmp(amount) != 0 {
+			t.Error("Unexpected amount", values[0].Amount)
+		}
+		if values[0].Ratio == nil || values[0].Ratio.Cmp(ratio) != 0 {
+			t.Error("Unexpected ratio", values[0].Ratio)
+		}
+		if values[1].Amount != nil || values[1].Ratio != nil {
+			t.Error("Expected nil values", values[1])
+		}
+
+		// Return success
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+type TestClassCascadeParent struct {
+	Id   int    `sqlite:"id,primary"`
+	Name string `sqlite:"name"`
+}
+
+type TestClassCascadeChild struct {
+	Id     int `sqlite:"id,primary"`
+	Parent int `sqlite:"parent,foreign"`
+}
+
+type TestClassCascadeGrandchild struct {
+	Id    int `sqlite:"id,primary"`
+	Child int `sqlite:"child,foreign"`
+}
+
+func Test_Class_021(t *testing.T) {
+	parent := MustRegisterClass(N("cascade_parent"), TestClassCascadeParent{})
+	child := MustRegisterClass(N("cascade_child"), TestClassCascadeChild{}).ForeignKey(parent, "id")
+	grandchild := MustRegisterClass(N("cascade_grandchild"), TestClassCascadeGrandchild{}).ForeignKey(child, "id")
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Foreign key enforcement is off, so rows in dependent classes are not
+	// deleted with the parent
+	if err := db.SetForeignKeyConstraints(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		for _, class := range []SQClass{parent, child, grandchild} {
+			if err := class.Create(txn, ""); err != nil {
+				return err
+			}
+		}
+		if _, err := parent.Insert(txn, TestClassCascadeParent{1, "a"}, TestClassCascadeParent{2, "b"}, TestClassCascadeParent{3, "c"}, TestClassCascadeParent{4, "d"}); err != nil {
+			return err
+		}
+		if _, err := child.Insert(txn, TestClassCascadeChild{1, 1}, TestClassCascadeChild{2, 1}, TestClassCascadeChild{3, 2}, TestClassCascadeChild{4, 3}, TestClassCascadeChild{5, 4}); err != nil {
+			return err
+		}
+		if _, err := grandchild.Insert(txn, TestClassCascadeGrandchild{1, 1}, TestClassCascadeGrandchild{2, 3}, TestClassCascadeGrandchild{3, 4}, TestClassCascadeGrandchild{4, 5}); err != nil {
+			return err
+		}
+		if n, err := parent.DeleteKeys(txn, TestClassCascadeParent{Id: 4}); err != nil {
+			return err
+		} else if n != 1 {
+			t.Error("Expected one deleted row, got", n)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n := db.Count("", "cascade_child"); n != 5 {
+		t.Error("Expected five child rows, got", n)
+	}
+
+	// With cascade, rows in dependent classes are deleted in the same transaction
+	parent.WithCascade()
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if n, err := parent.DeleteKeys(txn, TestClassCascadeParent{Id: 1}); err != nil {
+			return err
+		} else if n != 1 {
+			t.Error("Expected one deleted row, got", n)
+		}
+		if n, err := parent.DeleteWhere(txn, Q("name=", V("b"))); err != nil {
+			return err
+		} else if n != 1 {
+			t.Error("Expected one deleted row, got", n)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for table, expected := range map[string]int64{"cascade_parent": 1, "cascade_child": 2, "cascade_grandchild": 2} {
+		if n := db.Count("", table); n != expected {
+			t.Errorf("%s: Expected %d rows, got %d", table, expected, n)
+		}
+	}
+
+	// Delete all rows, which deletes all dependent rows with a parent
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		_, err := parent.DeleteAll(txn)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for table, expected := range map[string]int64{"cascade_parent": 0, "cascade_child": 1, "cascade_grandchild": 1} {
+		if n := db.Count("", table); n != expected {
+			t.Errorf("%s: Expected %d rows, got %d", table, expected, n)
+		}
+	}
+}
+
+func Benchmark_Class_001(b *testing.B) {
+	benchmarkUpsert(b, func(txn SQTransaction, class *Class, objs []interface{}) error {
+		_, err := class.Upsert(txn, objs...)
+		return err
+	})
+}
+
+func Benchmark_Class_002(b *testing.B) {
+	benchmarkUpsert(b, func(txn SQTransaction, class *Class, objs []interface{}) error {
+		_, err := class.UpsertKeys(txn, objs...)
+		return err
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func benchmarkUpsert(b *testing.B, upsert func(SQTransaction, *Class, []interface{}) error) {
+	class := MustRegisterClass(N("upsert"), TestClassStructUpsert{})
+	db, err := sqlite3.New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	objs := make([]interface{}, 1000)
+	for i := range objs {
+		objs[i] = TestClassStructUpsert{i, strconv.Itoa(i)}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := class.Create(txn, ""); err != nil {
+			return err
+		}
+		for i := 0; i < b.N; i++ {
+			if err := upsert(txn, class, objs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		b.Fatal(err)
+	}
+}
+
+type TestClassStructConflict struct {
+	Region string `sqlite:"region,unique:region_code"`
+	Code   int    `sqlite:"code,unique:region_code"`
+	Name   string `sqlite:"name,unique:name,conflict"`
+	Value  string `sqlite:"value"`
+}
+
+type TestClassStructNoKey struct {
+	Name  string `sqlite:"name"`
+	Value string `sqlite:"value"`
+}
+
+func Test_Class_022(t *testing.T) {
+	conflict := MustRegisterClass(N("conflict"), TestClassStructConflict{})
+	nokey := MustRegisterClass(N("nokey"), TestClassStructNoKey{})
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		for _, class := range []*Class{conflict, nokey} {
+			if err := class.Create(txn, ""); err != nil {
+				return err
+			}
+		}
+
+		// Upsert on the designated conflict target updates the existing row
+		if _, err := conflict.Upsert(txn, TestClassStructConflict{"eu", 1, "a", "1"}); err != nil {
+			return err
+		}
+		if _, err := conflict.UpsertKeys(txn, TestClassStructConflict{"us", 2, "a", "2"}); err != nil {
+			return err
+		}
+		if n := db.Count("", "conflict"); n != 1 {
+			t.Error("Expected one row, got", n)
+		}
+
+		// Upsert without a conflict target does nothing on conflict
+		if _, err := nokey.Upsert(txn, TestClassStructNoKey{"a", "1"}, TestClassStructNoKey{"a", "2"}); err != nil {
+			return err
+		}
+		if _, err := nokey.UpsertKeys(txn, TestClassStructNoKey{"b", "3"}); err != nil {
+			return err
+		}
+		if n := db.Count("", "nokey"); n != 3 {
+			t.Error("Expected three rows, got", n)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+type TestClassStructCompositeKey struct {
+	Region string `sqlite:"region,unique:region_code,conflict"`
+	Code   int    `sqlite:"code,unique:region_code,conflict"`
+	Value  string `sqlite:"value"`
+}
+
+func Test_Class_023(t *testing.T) {
+	class := MustRegisterClass(N("composite_key"), TestClassStructCompositeKey{})
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := class.Create(txn, ""); err != nil {
+			return err
+		}
+		if _, err := class.Upsert(txn, TestClassStructCompositeKey{"eu", 1, "a"}, TestClassStructCompositeKey{"eu", 2, "b"}, TestClassStructCompositeKey{"us", 1, "c"}); err != nil {
+			return err
+		}
+
+		// Rows with the same region and code are updated
+		if _, err := class.Upsert(txn, TestClassStructCompositeKey{"eu", 1, "A"}, TestClassStructCompositeKey{"us", 2, "d"}); err != nil {
+			return err
+		}
+		if _, err := class.UpsertKeys(txn, TestClassStructCompositeKey{"eu", 2, "B"}); err != nil {
+			return err
+		}
+
+		// Check values
+		iter, err := class.Read(txn)
+		if err != nil {
+			return err
+		}
+		values := make(map[string]string)
+		for v := iter.Next(); v != nil; v = iter.Next() {
+			v := v.(*TestClassStructCompositeKey)
+			values[v.Region+strconv.Itoa(v.Code)] = v.Value
+		}
+		expected := map[string]string{"eu1": "A", "eu2": "B", "us1": "c", "us2": "d"}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("Expected %v, got %v", expected, values)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+type TestColor int
+
+const (
+	TestRed TestColor = iota + 1
+	TestGreen
+)
+
+func (c TestColor) String() string {
+	switch c {
+	case TestRed:
+		return "red"
+	case TestGreen:
+		return "green"
+	default:
+		return "[?? Invalid TestColor value]"
+	}
+}
+
+type TestClassStructEnum struct {
+	Id    int       `sqlite:"id,primary"`
+	Color TestColor `sqlite:"color"`
+	Name  TestColor `sqlite:"name,text"`
+}
+
+func Test_Class_024(t *testing.T) {
+	class := MustRegisterClass(N("enum"), TestClassStructEnum{})
+
+	db, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Parse the text form of the enum
+	if err := class.RegisterParser("color", nil); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter for a column stored as an integer, got", err)
+	}
+	if err := class.RegisterParser("name", func(v string) (interface{}, error) {
+		for _, c := range []TestColor{TestRed, TestGreen} {
+			if c.String() == v {
+				return c, nil
+			}
+		}
+		return nil, ErrBadParameter.With(v)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := class.Create(txn, ""); err != nil {
+			return err
+		}
+		if _, err := class.Insert(txn, TestClassStructEnum{1, TestRed, TestGreen}); err != nil {
+			return err
+		}
+
+		// The enum is stored as an integer, or as text
+		rs, err := txn.Query(Q("SELECT color, name FROM enum"))
+		if err != nil {
+			return err
+		}
+		if row := rs.Next(); !reflect.DeepEqual(row, []interface{}{int64(1), "green"}) {
+			t.Error("Unexpected row", row)
+		}
+
+		// Both storage modes are read back into the enum
+		iter, err := class.Read(txn)
+		if err != nil {
+			return err
+		}
+		if v, ok := iter.Next().(*TestClassStructEnum); !ok {
+			t.Error("Expected a row")
+		} else if v.Color != TestRed || v.Name != TestGreen {
+			t.Error("Unexpected values", v)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
@@ -34,7 +34,7 @@ func iterator(class *Class, rs SQResults) *Iterator {
 	// Set the casting types - first is the rowid, then the rest are the values
 	this.t = append(this.t, reflect.TypeOf(int64(0)))
 	for _, col := range this.class.col {
-		this.t = append(this.t, col.Type)
+		this.t = append(this.t, col.castType())
 	}
 
 	return this
@@ -43,25 +43,47 @@ func iterator(class *Class, rs SQResults) *Iterator {
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
+// Next returns the next object, or nil if there are no more. The same object
+// is returned on each call, with the values of the next row
 func (i *Iterator) Next() interface{} {
-	if i.rs == nil {
+	if i.NextInto(i.proto.Interface()) {
+		return i.proto.Interface()
+	} else {
 		return nil
 	}
+}
+
+// NextInto reads the next row into dst, which should be a pointer to a struct
+// of the class type, and returns false if there are no more rows or dst is not
+// valid. Reusing dst between calls avoids allocating an object for each row
+func (i *Iterator) NextInto(dst interface{}) bool {
+	rv := reflect.ValueOf(dst)
+	if i.rs == nil {
+		return false
+	} else if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Type() != i.class.t {
+		return false
+	}
 	v := i.rs.Next(i.t...)
 	if v == nil {
 		i.rs = nil
 		i.rowid = 0
-		return nil
+		return false
 	}
 
-	// Set rowid and proto values
+	// Values which cannot be set end the iteration
 	i.rowid = v[0].(int64)
-	i.class.unboundValues(i.proto, v[1:])
+	if err := i.class.unboundValues(rv, v[1:]); err != nil {
+		i.rs = nil
+		i.rowid = 0
+		return false
+	}
 
-	// Return the prototype object
-	return i.proto.Interface()
+	// Return success
+	return true
 }
 
+// RowId returns the rowid of the last row read. For a table without rowid,
+// the integer primary key is returned, or zero if there is none
 func (i *Iterator) RowId() int64 {
 	return i.rowid
 }
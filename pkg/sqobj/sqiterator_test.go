@@ -81,3 +81,99 @@ func Test_Iterator_001(t *testing.T) {
 		return nil
 	})
 }
+
+func Test_Iterator_002(t *testing.T) {
+	conn, class := newIteratorClass(t, 10)
+	defer conn.Close()
+
+	conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		iter, err := class.Read(txn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Invalid destinations are rejected
+		if iter.NextInto(TestIteratorStructA{}) || iter.NextInto((*TestIteratorStructA)(nil)) || iter.NextInto(&struct{}{}) {
+			t.Error("Expected false for invalid destination")
+		}
+
+		// Read all rows into the same destination
+		var dst TestIteratorStructA
+		n := 0
+		for iter.NextInto(&dst) {
+			n++
+			if dst.A != n || dst.B != fmt.Sprint("T", n) || iter.RowId() != int64(n) {
+				t.Error("Unexpected row", dst.String())
+			}
+		}
+		if n != 10 {
+			t.Error("Expected 10 rows, got", n)
+		}
+		if iter.NextInto(&dst) || iter.Next() != nil {
+			t.Error("Expected end of iteration")
+		}
+
+		// Return success
+		return nil
+	})
+}
+
+func Benchmark_Iterator_001(b *testing.B) {
+	benchmarkIterator(b, func(iter SQIterator, _ *TestIteratorStructA) bool {
+		return iter.Next() != nil
+	})
+}
+
+func Benchmark_Iterator_002(b *testing.B) {
+	benchmarkIterator(b, func(iter SQIterator, dst *TestIteratorStructA) bool {
+		return iter.NextInto(dst)
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func newIteratorClass(t testing.TB, n int) (*sqlite3.Conn, *Class) {
+	conn, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	class, err := RegisterClass(N("test"), TestIteratorStructA{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		if err := class.Create(txn, "main"); err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if _, err := class.Insert(txn, TestIteratorStructA{B: fmt.Sprint("T", i+1)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return conn, class
+}
+
+func benchmarkIterator(b *testing.B, next func(SQIterator, *TestIteratorStructA) bool) {
+	conn, class := newIteratorClass(b, 1000)
+	defer conn.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	conn.Do(context.Background(), 0, func(txn SQTransaction) error {
+		var dst TestIteratorStructA
+		for i := 0; i < b.N; i++ {
+			iter, err := class.Read(txn)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for next(iter, &dst) {
+			}
+		}
+		return nil
+	})
+}
@@ -1,8 +1,11 @@
 package sqobj
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,36 +30,67 @@ type SQReflect struct {
 	idxmap  map[string]*sqindex
 	joinmap map[string]*sqcolumn
 	fk      []*sqforeignkey
+	norowid bool // Table is created WITHOUT ROWID
 }
 
 type sqcolumn struct {
 	*marshaler.Field
-	Col     SQColumn
-	Primary bool
-	Index   bool
-	Unique  bool
-	Foreign bool
-	Auto    bool
-	Join    bool
+	Col      SQColumn
+	Primary  bool
+	Index    bool
+	Unique   bool
+	Foreign  bool
+	Auto     bool
+	Join     bool
+	Ref      *sqcolumn // Primary key of a referenced class, for a foreign struct pointer
+	JSON     bool      // Value is stored as JSON
+	Codec    bool      // Value is transformed by a registered codec
+	Text     bool      // Enum value is stored as text using its String method
+	NoRowID  bool      // Table is created WITHOUT ROWID
+	Conflict bool      // Column is in the conflict target for upserts
+	Encode   codecFunc // Transforms a value before binding
+	Decode   codecFunc // Transforms a value after reading
+	Parse    parseFunc // Parses an enum value stored as text
 }
 
+// codecFunc transforms a column value
+type codecFunc func(interface{}) (interface{}, error)
+
+// parseFunc returns an enum value from its text form
+type parseFunc func(string) (interface{}, error)
+
 type sqindex struct {
 	name   string
 	unique bool
 	cols   []string
+	desc   []bool // Descending sort order for each column
+}
+
+// ForeignKey describes a foreign key relationship between columns of a
+// class and a referenced table
+type ForeignKey struct {
+	Columns       []string // Referencing columns
+	Table         string   // Referenced table
+	ParentColumns []string // Referenced columns, or empty for the primary key
 }
 
 type sqforeignkey struct {
 	SQForeignKey
-	cols []string
+	cols       []string
+	parent     string   // Name of the parent table
+	parentcols []string // Parent columns, or the parent primary key if empty
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // GLOBALS
 
 var (
-	timeType = reflect.TypeOf(time.Time{})
-	blobType = reflect.TypeOf([]byte{})
+	timeType     = reflect.TypeOf(time.Time{})
+	stringType   = reflect.TypeOf("")
+	blobType     = reflect.TypeOf([]byte{})
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
 )
 
 const (
@@ -67,6 +101,11 @@ const (
 	tagForeign       = "FOREIGN,FOREIGN KEY"
 	tagIndex         = "INDEX,INDEX KEY"
 	tagJoin          = "JOIN"
+	tagDefault       = "DEFAULT"
+	tagWithoutRowID  = "WITHOUT ROWID,WITHOUTROWID"
+	tagJSON          = "JSON"
+	tagCodec         = "CODEC"
+	tagConflict      = "CONFLICT"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -75,6 +114,15 @@ const (
 // Return a reflection object for the given struct or nil if the argument is
 // not a pointer to a struct or has no fields which are exported
 func NewReflect(proto interface{}) (*SQReflect, error) {
+	return NewReflectWithTag(proto, TagName)
+}
+
+// Return a reflection object for the given struct, using the named struct
+// tag (ie, "db") rather than the default tag name
+func NewReflectWithTag(proto interface{}, tag string) (*SQReflect, error) {
+	if tag == "" {
+		return nil, ErrBadParameter.With("tag")
+	}
 	r := new(SQReflect)
 	r.colmap = make(map[string]*sqcolumn)
 	r.idxmap = make(map[string]*sqindex)
@@ -88,7 +136,7 @@ func NewReflect(proto interface{}) (*SQReflect, error) {
 	}
 
 	// Reflect fields
-	fields := marshaler.NewEncoder(TagName).Reflect(proto)
+	fields := marshaler.NewEncoder(tag).Reflect(proto)
 	if len(fields) == 0 {
 		return nil, ErrBadParameter.Withf("%T", proto)
 	}
@@ -108,9 +156,28 @@ func NewReflect(proto interface{}) (*SQReflect, error) {
 		// Create column
 		if col := newColumnFor(field); col == nil {
 			result = multierror.Append(result, ErrInternalAppError.With(field.Name))
+		} else if err := col.setStructPtr(); err != nil {
+			result = multierror.Append(result, err)
+		} else if err := col.checkType(); err != nil {
+			result = multierror.Append(result, err)
 		} else {
 			r.col = append(r.col, col)
 			r.colmap[field.Name] = col
+			r.norowid = r.norowid || col.NoRowID
+		}
+	}
+
+	// A table without rowid requires a primary key, and cannot autoincrement
+	if r.norowid {
+		var primary bool
+		for _, col := range r.col {
+			if col.Auto {
+				result = multierror.Append(result, ErrBadParameter.Withf("%q: WITHOUT ROWID table cannot autoincrement", col.Field.Name))
+			}
+			primary = primary || col.Primary
+		}
+		if !primary {
+			result = multierror.Append(result, ErrBadParameter.With("WITHOUT ROWID table requires a primary key"))
 		}
 	}
 
@@ -121,19 +188,58 @@ func NewReflect(proto interface{}) (*SQReflect, error) {
 			continue
 		}
 		for _, tag := range field.Tags {
-			name, unique := parseTagIndexValue(tag)
+			name, unique, desc := parseTagIndexValue(tag)
 			if name != "" {
 				if index, exists := r.idxmap[name]; !exists {
-					r.idxmap[name] = &sqindex{name, unique, []string{field.Name}}
+					r.idxmap[name] = &sqindex{name, unique, []string{field.Name}, []bool{desc}}
 				} else if index.unique != unique {
 					result = multierror.Append(result, ErrInternalAppError.With(field.Name))
 				} else {
 					index.cols = append(index.cols, field.Name)
+					index.desc = append(index.desc, desc)
 				}
 			}
 		}
 	}
 
+	// Set foreign keys which name the referenced table, where columns which
+	// reference the same table form a single foreign key
+	for _, field := range fields {
+		if field == nil {
+			// Ignored fields
+			continue
+		}
+		for _, tag := range field.Tags {
+			parent, parentcol := parseTagForeignValue(tag)
+			if parent == "" {
+				continue
+			}
+			if fk := r.foreignKeyFor(parent); fk == nil {
+				fk = &sqforeignkey{nil, []string{field.Name}, parent, nil}
+				if parentcol != "" {
+					fk.parentcols = []string{parentcol}
+				}
+				r.fk = append(r.fk, fk)
+			} else if (parentcol == "") != (len(fk.parentcols) == 0) {
+				result = multierror.Append(result, ErrBadParameter.Withf("%q: Foreign key to %q requires referenced columns", field.Name, parent))
+			} else {
+				fk.cols = append(fk.cols, field.Name)
+				if parentcol != "" {
+					fk.parentcols = append(fk.parentcols, parentcol)
+				}
+			}
+		}
+	}
+	for _, fk := range r.fk {
+		fk.SQForeignKey = N(fk.parent).ForeignKey(fk.parentcols...).OnDeleteCascade()
+	}
+
+	// Columns designated as the conflict target must be the primary key, a
+	// unique column or a unique index
+	if err := r.checkConflict(); err != nil {
+		result = multierror.Append(result, err)
+	}
+
 	// Set joins. The join names are aliases so when joining two tables, the aliases
 	// are used to match up the columns
 	for _, field := range fields {
@@ -177,6 +283,9 @@ func (this *SQReflect) String() string {
 	if len(this.fk) > 0 {
 		str += fmt.Sprintf(" foreignkeys=%v", this.fk)
 	}
+	if this.norowid {
+		str += " withoutrowid"
+	}
 	return str + ">"
 }
 
@@ -201,6 +310,18 @@ func (this *sqcolumn) String() string {
 	if this.Join {
 		str += " join"
 	}
+	if this.Ref != nil {
+		str += " ref=" + this.Ref.Field.Name
+	}
+	if this.JSON {
+		str += " json"
+	}
+	if this.Codec {
+		str += " codec"
+	}
+	if this.Text {
+		str += " text"
+	}
 	return str + ">"
 }
 
@@ -239,7 +360,13 @@ func (this *SQReflect) Index(source SQSource, name string) SQIndexView {
 	if !exists || source == nil || source.Name() == "" {
 		return nil
 	}
-	st := N(source.Name()+"_"+name).WithSchema(source.Schema()).CreateIndex(source.Name(), index.cols...)
+	cols := make([]SQSource, len(index.cols))
+	for i, col := range index.cols {
+		if cols[i] = N(col); index.desc[i] {
+			cols[i] = cols[i].WithDesc()
+		}
+	}
+	st := N(source.Name()+"_"+name).WithSchema(source.Schema()).CreateIndex(source.Name(), cols...)
 	if st == nil {
 		return nil
 	}
@@ -265,14 +392,30 @@ func (this *SQReflect) WithForeignKey(parent SQSource, parentcols ...string) err
 	}
 
 	// Append foreign key columns
-	this.fk = append(this.fk, &sqforeignkey{parent.ForeignKey(parentcols...).OnDeleteCascade(), cols})
+	this.fk = append(this.fk, &sqforeignkey{parent.ForeignKey(parentcols...).OnDeleteCascade(), cols, parent.Name(), parentcols})
 
 	// Return success
 	return nil
 }
 
+// ForeignKeys returns the foreign key relationships of the class, both those
+// defined by tags which name the referenced table and those added with
+// WithForeignKey
+func (this *SQReflect) ForeignKeys() []ForeignKey {
+	result := make([]ForeignKey, 0, len(this.fk))
+	for _, fk := range this.fk {
+		result = append(result, ForeignKey{
+			Columns:       append([]string{}, fk.cols...),
+			Table:         fk.parent,
+			ParentColumns: append([]string{}, fk.parentcols...),
+		})
+	}
+	return result
+}
+
 // Return table and index definitions for a given source table
-// adding IF NOT EXISTS to the table and indexes
+// adding IF NOT EXISTS to the table and indexes. Columns which share
+// a unique group name are created as a single UNIQUE table constraint
 func (this *SQReflect) Table(source SQSource, ifnotexists bool) []SQStatement {
 	if source == nil || source.Name() == "" {
 		return nil
@@ -287,6 +430,9 @@ func (this *SQReflect) Table(source SQSource, ifnotexists bool) []SQStatement {
 	if ifnotexists {
 		table = table.IfNotExists()
 	}
+	if this.norowid {
+		table = table.WithoutRowID()
+	}
 	for _, column := range this.col {
 		if column.Unique {
 			table = table.WithUnique(column.Field.Name)
@@ -295,6 +441,11 @@ func (this *SQReflect) Table(source SQSource, ifnotexists bool) []SQStatement {
 		}
 	}
 
+	// Add unique constraints for columns which share a unique group name
+	for _, name := range this.indexNames(true) {
+		table = table.WithUnique(this.idxmap[name].cols...)
+	}
+
 	// Add foreign keys
 	for _, fk := range this.fk {
 		table = table.WithForeignKey(fk.SQForeignKey, fk.cols...)
@@ -304,8 +455,8 @@ func (this *SQReflect) Table(source SQSource, ifnotexists bool) []SQStatement {
 	result[0] = table
 
 	// Append index statements
-	for _, index := range this.idxmap {
-		st := this.Index(source, index.name)
+	for _, name := range this.indexNames(false) {
+		st := this.Index(source, name)
 		if st == nil {
 			return nil
 		}
@@ -369,6 +520,29 @@ func (this *SQReflect) View(source SQSource, st SQSelect, ifnotexists bool) SQSt
 ///////////////////////////////////////////////////////////////////////////////
 // STATIC METHODS
 
+// indexNames returns the sorted names of unique groups or indexes
+func (this *SQReflect) indexNames(unique bool) []string {
+	result := make([]string, 0, len(this.idxmap))
+	for name, index := range this.idxmap {
+		if index.unique == unique {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// foreignKeyFor returns the foreign key which references a parent table,
+// or nil
+func (this *SQReflect) foreignKeyFor(parent string) *sqforeignkey {
+	for _, fk := range this.fk {
+		if fk.parent == parent {
+			return fk
+		}
+	}
+	return nil
+}
+
 func (this *SQReflect) columnNamesForTag(tag string) []string {
 	result := make([]string, 0, len(this.col))
 	for _, col := range this.col {
@@ -417,7 +591,9 @@ func ValueOf(v interface{}) reflect.Value {
 }
 
 // DeclType returns the declared column type for a given field
-// uses TEXT by default. Accepts both scalar types and pointer types
+// uses TEXT by default. Accepts both scalar types and pointer types.
+// Types which cannot be stored also return TEXT, use IsSupportedType
+// to check a type can be stored
 func DeclType(t reflect.Type) string {
 	// Convert pointer type to element type
 	if t.Kind() == reflect.Ptr {
@@ -444,9 +620,50 @@ func DeclType(t reflect.Type) string {
 	return "TEXT"
 }
 
+// IsSupportedType returns true if values of a type can be stored in a column
+// without transformation. Accepts both scalar types and pointer types. The
+// arbitrary-precision types *big.Int and *big.Rat are stored as TEXT
+func IsSupportedType(t reflect.Type) bool {
+	// Arbitrary-precision numbers are supported as pointers only
+	if isBigPtr(t) {
+		return true
+	}
+	// Convert pointer type to element type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return true
+	case reflect.Slice:
+		return t == blobType
+	case reflect.Struct:
+		return t == timeType
+	default:
+		return false
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// isEnum returns true if a type has an integer kind and a String method,
+// so that it can be stored as text
+func isEnum(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return t.Implements(stringerType)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return t.Implements(stringerType)
+	default:
+		return false
+	}
+}
+
 // newColumnFor returns a new column for the given field or nil if there
 // is some sort of error
 func newColumnFor(f *marshaler.Field) *sqcolumn {
@@ -460,16 +677,23 @@ func newColumnFor(f *marshaler.Field) *sqcolumn {
 	this.Col = C(f.Name).WithType(DeclType(f.Type))
 
 	// If field value is not zero type, then set default=true
-	if !f.Value.IsZero() && f.Value.CanInterface() {
+	if !f.Value.IsZero() && f.Value.CanInterface() && IsSupportedType(f.Type) && !isBigPtr(f.Type) {
 		this.Col = this.Col.WithDefault(f.Value.Interface())
 	}
 
 	// Cycle through tags
-	for _, tag := range f.Tags {
+	for _, tag := range joinTagParens(f.Tags) {
+		// Set default value or expression
+		if def, exists := parseTagDefaultValue(tag); exists {
+			this.Col = this.Col.WithDefaultExpr(def)
+			continue
+		}
 		tag = strings.TrimSpace(strings.ToUpper(tag))
-		// If tag is BOOL, INTEGER, FLOAT, TEXT, BLOB then set column type
-		if IsType(tag) {
-			this.Col = this.Col.WithType(strings.ToUpper(tag))
+		// If tag is a declared type (ie, INTEGER, NUMERIC, DECIMAL(10,2)) then
+		// set column type
+		if IsDeclType(tag) {
+			this.Col = this.Col.WithType(tag)
+			this.Text = tag == "TEXT" && isEnum(f.Type)
 			continue
 		}
 		// Check for other tags, ignore unrecognized tags
@@ -491,24 +715,325 @@ func newColumnFor(f *marshaler.Field) *sqcolumn {
 			this.Foreign = true
 		case isTag(tag, tagJoin):
 			this.Join = true
+		case isTag(tag, tagWithoutRowID):
+			this.NoRowID = true
+		case isTag(tag, tagJSON):
+			this.JSON = true
+			this.Col = this.Col.WithType("TEXT")
+		case isTag(tag, tagCodec):
+			this.Codec = true
+		case isTag(tag, tagConflict):
+			this.Conflict = true
 		}
 	}
 	return this
 }
 
-// parseTagIndexValue returns name of index and whether the index is
-// unique or not. Returns empty string if not recognized
-func parseTagIndexValue(tag string) (string, bool) {
-	tag_name := strings.SplitN(tag, ":", 2)
-	if len(tag_name) == 2 {
-		tag = strings.TrimSpace(strings.ToUpper(tag_name[0]))
-		if isTag(tag, tagUnique) {
-			return tag_name[1], true
-		} else if isTag(tag, tagIndex) {
-			return tag_name[1], false
+// setStructPtr sets the column type for a pointer to a struct. If the field
+// is tagged as foreign and the struct is a registered class, the primary key of
+// the referenced row is stored. Otherwise the struct is stored as JSON.
+func (this *sqcolumn) setStructPtr() error {
+	if !isStructPtr(this.Field.Type) {
+		return nil
+	}
+	if this.JSON {
+		return nil
+	} else if !this.Foreign {
+		this.JSON = true
+		this.Col = this.Col.WithType("TEXT")
+		return nil
+	}
+
+	// Set the reference to the primary key of the class
+	class := classForType(this.Field.Type.Elem())
+	if class == nil {
+		return ErrNotFound.Withf("%q: Class not registered for %v", this.Field.Name, this.Field.Type)
+	}
+	var keys []*sqcolumn
+	for _, col := range class.col {
+		if col.Primary {
+			keys = append(keys, col)
+		}
+	}
+	if len(keys) != 1 {
+		return ErrBadParameter.Withf("%q: Class for %v requires a single primary key", this.Field.Name, this.Field.Type)
+	}
+	this.Ref = keys[0]
+	this.Col = this.Col.WithType(keys[0].Col.Type())
+
+	// Return success
+	return nil
+}
+
+// checkType returns an error if values of the field cannot be stored, unless
+// the field is stored as JSON or tagged to be transformed by a codec
+func (this *sqcolumn) checkType() error {
+	if this.JSON || this.Codec || this.Ref != nil || IsSupportedType(this.Field.Type) {
+		return nil
+	}
+	return ErrBadParameter.Withf("%q: Unsupported type %v, use the json or codec tag", this.Field.Name, this.Field.Type)
+}
+
+// castType returns the type used to cast the column value when reading,
+// or nil if the value should not be cast
+func (this *sqcolumn) castType() reflect.Type {
+	switch {
+	case this.Ref != nil, this.Decode != nil:
+		return nil
+	case this.JSON, this.Text:
+		return stringType
+	default:
+		return this.Type
+	}
+}
+
+// boundValue returns the sqlite-compatible value for a field, transformed
+// by any codec. Nil struct pointers are bound as NULL, skipping the codec
+func (this *sqcolumn) boundValue(v reflect.Value) (interface{}, error) {
+	value, err := this.fieldValue(v)
+	if err != nil || value == nil || this.Encode == nil {
+		return value, err
+	}
+	return this.Encode(value)
+}
+
+// fieldValue returns the sqlite-compatible value for a field
+func (this *sqcolumn) fieldValue(v reflect.Value) (interface{}, error) {
+	switch {
+	case this.Ref != nil:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return v.Elem().Field(this.Ref.Field.Index).Interface(), nil
+	case this.JSON:
+		if isNil(v) {
+			return nil, nil
+		}
+		if data, err := json.Marshal(v.Interface()); err != nil {
+			return nil, err
+		} else {
+			return string(data), nil
+		}
+	case this.Text:
+		return v.Interface().(fmt.Stringer).String(), nil
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// unboundValue sets a field from a value read from the database, transformed
+// by any codec. NULL values skip the codec, and are set as nil struct pointers
+func (this *sqcolumn) unboundValue(field reflect.Value, v interface{}) error {
+	if this.Decode != nil && v != nil {
+		if value, err := this.Decode(v); err != nil {
+			return err
+		} else {
+			v = value
+		}
+	}
+	switch {
+	case this.Ref != nil:
+		if v == nil {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		ref := reflect.New(field.Type().Elem())
+		key, rv := ref.Elem().Field(this.Ref.Field.Index), reflect.ValueOf(v)
+		if !rv.CanConvert(key.Type()) {
+			return ErrBadParameter.Withf("%q: Cannot convert %T to %v", this.Field.Name, v, key.Type())
+		}
+		key.Set(rv.Convert(key.Type()))
+		field.Set(ref)
+	case this.JSON:
+		if data, _ := v.(string); data == "" {
+			field.Set(reflect.Zero(field.Type()))
+		} else {
+			ref := reflect.New(field.Type())
+			if err := json.Unmarshal([]byte(data), ref.Interface()); err != nil {
+				return err
+			}
+			field.Set(ref.Elem())
+		}
+	case v == nil:
+		field.Set(reflect.Zero(field.Type()))
+	case this.Text:
+		if this.Parse == nil {
+			return ErrNotImplemented.Withf("%q: No parser registered for %v", this.Field.Name, field.Type())
+		}
+		data, _ := v.(string)
+		value, err := this.Parse(data)
+		if err != nil {
+			return err
+		}
+		rv := reflect.ValueOf(value)
+		if !rv.IsValid() || !rv.CanConvert(field.Type()) {
+			return ErrBadParameter.Withf("%q: Cannot convert %T to %v", this.Field.Name, value, field.Type())
+		}
+		field.Set(rv.Convert(field.Type()))
+	case this.Decode != nil:
+		rv := reflect.ValueOf(v)
+		if !rv.CanConvert(field.Type()) {
+			return ErrBadParameter.Withf("%q: Cannot convert %T to %v", this.Field.Name, v, field.Type())
+		}
+		field.Set(rv.Convert(field.Type()))
+	default:
+		field.Set(reflect.ValueOf(v))
+	}
+
+	// Return success
+	return nil
+}
+
+// conflictColumns returns the names of columns designated as the conflict
+// target for upserts, in column order
+func (this *SQReflect) conflictColumns() []string {
+	var result []string
+	for _, col := range this.col {
+		if col.Conflict {
+			result = append(result, col.Col.Name())
+		}
+	}
+	return result
+}
+
+// checkConflict returns an error if the designated conflict target is not the
+// primary key, a unique column or a unique index
+func (this *SQReflect) checkConflict() error {
+	target := this.conflictColumns()
+	if len(target) == 0 {
+		return nil
+	}
+	if len(target) == 1 && this.colmap[target[0]].Unique {
+		return nil
+	}
+	var primary []string
+	for _, col := range this.col {
+		if col.Primary {
+			primary = append(primary, col.Col.Name())
+		}
+	}
+	if sameColumns(target, primary) {
+		return nil
+	}
+	for _, name := range this.indexNames(true) {
+		if sameColumns(target, this.idxmap[name].cols) {
+			return nil
+		}
+	}
+	return ErrBadParameter.Withf("conflict target %q is not a primary key or unique index", target)
+}
+
+// intKey returns the primary key column when there is a single integer
+// primary key, or nil
+func (this *SQReflect) intKey() *sqcolumn {
+	var key *sqcolumn
+	for _, col := range this.col {
+		if !col.Primary {
+			continue
+		} else if key != nil {
+			return nil
 		}
+		key = col
+	}
+	if key == nil {
+		return nil
+	}
+	switch key.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return key
+	default:
+		return nil
+	}
+}
+
+// isStructPtr returns true if the type is a pointer to a struct, which is
+// not a time value or arbitrary-precision number
+func isStructPtr(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct && t.Elem() != timeType && !isBigPtr(t)
+}
+
+// isBigPtr returns true if the type is *big.Int or *big.Rat
+func isBigPtr(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && (t.Elem() == bigIntType || t.Elem() == bigRatType)
+}
+
+// isNil returns true if the value is a nil pointer, map, slice or interface
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// parseTagIndexValue returns name of index, whether the index is
+// unique or not and whether the column is in descending order (ie,
+// index:name:desc). Returns empty string if not recognized
+func parseTagIndexValue(tag string) (string, bool, bool) {
+	tag_name := strings.SplitN(tag, ":", 3)
+	if len(tag_name) < 2 {
+		return "", false, false
+	}
+	desc := false
+	if len(tag_name) == 3 {
+		switch strings.TrimSpace(strings.ToUpper(tag_name[2])) {
+		case "DESC":
+			desc = true
+		case "ASC":
+			desc = false
+		default:
+			return "", false, false
+		}
+	}
+	tag = strings.TrimSpace(strings.ToUpper(tag_name[0]))
+	if isTag(tag, tagUnique) {
+		return tag_name[1], true, desc
+	} else if isTag(tag, tagIndex) {
+		return tag_name[1], false, desc
+	}
+	return "", false, false
+}
+
+// joinTagParens rejoins tags which were split on a comma within parentheses,
+// so that a declared type such as DECIMAL(10,2) is a single tag
+func joinTagParens(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for i := 0; i < len(tags); i++ {
+		tag := tags[i]
+		for strings.Count(tag, "(") > strings.Count(tag, ")") && i+1 < len(tags) {
+			i++
+			tag += "," + tags[i]
+		}
+		result = append(result, tag)
+	}
+	return result
+}
+
+// parseTagDefaultValue returns the default for a column, which is an
+// expression or a quoted literal value as decided by DefaultExpr
+func parseTagDefaultValue(tag string) (string, bool) {
+	tag_name := strings.SplitN(tag, ":", 2)
+	if len(tag_name) != 2 || !isTag(strings.TrimSpace(strings.ToUpper(tag_name[0])), tagDefault) {
+		return "", false
+	}
+	return DefaultExpr(tag_name[1]), true
+}
+
+// parseTagForeignValue returns the referenced table and column for a
+// foreign key tag which names the table (ie, foreign:users.id). The column
+// is empty when the foreign key references the primary key of the table
+// (ie, foreign:users). Returns empty strings if not recognized
+func parseTagForeignValue(tag string) (string, string) {
+	tag_name := strings.SplitN(tag, ":", 2)
+	if len(tag_name) != 2 || !isTag(strings.TrimSpace(strings.ToUpper(tag_name[0])), tagForeign) {
+		return "", ""
+	}
+	ref := strings.SplitN(strings.TrimSpace(tag_name[1]), ".", 2)
+	if len(ref) == 2 {
+		return ref[0], ref[1]
 	}
-	return "", false
+	return ref[0], ""
 }
 
 // parseTagJoinValue returns name of join. Returns empty string
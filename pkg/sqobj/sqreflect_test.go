@@ -2,7 +2,13 @@ package sqobj_test
 
 import (
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	// Packages
+	sqlite3 "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
 
 	// Modules
 	. "github.com/djthorpe/go-errors"
@@ -188,3 +194,258 @@ func Test_Reflect_010(t *testing.T) {
 	t.Log(a)
 	t.Log(b)
 }
+
+type TestStructDefault struct {
+	Created time.Time `sqlite:"created,default:CURRENT_TIMESTAMP"`
+	Status  string    `sqlite:"status,default:CURRENT_TIMESTAMP is not a keyword"`
+	Count   int       `sqlite:"count,not null,default:10"`
+}
+
+func Test_Reflect_011(t *testing.T) {
+	r, err := NewReflect(TestStructDefault{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"created": `created TIMESTAMP DEFAULT CURRENT_TIMESTAMP`,
+		"status":  `status TEXT DEFAULT 'CURRENT_TIMESTAMP is not a keyword'`,
+		"count":   `count INTEGER NOT NULL DEFAULT 10`,
+	}
+	for name, expected := range tests {
+		if col := r.Column(name); col == nil {
+			t.Error("Missing column", name)
+		} else if col.String() != expected {
+			t.Errorf("Unexpected column %q, wanted %q", col, expected)
+		}
+	}
+	if st := r.Table(N("test"), false); len(st) == 0 {
+		t.Error("Unexpected nil returned")
+	} else if q := st[0].Query(); !strings.Contains(q, "DEFAULT CURRENT_TIMESTAMP") {
+		t.Error("Unexpected table", q)
+	}
+}
+
+type TestStructTag struct {
+	A int    `db:"id,primary" sqlite:"a"`
+	B string `db:"name,notnull" sqlite:"b"`
+	C string `db:"-"`
+}
+
+func Test_Reflect_012(t *testing.T) {
+	r, err := NewReflectWithTag(TestStructTag{}, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cols := r.Columns()
+	if len(cols) != 2 {
+		t.Fatal("Unexpected columns", cols)
+	} else if cols[0].String() != "id INTEGER NOT NULL" || cols[0].Primary() == "" {
+		t.Error("Unexpected column", cols[0])
+	} else if cols[1].String() != "name TEXT NOT NULL" {
+		t.Error("Unexpected column", cols[1])
+	}
+
+	// Default tag name is unchanged
+	if r, err := NewReflect(TestStructTag{}); err != nil {
+		t.Error(err)
+	} else if cols := r.Columns(); len(cols) != 3 || cols[0].Name() != "a" || cols[1].Name() != "b" || cols[2].Name() != "C" {
+		t.Error("Unexpected columns", cols)
+	}
+
+	// Empty tag name is an error
+	if _, err := NewReflectWithTag(TestStructTag{}, ""); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+}
+
+type TestStructUnsupported struct {
+	A int               `sqlite:"a"`
+	B map[string]string `sqlite:"b"`
+}
+
+type TestStructJSON struct {
+	A int               `sqlite:"a"`
+	B map[string]string `sqlite:"b,json"`
+	C []string          `sqlite:"c,codec"`
+}
+
+func Test_Reflect_013(t *testing.T) {
+	// Map fields cannot be stored
+	if _, err := NewReflect(TestStructUnsupported{}); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter, got", err)
+	} else if !strings.Contains(err.Error(), `"b"`) || !strings.Contains(err.Error(), "map[string]string") {
+		t.Error("Expected error to name field and type, got", err)
+	}
+
+	// Map fields tagged as JSON are stored as TEXT, and codec fields are allowed
+	if r, err := NewReflect(TestStructJSON{}); err != nil {
+		t.Error(err)
+	} else if col := r.Column("b"); col == nil || col.String() != "b TEXT" {
+		t.Error("Unexpected column", col)
+	} else if col := r.Column("c"); col == nil {
+		t.Error("Missing column c")
+	}
+}
+
+type TestStructG struct {
+	A int    `sqlite:"a,unique:grp"`
+	B string `sqlite:"b,unique:grp"`
+	C int    `sqlite:"c,index:c"`
+}
+
+func Test_Reflect_014(t *testing.T) {
+	r, err := NewReflect(TestStructG{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := r.Table(N("test"), false)
+	if len(st) != 2 {
+		t.Fatal("Expected table and one index, got", st)
+	}
+	if q := st[0].Query(); strings.Count(q, "UNIQUE (a,b)") != 1 {
+		t.Error("Unexpected table:", q)
+	} else if strings.Contains(q, "UNIQUE (a)") || strings.Contains(q, "UNIQUE (b)") {
+		t.Error("Unexpected table:", q)
+	}
+	if q := st[1].Query(); q != "CREATE INDEX test_c ON test (c)" {
+		t.Error("Unexpected index:", q)
+	}
+}
+
+type TestStructH struct {
+	A int `sqlite:"a,index:x"`
+	B int `sqlite:"b,index:x:desc"`
+	C int `sqlite:"c,index:y:asc"`
+}
+
+func Test_Reflect_015(t *testing.T) {
+	r, err := NewReflect(TestStructH{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := r.Index(N("test"), "x").Query(); q != "CREATE INDEX test_x ON test (a,b DESC)" {
+		t.Error("Unexpected index:", q)
+	}
+	if q := r.Index(N("test"), "y").Query(); q != "CREATE INDEX test_y ON test (c)" {
+		t.Error("Unexpected index:", q)
+	}
+}
+
+type TestStructAffinity struct {
+	A int64   `sqlite:"a,text"`
+	B float64 `sqlite:"b,numeric"`
+	C float64 `sqlite:"c,decimal(10,2),not null"`
+	D float64 `sqlite:"d,real"`
+}
+
+func Test_Reflect_016(t *testing.T) {
+	r, err := NewReflect(TestStructAffinity{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ name, col string }{
+		{"a", "a TEXT"},
+		{"b", "b NUMERIC"},
+		{"c", "c DECIMAL(10,2) NOT NULL"},
+		{"d", "d REAL"},
+	}
+	for _, test := range tests {
+		if col := r.Column(test.name); col == nil || col.String() != test.col {
+			t.Errorf("Unexpected column %q, expected %q", col, test.col)
+		}
+	}
+
+	// Create the table and check the declared types
+	conn, err := sqlite3.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for _, st := range r.Table(N("test"), false) {
+		if err := conn.Exec(st, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, col := range conn.ColumnsForTable("main", "test") {
+		if col.Name() == "c" && col.Type() != "DECIMAL(10,2)" {
+			t.Error("Unexpected declared type", col.Type())
+		} else if col.Name() == "b" && col.Type() != "NUMERIC" {
+			t.Error("Unexpected declared type", col.Type())
+		}
+	}
+}
+
+type TestStructConflict struct {
+	A int `sqlite:"a,primary,conflict"`
+	B int `sqlite:"b,primary,conflict"`
+	C int `sqlite:"c,unique:cd,conflict"`
+	D int `sqlite:"d,unique:cd"`
+}
+
+func Test_Reflect_017(t *testing.T) {
+	// The conflict target must be the primary key, a unique column or a unique index
+	tests := []struct {
+		proto interface{}
+		ok    bool
+	}{
+		{struct {
+			A int `sqlite:"a,primary,conflict"`
+			B int `sqlite:"b,primary,conflict"`
+		}{}, true},
+		{struct {
+			A int `sqlite:"a,unique,conflict"`
+			B int `sqlite:"b"`
+		}{}, true},
+		{struct {
+			A int `sqlite:"a,primary"`
+			C int `sqlite:"c,unique:cd,conflict"`
+			D int `sqlite:"d,unique:cd,conflict"`
+		}{}, true},
+		{struct {
+			A int `sqlite:"a,primary"`
+			B int `sqlite:"b,conflict"`
+		}{}, false},
+		{TestStructConflict{}, false},
+	}
+	for i, test := range tests {
+		if _, err := NewReflect(test.proto); test.ok && err != nil {
+			t.Errorf("%d: Unexpected error %v", i, err)
+		} else if !test.ok && !errors.Is(err, ErrBadParameter) {
+			t.Errorf("%d: Expected ErrBadParameter, got %v", i, err)
+		}
+	}
+}
+
+func Test_Reflect_018(t *testing.T) {
+	// Foreign keys which name the referenced table are parsed from the tags
+	r, err := NewReflect(struct {
+		A      int    `sqlite:"a,primary"`
+		UserId int    `sqlite:"user_id,foreign:users.id"`
+		Org    string `sqlite:"org,foreign:orgs.name"`
+		Unit   string `sqlite:"unit,foreign:orgs.unit"`
+		Group  int    `sqlite:"group_id,foreign:groups"`
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ForeignKey{
+		{[]string{"user_id"}, "users", []string{"id"}},
+		{[]string{"org", "unit"}, "orgs", []string{"name", "unit"}},
+		{[]string{"group_id"}, "groups", []string{}},
+	}
+	if fk := r.ForeignKeys(); !reflect.DeepEqual(fk, expected) {
+		t.Errorf("Unexpected foreign keys %v", fk)
+	}
+	st := r.Table(N("test"), false)
+	if len(st) == 0 || !strings.Contains(st[0].Query(), `REFERENCES users (id)`) || !strings.Contains(st[0].Query(), `REFERENCES orgs (name,unit)`) {
+		t.Errorf("Unexpected table %q", st)
+	}
+
+	// Columns which reference the same table must all name a column, or none
+	if _, err := NewReflect(struct {
+		A int `sqlite:"a,foreign:users.id"`
+		B int `sqlite:"b,foreign:users"`
+	}{}); !errors.Is(err, ErrBadParameter) {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+}
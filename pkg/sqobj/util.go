@@ -8,3 +8,17 @@ func hasElement(arr []string, elem string) bool {
 	}
 	return false
 }
+
+// sameColumns returns true if two lists contain the same column names,
+// in any order
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, v := range a {
+		if !hasElement(b, v) {
+			return false
+		}
+	}
+	return true
+}
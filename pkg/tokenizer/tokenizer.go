@@ -2,8 +2,11 @@ package tokenizer
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
 	"io"
 	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -22,6 +25,17 @@ import (
 // A tokenizer that scans the input SQL statement
 type Tokenizer struct {
 	*bufio.Scanner
+
+	offset int             // Offset of the next token in the input
+	quote  PuncuationToken // Opening quote of a string or identifier
+	start  int             // Offset of the opening quote
+}
+
+// UnterminatedError is returned when a quoted string or identifier
+// is not closed before the end of the input
+type UnterminatedError struct {
+	Quote  string // The opening quote character
+	Offset int    // The offset of the opening quote in the input
 }
 
 type (
@@ -31,15 +45,23 @@ type (
 	ValueToken      string // A value literal
 	PuncuationToken string // A punctuation character
 	WhitespaceToken string // Whitespace token
+	ParameterToken  string // A bind parameter placeholder
 )
 
 ////////////////////////////////////////////////////////////////////////////////
 // GLOBALS
 
+var (
+	// ErrUnterminatedString is matched by errors.Is when a quoted string
+	// or identifier is not closed before the end of the input
+	ErrUnterminatedString = errors.New("unterminated string")
+)
+
 var (
 	reWhitespace = regexp.MustCompile(`^\s*$`)
 	reName       = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
 	reNumber     = regexp.MustCompile(`^[+-]?([0-9]+([.][0-9]*)?|[.][0-9]+)$`)
+	reParameter  = regexp.MustCompile(`^(\?[0-9]*|[:@$][a-zA-Z_][a-zA-Z0-9_]*)$`)
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -47,23 +69,47 @@ var (
 
 // NewTokenizer returns a new Tokenizer that scans the input SQL statement
 func NewTokenizer(v string) *Tokenizer {
-	t := &Tokenizer{bufio.NewScanner(strings.NewReader(v))}
+	t := &Tokenizer{Scanner: bufio.NewScanner(strings.NewReader(v))}
 	t.Scanner.Split(sqlSplit)
 	return t
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (e *UnterminatedError) Error() string {
+	return fmt.Sprintf("%v: %s at offset %d", ErrUnterminatedString, e.Quote, e.Offset)
+}
+
+func (e *UnterminatedError) Unwrap() error {
+	return ErrUnterminatedString
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
 // Next returns the next token in the input stream, or returns io.EOF error and
-// nil if there are no more tokens to comsume.
+// nil if there are no more tokens to comsume. If a quoted string or identifier
+// is not closed at the end of the input, an *UnterminatedError is returned
+// instead of io.EOF.
 func (t *Tokenizer) Next() (interface{}, error) {
 	if t.Scanner.Scan() {
 		txt := t.Scanner.Text()
-		return toToken(txt), nil
+		token := toToken(txt)
+		if quote, ok := token.(PuncuationToken); ok && isQuote(quote) {
+			if t.quote == "" {
+				t.quote, t.start = quote, t.offset
+			} else if t.quote == quote {
+				t.quote = ""
+			}
+		}
+		t.offset += len(txt)
+		return token, nil
 	}
 	if t.Scanner.Err() != nil {
 		return nil, t.Scanner.Err()
+	} else if t.quote != "" {
+		return nil, &UnterminatedError{string(t.quote), t.start}
 	} else {
 		return nil, io.EOF
 	}
@@ -74,6 +120,53 @@ func IsComplete(v string) bool {
 	return sqlite3.IsComplete(v)
 }
 
+// CountParameters returns the number of positional parameters and the names
+// of named parameters in the input SQL statement. Anonymous parameters (?) are
+// numbered in sequence and numbered parameters (?NNN) set the position, so the
+// positional count is the highest position used. Named parameters (:name, @name
+// and $name) are returned in order of first appearance. Placeholders within
+// quoted strings or identifiers are ignored.
+func CountParameters(v string) (int, []string) {
+	var positional, index int
+	var named []string
+	var quote PuncuationToken
+
+	t := NewTokenizer(v)
+	for {
+		token, err := t.Next()
+		if token == nil || err != nil {
+			break
+		}
+		switch token := token.(type) {
+		case PuncuationToken:
+			if quote == "" && isQuote(token) {
+				quote = token
+			} else if token == quote {
+				quote = ""
+			}
+		case ParameterToken:
+			if quote != "" {
+				continue
+			}
+			switch {
+			case token == "?":
+				index = index + 1
+			case token[0] == '?':
+				index, _ = strconv.Atoi(string(token[1:]))
+			default:
+				if !stringSliceContains(named, string(token)) {
+					named = append(named, string(token))
+				}
+				continue
+			}
+			if index > positional {
+				positional = index
+			}
+		}
+	}
+	return positional, named
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
@@ -88,12 +181,19 @@ func toToken(v string) interface{} {
 		return NameToken(v)
 	} else if reNumber.MatchString(v) {
 		return ValueToken(v)
+	} else if reParameter.MatchString(v) {
+		return ParameterToken(v)
 	} else {
 		return PuncuationToken(v)
 	}
 }
 
 func sqlSplit(data []byte, atEOF bool) (int, []byte, error) {
+	// Return at end of input
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
 	advance, token, err := bufio.ScanWords(data, atEOF)
 	if err != nil {
 		return advance, token, err
@@ -104,6 +204,9 @@ func sqlSplit(data []byte, atEOF bool) (int, []byte, error) {
 	if width == 0 {
 		return 0, token, ErrBadParameter.With("Invalid string")
 	}
+	if isParameter(r) {
+		return parameterSplit(data, width, atEOF)
+	}
 	if !(unicode.IsDigit(r) || unicode.IsLetter(r) || r == '_') {
 		return width, []byte(string(r)), nil
 	}
@@ -122,3 +225,48 @@ func sqlSplit(data []byte, atEOF bool) (int, []byte, error) {
 	// Return a word
 	return advance, token, nil
 }
+
+// isQuote returns true if the token opens or closes a string or identifier
+func isQuote(token PuncuationToken) bool {
+	return token == "'" || token == "\"" || token == "`"
+}
+
+// isParameter returns true if the rune can start a bind parameter
+func isParameter(r rune) bool {
+	return r == '?' || r == ':' || r == '@' || r == '$'
+}
+
+// parameterSplit returns a bind parameter token. Anonymous and numbered
+// parameters are followed by digits, named parameters by an identifier. If
+// the prefix is not followed by a name, it is returned as punctuation
+func parameterSplit(data []byte, width int, atEOF bool) (int, []byte, error) {
+	i := width
+	for i < len(data) {
+		r, w := utf8.DecodeRune(data[i:])
+		if w == 0 {
+			return 0, nil, ErrBadParameter.With("Invalid string")
+		}
+		if data[0] == '?' && !unicode.IsDigit(r) {
+			break
+		} else if data[0] != '?' && !(unicode.IsDigit(r) || unicode.IsLetter(r) || r == '_') {
+			break
+		} else if data[0] != '?' && i == width && unicode.IsDigit(r) {
+			break
+		}
+		i += w
+	}
+	if i == len(data) && !atEOF {
+		// Request more data
+		return 0, nil, nil
+	}
+	return i, data[:i], nil
+}
+
+func stringSliceContains(slice []string, v string) bool {
+	for _, elem := range slice {
+		if elem == v {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,8 @@
 package tokenizer_test
 
 import (
+	"errors"
+	"io"
 	"testing"
 
 	// Namespace Imports
@@ -32,3 +34,110 @@ func Test_Tokenizer_001(t *testing.T) {
 		}
 	}
 }
+
+func Test_Tokenizer_002(t *testing.T) {
+	var tests = []struct {
+		In       string
+		Expected []ParameterToken
+	}{
+		{"SELECT * FROM foo WHERE a=?", []ParameterToken{"?"}},
+		{"SELECT * FROM foo WHERE a=? AND b=?", []ParameterToken{"?", "?"}},
+		{"SELECT * FROM foo WHERE a=?1 AND b=?23", []ParameterToken{"?1", "?23"}},
+		{"SELECT * FROM foo WHERE a=:name AND b=:name_2", []ParameterToken{":name", ":name_2"}},
+		{"SELECT * FROM foo WHERE a=@name", []ParameterToken{"@name"}},
+		{"SELECT * FROM foo WHERE a=$name", []ParameterToken{"$name"}},
+		{"SELECT * FROM foo WHERE a=(?,:b)", []ParameterToken{"?", ":b"}},
+		{"SELECT * FROM foo WHERE a=: AND b=@", []ParameterToken{}},
+	}
+	for _, test := range tests {
+		tokenizer := NewTokenizer(test.In)
+		params := []ParameterToken{}
+		for {
+			token, err := tokenizer.Next()
+			if token == nil {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if param, ok := token.(ParameterToken); ok {
+				params = append(params, param)
+			}
+		}
+		if len(params) != len(test.Expected) {
+			t.Errorf("%q: unexpected parameters %q", test.In, params)
+			continue
+		}
+		for i := range params {
+			if params[i] != test.Expected[i] {
+				t.Errorf("%q: unexpected parameter %q, expected %q", test.In, params[i], test.Expected[i])
+			}
+		}
+	}
+}
+
+func Test_Tokenizer_003(t *testing.T) {
+	var tests = []struct {
+		In         string
+		Positional int
+		Named      []string
+	}{
+		{"SELECT 1", 0, nil},
+		{"SELECT ?, ?, ?", 3, nil},
+		{"SELECT ?3, ?", 4, nil},
+		{"SELECT ?2, ?1, ?2", 2, nil},
+		{"SELECT :a, @b, $c, :a", 0, []string{":a", "@b", "$c"}},
+		{"SELECT ?, :a", 1, []string{":a"}},
+		{"SELECT '?', ':a', \"@b\", ?", 1, nil},
+	}
+	for _, test := range tests {
+		positional, named := CountParameters(test.In)
+		if positional != test.Positional {
+			t.Errorf("%q: unexpected positional count %d, expected %d", test.In, positional, test.Positional)
+		}
+		if len(named) != len(test.Named) {
+			t.Errorf("%q: unexpected named %q, expected %q", test.In, named, test.Named)
+			continue
+		}
+		for i := range named {
+			if named[i] != test.Named[i] {
+				t.Errorf("%q: unexpected named %q, expected %q", test.In, named, test.Named)
+			}
+		}
+	}
+}
+
+func Test_Tokenizer_004(t *testing.T) {
+	var tests = []struct {
+		In     string
+		Quote  string
+		Offset int
+	}{
+		{"SELECT 'value", "'", 7},
+		{`SELECT "name FROM foo`, `"`, 7},
+		{`SELECT 'it''s', "a" FROM 'foo`, "'", 25},
+		{`SELECT 'a "b'`, "", 0},
+		{`SELECT "a", 'b'`, "", 0},
+	}
+	for _, test := range tests {
+		tokenizer := NewTokenizer(test.In)
+		var err error
+		for err == nil {
+			_, err = tokenizer.Next()
+		}
+		if test.Quote == "" {
+			if err != io.EOF {
+				t.Errorf("%q: Expected io.EOF, got %v", test.In, err)
+			}
+			continue
+		}
+		var unterminated *UnterminatedError
+		if !errors.Is(err, ErrUnterminatedString) {
+			t.Errorf("%q: Expected ErrUnterminatedString, got %v", test.In, err)
+		} else if !errors.As(err, &unterminated) {
+			t.Errorf("%q: Expected UnterminatedError, got %T", test.In, err)
+		} else if unterminated.Quote != test.Quote || unterminated.Offset != test.Offset {
+			t.Errorf("%q: Unexpected error %v", test.In, err)
+		}
+	}
+}
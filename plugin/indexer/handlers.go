@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	// Namespace imports
 	. "github.com/mutablelogic/go-server"
 	. "github.com/mutablelogic/go-sqlite"
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -39,6 +41,9 @@ type QueryRequest struct {
 	Offset  uint   `json:"offset"`  // Offset within the result set
 	Limit   uint   `json:"limit"`   // Limit the results
 	Snippet bool   `json:"snippet"` // Whether to generate a snippet
+	Facets  bool   `json:"facets"`  // Whether to count results by ext and parent
+	Dedupe  bool   `json:"dedupe"`  // Whether to collapse files with the same content
+	Explain bool   `json:"explain"` // Whether to return the query plan
 }
 
 type QueryResponse struct {
@@ -46,6 +51,19 @@ type QueryResponse struct {
 	Offset  uint             `json:"offset,omitempty"`
 	Limit   uint             `json:"limit,omitempty"`
 	Results []ResultResponse `json:"results"`
+	Facets  *FacetResponse   `json:"facets,omitempty"`
+	Plan    []PlanResponse   `json:"plan,omitempty"`
+}
+
+type FacetResponse struct {
+	Ext    map[string]int64 `json:"ext"`
+	Parent map[string]int64 `json:"parent"`
+}
+
+type PlanResponse struct {
+	Id     int64  `json:"id"`
+	Parent int64  `json:"parent"`
+	Detail string `json:"detail"`
 }
 
 type ResultResponse struct {
@@ -54,6 +72,7 @@ type ResultResponse struct {
 	Rank    float64      `json:"rank"`
 	Index   string       `json:"index"`
 	Snippet string       `json:"snippet,omitempty"`
+	Hash    string       `json:"hash,omitempty"`
 	File    FileResponse `json:"file"`
 }
 
@@ -73,6 +92,7 @@ type FileResponse struct {
 var (
 	reRoutePing  = regexp.MustCompile(`^/?$`)
 	reRouteQuery = regexp.MustCompile(`^/q/?$`)
+	reRouteIndex = regexp.MustCompile(`^/([A-Za-z0-9\_\-]+)/(pause|resume|cancel)/?$`)
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -96,6 +116,11 @@ func (p *plugin) AddHandlers(ctx context.Context, provider Provider) error {
 		return err
 	}
 
+	// Add handler to pause, resume or cancel reindexing
+	if err := provider.AddHandlerFuncEx(ctx, reRouteIndex, p.ServeIndex, http.MethodPost); err != nil {
+		return err
+	}
+
 	// Return success
 	return nil
 }
@@ -188,7 +213,21 @@ func (p *plugin) ServeQuery(w http.ResponseWriter, req *http.Request) {
 
 	// Perform the query and collate the results
 	if err := conn.Do(req.Context(), 0, func(txn SQTransaction) error {
-		q := indexer.Query(p.store.Schema(), query.Snippet).WithLimitOffset(query.Limit, query.Offset)
+		if query.Facets {
+			if facets, err := p.facets(txn, query.Query); err != nil {
+				return err
+			} else {
+				response.Facets = facets
+			}
+		}
+		q := indexer.Query(p.store.Schema(), query.Snippet, query.Dedupe).WithLimitOffset(query.Limit, query.Offset)
+		if query.Explain {
+			if plan, err := p.plan(txn, q, query.Query); err != nil {
+				return err
+			} else {
+				response.Plan = plan
+			}
+		}
 		r, err := txn.Query(q, query.Query)
 		if err != nil {
 			return err
@@ -201,12 +240,14 @@ func (p *plugin) ServeQuery(w http.ResponseWriter, req *http.Request) {
 			} else {
 				n = n + 1
 			}
+			hash, _ := rows[11].(string)
 			response.Results = append(response.Results, ResultResponse{
 				Id:      rows[0].(int64),
 				Offset:  n + int64(query.Offset) - 1,
 				Rank:    rows[1].(float64),
 				Snippet: rows[2].(string),
 				Index:   rows[3].(string),
+				Hash:    hash,
 				File: FileResponse{
 					Path:     rows[4].(string),
 					Parent:   rows[5].(string),
@@ -227,9 +268,77 @@ func (p *plugin) ServeQuery(w http.ResponseWriter, req *http.Request) {
 	router.ServeJSON(w, response, http.StatusOK, 2)
 }
 
+func (p *plugin) ServeIndex(w http.ResponseWriter, req *http.Request) {
+	// Decode params, params[0] is the index name and params[1] is the action
+	params := router.RequestParams(req)
+	idx, exists := p.index[params[0]]
+	if !exists {
+		router.ServeError(w, http.StatusNotFound, "index not found: "+strconv.Quote(params[0]))
+		return
+	}
+
+	// Perform the action
+	var err error
+	switch params[1] {
+	case "pause":
+		err = idx.Pause()
+	case "resume":
+		err = idx.Resume()
+	case "cancel":
+		err = idx.Cancel(req.Context())
+	}
+	if err != nil {
+		router.ServeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	// Serve response
+	router.ServeJSON(w, IndexResponse{
+		Name:    idx.Name(),
+		Path:    idx.Path(),
+		Modtime: p.modtimeForIndex(idx.Name()),
+		Status:  p.statusForIndex(idx.Name()),
+	}, http.StatusOK, 2)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// facets returns counts of the matched set grouped by ext and parent,
+// before limit and offset are applied
+func (p *plugin) facets(txn SQTransaction, q string) (*FacetResponse, error) {
+	var err error
+	facets := new(FacetResponse)
+	if facets.Ext, err = indexer.Facets(txn, p.store.Schema(), "ext", q); err != nil {
+		return nil, err
+	}
+	if facets.Parent, err = indexer.Facets(txn, p.store.Schema(), "parent", q); err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+func (p *plugin) plan(txn SQTransaction, st SQStatement, q string) ([]PlanResponse, error) {
+	r, err := txn.Query(ExplainQueryPlan(st), q)
+	if err != nil {
+		return nil, err
+	}
+	var plan []PlanResponse
+	for {
+		row := r.Next()
+		if row == nil {
+			break
+		}
+		if len(row) == 4 {
+			id, _ := row[0].(int64)
+			parent, _ := row[1].(int64)
+			detail, _ := row[3].(string)
+			plan = append(plan, PlanResponse{id, parent, detail})
+		}
+	}
+	return plan, nil
+}
+
 func (p *plugin) pathForIndex(name string) string {
 	if idx, exists := p.index[name]; exists {
 		return idx.Path()
@@ -249,8 +358,12 @@ func (p *plugin) modtimeForIndex(name string) interface{} {
 func (p *plugin) statusForIndex(name string) string {
 	if idx, exists := p.index[name]; !exists {
 		return ""
+	} else if idx.IsPaused() {
+		return "paused"
 	} else if idx.IsIndexing() {
 		return "indexing"
+	} else if idx.IsInterrupted() {
+		return "interrupted"
 	} else if t, exists := p.modtime[name]; exists && t.IsZero() == false {
 		return "ready"
 	} else {
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// Packages
+	indexer "github.com/mutablelogic/go-sqlite/pkg/indexer"
+	sqlite3 "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+)
+
+func Test_Handlers_001(t *testing.T) {
+	pool, err := sqlite3.NewPool(":memory:", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	store := indexer.NewStore(pool, "main", indexer.NewQueue(), nil, 1)
+	if store == nil {
+		t.Fatal("Unexpected nil store")
+	}
+	p := &plugin{pool: pool, store: store}
+
+	// Create the schema
+	conn := pool.Get()
+	if err := indexer.CreateSchema(context.Background(), conn, "main", ""); err != nil {
+		t.Fatal(err)
+	}
+	pool.Put(conn)
+
+	var tests = []struct {
+		url     string
+		explain bool
+	}{
+		{"/?q=report", false},
+		{"/?q=report&explain=1", true},
+		{"/?q=report&explain=0", false},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.url, nil)
+		w := httptest.NewRecorder()
+		p.ServeQuery(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: Expected status %d, got %d", test.url, http.StatusOK, w.Code)
+			continue
+		}
+		var response QueryResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Error(err)
+		} else if test.explain && len(response.Plan) == 0 {
+			t.Errorf("%s: Expected query plan", test.url)
+		} else if !test.explain && response.Plan != nil {
+			t.Errorf("%s: Unexpected query plan: %v", test.url, response.Plan)
+		}
+		for _, plan := range response.Plan {
+			if plan.Detail == "" {
+				t.Errorf("%s: Expected plan detail", test.url)
+			}
+		}
+	}
+}
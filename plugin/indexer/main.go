@@ -226,22 +226,22 @@ FOR_LOOP:
 // PRIVATE METHODS
 
 func (p *plugin) hasSchema(v string) (string, error) {
-	conn := p.pool.Get()
-	if conn == nil {
-		return "", ErrInternalAppError.With("unable to get connection")
-	}
-	defer p.pool.Put(conn)
-
 	if v == "" {
 		v = sqlite3.DefaultSchema
 	}
-	for _, schema := range conn.Schemas() {
-		if schema == v {
-			return v, nil
+	if err := p.pool.WithConn(context.Background(), func(conn SQConnection) error {
+		for _, schema := range conn.Schemas() {
+			if schema == v {
+				return nil
+			}
 		}
+		return ErrNotFound.Withf("schema not found: %q", v)
+	}); err != nil {
+		return "", err
 	}
 
-	return "", ErrNotFound.Withf("schema not found: %q", v)
+	// Return success
+	return v, nil
 }
 
 // Return the next index to be reindexed
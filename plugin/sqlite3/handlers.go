@@ -10,13 +10,16 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 
 	// Packages
 	router "github.com/mutablelogic/go-server/pkg/httprouter"
 	sqlite3 "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
 	tokenizer "github.com/mutablelogic/go-sqlite/pkg/tokenizer"
+	sys "github.com/mutablelogic/go-sqlite/sys/sqlite3"
 
 	// Namespace imports
+	. "github.com/djthorpe/go-errors"
 	. "github.com/mutablelogic/go-server"
 	. "github.com/mutablelogic/go-sqlite"
 	. "github.com/mutablelogic/go-sqlite/pkg/lang"
@@ -26,10 +29,13 @@ import (
 // TYPES
 
 type PingResponse struct {
-	Version string       `json:"version"`
-	Modules []string     `json:"modules"`
-	Schemas []string     `json:"schemas"`
-	Pool    PoolResponse `json:"pool"`
+	Version  string       `json:"version"`
+	Modules  []string     `json:"modules"`
+	Schemas  []string     `json:"schemas"`
+	Filename string       `json:"filename,omitempty"`
+	Memory   bool         `json:"memory,omitempty"`
+	ReadOnly bool         `json:"readonly,omitempty"`
+	Pool     PoolResponse `json:"pool"`
 }
 
 type PoolResponse struct {
@@ -42,15 +48,18 @@ type SchemaResponse struct {
 	Filename string                 `json:"filename,omitempty"`
 	Memory   bool                   `json:"memory,omitempty"`
 	Tables   []SchemaTableResponse  `json:"tables,omitempty"`
+	Views    []string               `json:"views,omitempty"`
+	Triggers []string               `json:"triggers,omitempty"`
 	Columns  []SchemaColumnResponse `json:"columns,omitempty"`
 }
 
 type SchemaTableResponse struct {
-	Name    string                 `json:"name"`
-	Schema  string                 `json:"schema"`
-	Count   int64                  `json:"count"`
-	Indexes []SchemaIndexResponse  `json:"indexes,omitempty"`
-	Columns []SchemaColumnResponse `json:"columns,omitempty"`
+	Name     string                 `json:"name"`
+	Schema   string                 `json:"schema"`
+	Count    int64                  `json:"count"`
+	Indexes  []SchemaIndexResponse  `json:"indexes,omitempty"`
+	Triggers []string               `json:"triggers,omitempty"`
+	Columns  []SchemaColumnResponse `json:"columns,omitempty"`
 }
 
 type SchemaColumnResponse struct {
@@ -62,14 +71,40 @@ type SchemaColumnResponse struct {
 	Nullable bool   `json:"nullable,omitempty"`
 }
 
+type SchemaDescribeResponse struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+	Sql    string `json:"sql"`
+}
+
 type SchemaIndexResponse struct {
 	Name    string   `json:"name"`
 	Unique  bool     `json:"unique"`
 	Columns []string `json:"columns"`
 }
 
+// SqlRequest is either a raw SQL statement, or a structured query on a
+// table when Table is set. Values in a structured query are always bound
+// as parameters.
 type SqlRequest struct {
-	Sql string `json:"sql"`
+	Sql    string             `json:"sql,omitempty"`
+	Schema string             `json:"schema,omitempty"`
+	Table  string             `json:"table,omitempty"`
+	Where  []SqlFilterRequest `json:"where,omitempty"`
+	Order  []SqlOrderRequest  `json:"order,omitempty"`
+	Limit  uint               `json:"limit,omitempty"`
+	Offset uint               `json:"offset,omitempty"`
+}
+
+type SqlFilterRequest struct {
+	Column string      `json:"column"`
+	Op     string      `json:"op"`
+	Value  interface{} `json:"value,omitempty"`
+}
+
+type SqlOrderRequest struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc,omitempty"`
 }
 
 type SqlResultResponse struct {
@@ -80,11 +115,13 @@ type SqlResultResponse struct {
 	RowsAffected int                    `json:"rows_affected,omitempty"`
 	Columns      []SchemaColumnResponse `json:"columns,omitempty"`
 	Results      []interface{}          `json:"results,omitempty"`
+	Truncated    bool                   `json:"truncated,omitempty"`
 }
 
 type TokenizerResponse struct {
 	Html     []template.HTML `json:"html,omitempty"`
 	Complete bool            `json:"complete"`
+	Reason   string          `json:"reason,omitempty"`
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -94,6 +131,7 @@ var (
 	reRoutePing      = regexp.MustCompile(`^/?$`)
 	reRouteSchema    = regexp.MustCompile(`^/([a-zA-Z][a-zA-Z0-9_-]+)/?$`)
 	reRouteTable     = regexp.MustCompile(`^/([a-zA-Z][a-zA-Z0-9_-]+)/([^/]+)/?$`)
+	reRouteDescribe  = regexp.MustCompile(`^/([a-zA-Z][a-zA-Z0-9_-]+)/([^/]+)/describe/?$`)
 	reRouteTokenizer = regexp.MustCompile(`^/-/tokenizer/?$`)
 	reRouteQuery     = regexp.MustCompile(`^/-/q/?$`)
 )
@@ -105,6 +143,27 @@ const (
 	maxResultLimit = 1000
 )
 
+var (
+	// Operators which can be used in a structured query filter
+	filterOps = map[string]string{
+		"=":           "=",
+		"==":          "=",
+		"!=":          "<>",
+		"<>":          "<>",
+		"<":           "<",
+		"<=":          "<=",
+		">":           ">",
+		">=":          ">=",
+		"LIKE":        "LIKE",
+		"NOT LIKE":    "NOT LIKE",
+		"GLOB":        "GLOB",
+		"IN":          "IN",
+		"NOT IN":      "NOT IN",
+		"IS NULL":     "IS NULL",
+		"IS NOT NULL": "IS NOT NULL",
+	}
+)
+
 ///////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
@@ -124,6 +183,11 @@ func (p *plugin) AddHandlers(ctx context.Context, provider Provider) error {
 		return err
 	}
 
+	// Add handler for table DDL
+	if err := provider.AddHandlerFuncEx(ctx, reRouteDescribe, p.ServeDescribe); err != nil {
+		return err
+	}
+
 	// Add handler for SQL tokenizer
 	if err := provider.AddHandlerFuncEx(ctx, reRouteTokenizer, p.ServeTokenizer, http.MethodPost); err != nil {
 		return err
@@ -158,6 +222,9 @@ func (p *plugin) ServePing(w http.ResponseWriter, req *http.Request) {
 	response.Version = sqlite3.Version()
 	response.Schemas = append(response.Schemas, conn.Schemas()...)
 	response.Modules = append(response.Modules, conn.Modules()...)
+	response.Filename = conn.Filename(sqlite3.DefaultSchema)
+	response.Memory = conn.Flags().Is(SQFlag(sys.SQLITE_OPEN_MEMORY))
+	response.ReadOnly = conn.Flags().Is(SQFlag(sys.SQLITE_OPEN_READONLY))
 	response.Pool = PoolResponse{Cur: p.pool.Cur(), Max: p.pool.Max()}
 
 	// Serve response
@@ -187,6 +254,8 @@ func (p *plugin) ServeSchema(w http.ResponseWriter, req *http.Request) {
 		Schema:   params[0],
 		Filename: conn.Filename(params[0]),
 		Tables:   []SchemaTableResponse{},
+		Views:    conn.Views(params[0]),
+		Triggers: conn.Triggers(params[0], ""),
 	}
 
 	// Set memory flag
@@ -197,11 +266,12 @@ func (p *plugin) ServeSchema(w http.ResponseWriter, req *http.Request) {
 	// Populate tables
 	for _, name := range conn.Tables(params[0]) {
 		table := SchemaTableResponse{
-			Name:    name,
-			Schema:  params[0],
-			Count:   conn.Count(params[0], name),
-			Columns: []SchemaColumnResponse{},
-			Indexes: []SchemaIndexResponse{},
+			Name:     name,
+			Schema:   params[0],
+			Count:    conn.CountApprox(params[0], name),
+			Columns:  []SchemaColumnResponse{},
+			Indexes:  []SchemaIndexResponse{},
+			Triggers: conn.Triggers(params[0], name),
 		}
 		for _, index := range conn.IndexesForTable(params[0], name) {
 			table.Indexes = append(table.Indexes, SchemaIndexResponse{
@@ -259,7 +329,7 @@ func (p *plugin) ServeTable(w http.ResponseWriter, req *http.Request) {
 	// Populate response
 	var response SqlResultResponse
 	if err := conn.Do(req.Context(), SQLITE_TXN_DEFAULT, func(txn SQTransaction) error {
-		r, err := txn.Query(S(N(params[1]).WithSchema(params[0])).WithLimitOffset(q.Limit, q.Offset))
+		r, err := txn.QueryLimited(S(N(params[1]).WithSchema(params[0])).WithLimitOffset(q.Limit, q.Offset), maxResultLimit)
 		if err != nil {
 			return err
 		}
@@ -281,6 +351,43 @@ func (p *plugin) ServeTable(w http.ResponseWriter, req *http.Request) {
 	router.ServeJSON(w, response, http.StatusOK, 2)
 }
 
+func (p *plugin) ServeDescribe(w http.ResponseWriter, req *http.Request) {
+	// Decode params, params[0] is the schema name and params[1] is the table name
+	params := router.RequestParams(req)
+
+	// Get a connection
+	conn := p.Get()
+	if conn == nil {
+		router.ServeError(w, http.StatusBadGateway, "No connection")
+		return
+	}
+	defer p.Put(conn)
+
+	// Check for schema
+	if !stringSliceContainsElement(conn.Schemas(), params[0]) {
+		router.ServeError(w, http.StatusNotFound, "Schema not found", strconv.Quote(params[0]))
+		return
+	}
+
+	// Populate response
+	response := SchemaDescribeResponse{
+		Name:   params[1],
+		Schema: params[0],
+	}
+	if sql, err := conn.TableDDL(params[0], params[1]); errors.Is(err, ErrNotFound) {
+		router.ServeError(w, http.StatusNotFound, "Table not found", strconv.Quote(params[1]))
+		return
+	} else if err != nil {
+		router.ServeError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else {
+		response.Sql = sql
+	}
+
+	// Serve response
+	router.ServeJSON(w, response, http.StatusOK, 2)
+}
+
 func (p *plugin) ServeTokenizer(w http.ResponseWriter, req *http.Request) {
 	// Decode request
 	query := SqlRequest{}
@@ -297,9 +404,10 @@ func (p *plugin) ServeTokenizer(w http.ResponseWriter, req *http.Request) {
 	}
 	defer p.Put(conn)
 
-	// Tokenize input
+	// Tokenize input, an unterminated string is reported as the
+	// reason the statement is incomplete
 	html, err := tokenize(query.Sql)
-	if err != nil {
+	if err != nil && !errors.Is(err, tokenizer.ErrUnterminatedString) {
 		router.ServeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -309,6 +417,9 @@ func (p *plugin) ServeTokenizer(w http.ResponseWriter, req *http.Request) {
 		Html:     html,
 		Complete: tokenizer.IsComplete(query.Sql),
 	}
+	if err != nil {
+		response.Reason = err.Error()
+	}
 
 	// Serve response
 	router.ServeJSON(w, response, http.StatusOK, 2)
@@ -330,10 +441,23 @@ func (p *plugin) ServeQuery(w http.ResponseWriter, req *http.Request) {
 	}
 	defer p.Put(conn)
 
+	// Build a structured query when a table is set
+	st, args := Q(query.Sql), []interface{}{}
+	if query.Table != "" {
+		var err error
+		if st, args, err = structuredQuery(conn, query); errors.Is(err, ErrNotFound) {
+			router.ServeError(w, http.StatusNotFound, err.Error())
+			return
+		} else if err != nil {
+			router.ServeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
 	// Perform query
 	response := make([]SqlResultResponse, 0, 2)
 	if err := conn.Do(req.Context(), SQLITE_TXN_DEFAULT, func(txn SQTransaction) error {
-		r, err := txn.Query(Q(query.Sql))
+		r, err := txn.QueryLimited(st, maxResultLimit, args...)
 		if err != nil {
 			return err
 		}
@@ -376,6 +500,74 @@ func schemaColumn(schema, table string, column SQColumn) SchemaColumnResponse {
 	return result
 }
 
+// structuredQuery returns a select statement and the bound arguments for a
+// structured query, after checking the schema, table and columns exist
+func structuredQuery(conn SQConnection, query SqlRequest) (SQStatement, []interface{}, error) {
+	schema := query.Schema
+	if schema == "" {
+		schema = sqlite3.DefaultSchema
+	}
+
+	// Check for schema and table
+	if !stringSliceContainsElement(conn.Schemas(), schema) {
+		return nil, nil, ErrNotFound.With("Schema not found: ", strconv.Quote(schema))
+	} else if !stringSliceContainsElement(conn.Tables(schema), query.Table) {
+		return nil, nil, ErrNotFound.With("Table not found: ", strconv.Quote(query.Table))
+	}
+
+	// Get column names for the table
+	columns := []string{}
+	for _, column := range conn.ColumnsForTable(schema, query.Table) {
+		columns = append(columns, column.Name())
+	}
+
+	// Translate filters into expressions, with values as bound parameters
+	where, args := []interface{}{}, []interface{}{}
+	for _, filter := range query.Where {
+		if !stringSliceContainsElement(columns, filter.Column) {
+			return nil, nil, ErrBadParameter.With("Column not found: ", strconv.Quote(filter.Column))
+		}
+		op, exists := filterOps[strings.ToUpper(strings.TrimSpace(filter.Op))]
+		if !exists {
+			return nil, nil, ErrBadParameter.With("Unsupported operator: ", strconv.Quote(filter.Op))
+		}
+		switch op {
+		case "IS NULL":
+			where = append(where, N(filter.Column).IsNull())
+		case "IS NOT NULL":
+			where = append(where, N(filter.Column).IsNotNull())
+		case "IN", "NOT IN":
+			if _, ok := filter.Value.([]interface{}); !ok {
+				return nil, nil, ErrBadParameter.With("Expected array value for ", strconv.Quote(filter.Column))
+			}
+			where = append(where, Q(N(filter.Column), " ", op, " (", P, ")"))
+			args = append(args, filter.Value)
+		default:
+			if filter.Value == nil {
+				return nil, nil, ErrBadParameter.With("Missing value for ", strconv.Quote(filter.Column))
+			}
+			where = append(where, Q(N(filter.Column), " ", op, " ", P))
+			args = append(args, filter.Value)
+		}
+	}
+
+	// Set order
+	order := []SQSource{}
+	for _, o := range query.Order {
+		if !stringSliceContainsElement(columns, o.Column) {
+			return nil, nil, ErrBadParameter.With("Column not found: ", strconv.Quote(o.Column))
+		} else if o.Desc {
+			order = append(order, N(o.Column).WithDesc())
+		} else {
+			order = append(order, N(o.Column))
+		}
+	}
+
+	// Return the statement, limiting the number of results
+	st := S(N(query.Table).WithSchema(schema)).Where(where...).Order(order...)
+	return st.WithLimitOffset(uintMin(query.Limit, maxResultLimit), query.Offset), args, nil
+}
+
 func results(r SQResults) (SqlResultResponse, error) {
 	result := SqlResultResponse{
 		Sql:          r.ExpandedSQL(),
@@ -390,7 +582,8 @@ func results(r SQResults) (SqlResultResponse, error) {
 		result.Columns = append(result.Columns, schemaColumn(schema, table, column))
 	}
 
-	// Iterate through the rows, break when maximum number of results is reached
+	// Iterate through the rows, which stop when the maximum number of
+	// results is reached
 	for {
 		row := r.Next()
 		if row == nil {
@@ -398,10 +591,8 @@ func results(r SQResults) (SqlResultResponse, error) {
 		} else {
 			result.Results = append(result.Results, interfaceSliceCopy(row))
 		}
-		if len(result.Results) >= maxResultLimit {
-			break
-		}
 	}
+	result.Truncated = r.Truncated()
 
 	// Return success
 	return result, nil
@@ -422,7 +613,8 @@ func stringSliceContainsElement(v []string, elem string) bool {
 	return false
 }
 
-// tokenize will return an array of html spans, one for each token in the input
+// tokenize will return an array of html spans, one for each token in the input.
+// If a string is not terminated, the tokens are returned with the error
 func tokenize(v string) ([]template.HTML, error) {
 	result := []template.HTML{}
 
@@ -430,10 +622,14 @@ func tokenize(v string) ([]template.HTML, error) {
 	t := tokenizer.NewTokenizer(v)
 	for {
 		token, err := t.Next()
-		if token == nil || err == io.EOF {
+		if err == io.EOF {
 			break
+		} else if errors.Is(err, tokenizer.ErrUnterminatedString) {
+			return result, err
 		} else if err != nil {
 			return nil, err
+		} else if token == nil {
+			break
 		}
 		switch t := token.(type) {
 		case tokenizer.KeywordToken:
@@ -444,6 +640,8 @@ func tokenize(v string) ([]template.HTML, error) {
 			result = appendtoken(result, "name", t)
 		case tokenizer.ValueToken:
 			result = appendtoken(result, "value", t)
+		case tokenizer.ParameterToken:
+			result = appendtoken(result, "parameter", t)
 		case tokenizer.PuncuationToken:
 			result = appendtoken(result, "puncuation", t)
 		case tokenizer.WhitespaceToken:
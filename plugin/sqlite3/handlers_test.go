@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// Packages
+	provider "github.com/mutablelogic/go-server/pkg/provider"
+	sqlite3 "github.com/mutablelogic/go-sqlite/pkg/sqlite3"
+
+	// Namespace imports
+	. "github.com/mutablelogic/go-sqlite/pkg/lang"
+)
+
+func Test_Handlers_001(t *testing.T) {
+	errs := make(chan error)
+	defer close(errs)
+	go func() {
+		for err := range errs {
+			t.Error(err)
+		}
+	}()
+	pool, err := sqlite3.NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	p := &plugin{pool: pool}
+
+	// Create a table with rows
+	conn := pool.Get()
+	if err := conn.Exec(N("test").CreateTable(C("a").WithType("TEXT"), C("b").WithType("INTEGER")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(Q("INSERT INTO test (a, b) VALUES ('apple', 1), ('banana', 2), ('cherry', 3), (NULL, 4)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	pool.Put(conn)
+
+	var tests = []struct {
+		body     string
+		status   int
+		expected []interface{}
+	}{
+		{`{"table":"test","where":[{"column":"b","op":">","value":1}],"order":[{"column":"b","desc":true}]}`, http.StatusOK, []interface{}{nil, "cherry", "banana"}},
+		{`{"table":"test","where":[{"column":"a","op":"like","value":"%an%"}]}`, http.StatusOK, []interface{}{"banana"}},
+		{`{"table":"test","where":[{"column":"a","op":"IN","value":["apple","cherry"]}],"order":[{"column":"a"}]}`, http.StatusOK, []interface{}{"apple", "cherry"}},
+		{`{"table":"test","where":[{"column":"a","op":"IS NULL"}]}`, http.StatusOK, []interface{}{nil}},
+		{`{"table":"test","where":[{"column":"a","op":"=","value":"x' OR 1=1 --"}]}`, http.StatusOK, nil},
+		{`{"table":"test","order":[{"column":"b"}],"limit":1,"offset":1}`, http.StatusOK, []interface{}{"banana"}},
+		{`{"table":"test","where":[{"column":"a","op":"; DROP TABLE test","value":1}]}`, http.StatusBadRequest, nil},
+		{`{"table":"test","where":[{"column":"c","op":"=","value":1}]}`, http.StatusBadRequest, nil},
+		{`{"table":"other"}`, http.StatusNotFound, nil},
+		{`{"schema":"other","table":"test"}`, http.StatusNotFound, nil},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/-/q", bytes.NewBufferString(test.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		p.ServeQuery(w, req)
+		if w.Code != test.status {
+			t.Errorf("%s: Expected status %d, got %d", test.body, test.status, w.Code)
+			continue
+		} else if w.Code != http.StatusOK {
+			continue
+		}
+		var response []SqlResultResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Error(err)
+			continue
+		} else if len(response) != 1 {
+			t.Errorf("%s: Expected one result, got %d", test.body, len(response))
+			continue
+		}
+		var values []interface{}
+		for _, row := range response[0].Results {
+			values = append(values, row.([]interface{})[0])
+		}
+		if len(values) != len(test.expected) {
+			t.Errorf("%s: Expected %v, got %v", test.body, test.expected, values)
+			continue
+		}
+		for i := range values {
+			if values[i] != test.expected[i] {
+				t.Errorf("%s: Expected %v, got %v", test.body, test.expected, values)
+				break
+			}
+		}
+	}
+}
+
+func Test_Handlers_002(t *testing.T) {
+	errs := make(chan error)
+	defer close(errs)
+	go func() {
+		for err := range errs {
+			t.Error(err)
+		}
+	}()
+	pool, err := sqlite3.NewPool(":memory:", errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	p := &plugin{pool: pool}
+
+	// Create a table with an index
+	conn := pool.Get()
+	if err := conn.Exec(Q("CREATE TABLE test (a TEXT PRIMARY KEY, b INTEGER NOT NULL); CREATE INDEX test_b ON test (b)"), nil); err != nil {
+		t.Fatal(err)
+	}
+	pool.Put(conn)
+
+	var tests = []struct {
+		params   []string
+		status   int
+		expected string
+	}{
+		{[]string{"main", "test"}, http.StatusOK, "CREATE TABLE test (a TEXT PRIMARY KEY, b INTEGER NOT NULL);\nCREATE INDEX test_b ON test (b);\n"},
+		{[]string{"main", "other"}, http.StatusNotFound, ""},
+		{[]string{"other", "test"}, http.StatusNotFound, ""},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/"+test.params[0]+"/"+test.params[1]+"/describe", nil)
+		req = req.WithContext(provider.ContextWithPathParams(req.Context(), req.URL.Path, test.params))
+		w := httptest.NewRecorder()
+		p.ServeDescribe(w, req)
+		if w.Code != test.status {
+			t.Errorf("%v: Expected status %d, got %d", test.params, test.status, w.Code)
+			continue
+		} else if w.Code != http.StatusOK {
+			continue
+		}
+		var response SchemaDescribeResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Error(err)
+		} else if response.Sql != test.expected {
+			t.Errorf("%v: Expected %q, got %q", test.params, test.expected, response.Sql)
+		}
+	}
+}
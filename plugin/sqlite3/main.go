@@ -142,6 +142,10 @@ func (p *plugin) Put(conn SQConnection) {
 	p.pool.Put(conn)
 }
 
+func (p *plugin) WithConn(ctx context.Context, fn func(SQConnection) error) error {
+	return p.pool.WithConn(ctx, fn)
+}
+
 func (p *plugin) Close() error {
 	return ErrInternalAppError.With("sqlite3: cannot call close from plugin")
 }
@@ -2,7 +2,9 @@ package sqlite
 
 import (
 	"io"
+	"log"
 	"net/url"
+	"time"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -35,10 +37,59 @@ type SQImportConfig struct {
 	// LazyQuotes when true indicates the CSV file should allow non-standard quotes.
 	LazyQuotes bool `sqlite:"lazyquotes"`
 
+	// FieldsPermissive when true allows CSV rows with a different number of fields
+	// to the first row, and implies LazyQuotes. Short rows are padded with NULL
+	// values and long rows are truncated.
+	FieldsPermissive bool `sqlite:"fieldspermissive"`
+
+	// Null defines the sentinel value for NULL fields, such as \N or NULL.
+	// Optional.
+	Null string `sqlite:"null"`
+
+	// NullEmpty when true indicates empty fields are imported as NULL. Quoted
+	// empty fields cannot be distinguished and are also imported as NULL.
+	NullEmpty bool `sqlite:"nullempty"`
+
+	// DecimalSep defines the decimal separator for numeric fields, such as
+	// ',' for European formatted numbers. When DecimalSep or ThousandsSep is
+	// set, fields which parse as numbers are imported as INTEGER or REAL
+	// values. Optional, defaults to '.'
+	DecimalSep rune `sqlite:"decimalsep"`
+
+	// ThousandsSep defines the separator between groups of thousands in
+	// numeric fields, such as '.' for European formatted numbers. Optional.
+	ThousandsSep rune `sqlite:"thousandssep"`
+
+	// DateFormats defines the layouts for date and time fields, in the format
+	// used by time.Parse. Fields which parse with any layout are imported as
+	// time values. Optional.
+	DateFormats []string `sqlite:"dateformats"`
+
+	// Log receives warnings, such as when a row is truncated. Optional.
+	Log *log.Logger `sqlite:"log"`
+
 	// Overwrite existing table (will append data otherwise)
 	Overwrite bool `sqlite:"overwrite"`
 }
 
+type SQExportConfig struct {
+	// Header when true indicates the first line of a CSV file is a header
+	Header bool `sqlite:"header"`
+
+	// Delimiter defines the character which indicates a field delimiter. Optional.
+	Delimiter rune `sqlite:"delimiter"`
+
+	// TimeLayout defines the layout for time values. Optional, defaults to RFC3339.
+	TimeLayout string `sqlite:"timelayout"`
+
+	// Location defines the timezone for time values. Optional, defaults to UTC.
+	Location *time.Location `sqlite:"location"`
+
+	// Null defines the sentinel value for NULL fields. Optional, defaults to
+	// an empty field.
+	Null string `sqlite:"null"`
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // INTERFACES
 
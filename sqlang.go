@@ -24,6 +24,10 @@ type SQSource interface {
 	WithType(string) SQColumn
 	WithAlias(string) SQSource
 	WithDesc() SQSource
+	WithCollate(string) SQSource
+
+	// Select all columns of the source in a join (ie, "a.*")
+	All() SQSource
 
 	// Insert, replace or upsert a row with named columns
 	Insert(...string) SQInsert
@@ -38,7 +42,7 @@ type SQSource interface {
 	// Create objects
 	CreateTable(...SQColumn) SQTable
 	CreateVirtualTable(string, ...string) SQIndexView
-	CreateIndex(string, ...string) SQIndexView
+	CreateIndex(string, ...SQSource) SQIndexView
 	CreateTrigger(string, ...SQStatement) SQTrigger
 	CreateView(SQSelect, ...string) SQIndexView
 	ForeignKey(...string) SQForeignKey
@@ -46,6 +50,15 @@ type SQSource interface {
 	// Alter objects
 	AlterTable() SQAlter
 
+	// NULL-safe comparisons
+	IsNull() SQComparison
+	IsNotNull() SQComparison
+	IsDistinctFrom(interface{}) SQComparison
+
+	// Pattern matching comparisons
+	Glob(interface{}) SQComparison
+	Match(interface{}) SQComparison
+
 	// Update and delete data
 	Update(...string) SQUpdate
 	Delete(...interface{}) SQStatement
@@ -83,6 +96,7 @@ type SQUpdate interface {
 	WithIgnore() SQUpdate
 	WithReplace() SQUpdate
 	WithRollback() SQUpdate
+	WithComment(string) SQUpdate
 
 	// Where clause
 	Where(...interface{}) SQUpdate
@@ -136,6 +150,9 @@ type SQInsert interface {
 	DefaultValues() SQInsert
 	WithConflictDoNothing(...string) SQInsert
 	WithConflictUpdate(...string) SQInsert
+	WithConflictResolution(SQConflict) SQInsert
+	WithRows(uint) SQInsert
+	WithComment(string) SQInsert
 }
 
 // SQSelect defines a select statement
@@ -144,14 +161,18 @@ type SQSelect interface {
 
 	// Set select flags
 	WithDistinct() SQSelect
+	DistinctOn(...SQSource) SQSelect
 	WithLimitOffset(limit, offset uint) SQSelect
+	WithComment(string) SQSelect
 
 	// Destination expressions for results
 	To(...SQExpr) SQSelect
 
 	// Where and order clauses
 	Where(...interface{}) SQSelect
+	OrWhere(...interface{}) SQSelect
 	Order(...SQSource) SQSelect
+	OrderBy(...interface{}) SQSelect
 }
 
 // SQAlter defines an alter table statement
@@ -161,6 +182,8 @@ type SQAlter interface {
 	// Alter operation
 	AddColumn(SQColumn) SQStatement
 	DropColumn(SQColumn) SQStatement
+	RenameTo(string) SQStatement
+	RenameColumn(string, string) SQStatement
 }
 
 // SQForeignKey represents a foreign key constraint
@@ -186,6 +209,7 @@ type SQColumn interface {
 	WithPrimary() SQColumn
 	WithAutoIncrement() SQColumn
 	WithDefault(v interface{}) SQColumn
+	WithDefaultExpr(expr string) SQColumn
 	WithDefaultNow() SQColumn
 }
 
@@ -193,3 +217,12 @@ type SQColumn interface {
 type SQExpr interface {
 	String() string
 }
+
+// SQComparison defines a comparison expression, which can be used in a
+// WHERE clause
+type SQComparison interface {
+	SQExpr
+
+	// Return the negated comparison
+	Not() SQComparison
+}
@@ -2,6 +2,7 @@ package sqlite
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
 	"strings"
 )
@@ -17,6 +18,7 @@ const (
 type (
 	SQAuthFlag uint32
 	SQFlag     uint32
+	SQConflict uint
 	SQTxnFunc  func(SQTransaction) error
 	SQExecFunc func(row, col []string) bool
 )
@@ -36,6 +38,10 @@ type SQPool interface {
 	// Return connection to the pool
 	Put(SQConnection)
 
+	// WithConn gets a connection, calls the function with it and always
+	// returns the connection to the pool, returning any error
+	WithConn(context.Context, func(SQConnection) error) error
+
 	// Cur returns the current number of used connections
 	Cur() int
 
@@ -55,11 +61,23 @@ type SQConnection interface {
 	// or cancelled context
 	Do(context.Context, SQFlag, func(SQTransaction) error) error
 
+	// Execute a read-only transaction with context, which does not take
+	// the write lock
+	DoRead(context.Context, func(SQTransaction) error) error
+
 	// Execute a statement outside transacton
 	Exec(SQStatement, SQExecFunc) error
 
 	// Return a unique counter number for the connection
 	Counter() int64
+
+	// LastInsertId returns the rowid of the most recent successful insert
+	// into a rowid table on the connection, or zero
+	LastInsertId() int64
+
+	// SetLastInsertId sets the value returned by LastInsertId, for use
+	// with virtual tables and custom sequences
+	SetLastInsertId(int64)
 }
 
 // SQTransaction is an sqlite transaction
@@ -67,6 +85,10 @@ type SQTransaction interface {
 	// Query and return a set of results
 	Query(SQStatement, ...interface{}) (SQResults, error)
 
+	// QueryLimited returns a set of results which stops after a maximum
+	// number of rows
+	QueryLimited(SQStatement, int, ...interface{}) (SQResults, error)
+
 	// Schemas returns a list of all the schemas in the database
 	Schemas() []string
 
@@ -76,6 +98,14 @@ type SQTransaction interface {
 	// Count returns the number of rows in a table and schema
 	Count(string, string) int64
 
+	// CountApprox returns an estimated number of rows in a table and
+	// schema, without scanning the whole table
+	CountApprox(string, string) int64
+
+	// CountAtLeast returns true if a table and schema has at least
+	// the number of rows, without counting any further rows
+	CountAtLeast(string, string, int64) bool
+
 	// Filename returns a filename for a schema, returns empty
 	// string if in-memory database
 	Filename(string) string
@@ -92,6 +122,15 @@ type SQTransaction interface {
 	// Views returns a list of view names in a schema
 	Views(string) []string
 
+	// Triggers returns a list of trigger names in a schema. If
+	// a table name is provided, then only triggers on that table
+	// are returned
+	Triggers(string, string) []string
+
+	// TableDDL returns the CREATE statements for a schema and table,
+	// including the statements for indexes and triggers on the table
+	TableDDL(string, string) (string, error)
+
 	// Modules returns a list of modules. If an argument is
 	// provided, then only modules with those name prefixes
 	// matched
@@ -109,6 +148,13 @@ type SQResults interface {
 	// if not transient
 	Next(...reflect.Type) []interface{}
 
+	// Return next row as a map of column names to values, or nil when
+	// all rows consumed
+	NextMap() map[string]interface{}
+
+	// Return next row as a JSON object, or io.EOF when all rows consumed
+	NextJSON() (json.RawMessage, error)
+
 	// Close results and discard when done
 	Close() error
 
@@ -129,6 +175,14 @@ type SQResults interface {
 
 	// ColumnTable returns the schema, table and column name for a column index
 	ColumnSource(int) (string, string, string)
+
+	// Truncated returns true if rows were discarded because the results
+	// have more rows than the limit
+	Truncated() bool
+
+	// Err returns an error if rows were discarded because the results
+	// have more rows than the limit
+	Err() error
 }
 
 // SQAuth is an interface for authenticating an action
@@ -155,6 +209,17 @@ const (
 	SQLITE_OPEN_CACHE                    SQFlag = (1 << 20) // Cache prepared statements
 	SQLITE_OPEN_OVERWRITE                SQFlag = (1 << 21) // Overwrite objects
 	SQLITE_OPEN_FOREIGNKEYS              SQFlag = (1 << 22) // Enable foreign key support
+	SQLITE_TXN_READONLY                  SQFlag = (1 << 23) // Deferred transaction which cannot write
+)
+
+// Conflict resolution for an insert statement
+const (
+	SQLITE_CONFLICT_NONE     SQConflict = iota // Use the default conflict resolution
+	SQLITE_CONFLICT_ROLLBACK                   // Rollback the transaction
+	SQLITE_CONFLICT_ABORT                      // Abort the statement
+	SQLITE_CONFLICT_FAIL                       // Fail the statement, keeping prior changes
+	SQLITE_CONFLICT_IGNORE                     // Skip the row
+	SQLITE_CONFLICT_REPLACE                    // Replace the existing row
 )
 
 const (
@@ -248,6 +313,24 @@ func (v SQAuthFlag) String() string {
 	return strings.TrimPrefix(str, "|")
 }
 
+// String returns the conflict resolution keyword, or an empty string
+func (v SQConflict) String() string {
+	switch v {
+	case SQLITE_CONFLICT_ROLLBACK:
+		return "ROLLBACK"
+	case SQLITE_CONFLICT_ABORT:
+		return "ABORT"
+	case SQLITE_CONFLICT_FAIL:
+		return "FAIL"
+	case SQLITE_CONFLICT_IGNORE:
+		return "IGNORE"
+	case SQLITE_CONFLICT_REPLACE:
+		return "REPLACE"
+	default:
+		return ""
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // METHODS
 
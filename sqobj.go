@@ -32,6 +32,12 @@ type SQObjects interface {
 
 // SQClass is a class definition, which can be a table or view
 type SQClass interface {
+	// Name returns the name of the class table or view
+	Name() string
+
+	// Columns returns the column definitions for the class
+	Columns() []SQColumn
+
 	// Create class in the named database schema
 	Create(SQTransaction, string) error
 
@@ -42,12 +48,23 @@ type SQClass interface {
 	// Insert objects, return rowids
 	Insert(SQTransaction, ...interface{}) ([]int64, error)
 
+	// Insert objects with a conflict resolution, return rowids. The rowid
+	// is zero for any object which was ignored
+	InsertWithConflict(SQTransaction, SQConflict, ...interface{}) ([]int64, error)
+
 	// Delete rows in table based on rowid. Returns number of deleted rows
 	DeleteRows(SQTransaction, []int64) (int, error)
 
 	// Delete keys in table based on primary keys. Returns number of deleted rows
 	DeleteKeys(SQTransaction, ...interface{}) (int, error)
 
+	// Delete rows in table which match all the expressions. Returns number of
+	// deleted rows
+	DeleteWhere(SQTransaction, ...interface{}) (int, error)
+
+	// Delete all rows in table. Returns number of deleted rows
+	DeleteAll(SQTransaction) (int, error)
+
 	// Update objects by primary key, return number of updated rows
 	UpdateKeys(SQTransaction, ...interface{}) (int, error)
 
@@ -60,6 +77,10 @@ type SQIterator interface {
 	// Next returns the next object in the iterator, or nil if there are no more
 	Next() interface{}
 
+	// NextInto reads the next object into a pointer to a struct, which can be
+	// reused between calls, and returns false if there are no more
+	NextInto(interface{}) bool
+
 	// RowId returns the last read row, should be called after Next()
 	RowId() int64
 }
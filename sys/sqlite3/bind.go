@@ -1,9 +1,18 @@
 package sqlite3
 
 import (
+	"database/sql/driver"
 	"math"
+	"math/big"
+	"reflect"
+	"strings"
 	"time"
 	"unsafe"
+
+	// Modules
+	marshaler "github.com/djthorpe/go-marshaler"
+	multierror "github.com/hashicorp/go-multierror"
+	sqlite "github.com/mutablelogic/go-sqlite"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -50,13 +59,66 @@ func (s *Statement) BindNamedInterface(name string, value interface{}) error {
 	}
 }
 
+// BindStruct binds the exported fields of a struct, or a pointer to a struct,
+// to named parameters. The parameter name is the name in the "sqlite" struct
+// tag, or the field name if there is no tag, so a field tagged `sqlite:"id"`
+// is bound to :id, @id or $id. Fields which are not referenced by a parameter
+// are ignored, parameters without a matching field are left unbound, and nil
+// pointer fields are bound as NULL.
+func (s *Statement) BindStruct(v interface{}) error {
+	if !isStruct(v) {
+		return SQLITE_MISMATCH
+	}
+
+	// Map field names to values
+	fields := make(map[string]reflect.Value)
+	for _, field := range marshaler.NewEncoder(sqlite.TagName).Reflect(v) {
+		if field != nil {
+			fields[field.Name] = field.Value
+		}
+	}
+
+	// Bind each named parameter to a field
+	var result error
+	for i := 1; i <= s.NumParams(); i++ {
+		name := s.ParamName(i)
+		if name == "" {
+			continue
+		}
+		if field, exists := fieldForParam(fields, name); exists {
+			if err := s.BindInterface(i, fieldValue(field)); err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+	}
+
+	// Return any errors
+	return result
+}
+
 // Bind int, uint, float, bool, string, []byte, time.Time or nil to a statement,
-// return any errors
+// return any errors. Time values are stored as TEXT in RFC3339 format in
+// the UTC timezone, and a zero time value is stored as NULL. A time.Duration
+// is stored as an INTEGER number of nanoseconds. Arbitrary-precision *big.Int
+// and *big.Rat values are stored as TEXT to avoid loss of precision, and nil
+// pointers are stored as NULL. Any value which implements
+// driver.Valuer (including sql.NullString and the other sql.Null types) is
+// bound using the value it returns. Named types with an integer, float, bool
+// or string kind (such as enums) are bound using the underlying value.
 // TODO: Also accept custom types with Marshal and Unmarshal
 func (s *Statement) BindInterface(index int, value interface{}) error {
 	if value == nil {
 		return s.BindNull(index)
 	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		if v, err := valuer.Value(); err != nil {
+			return err
+		} else if _, ok := v.(driver.Valuer); ok {
+			return SQLITE_MISMATCH
+		} else {
+			return s.BindInterface(index, v)
+		}
+	}
 	switch v := value.(type) {
 	case int:
 		return s.BindInt64(index, int64(v))
@@ -68,6 +130,8 @@ func (s *Statement) BindInterface(index int, value interface{}) error {
 		return s.BindInt64(index, int64(v))
 	case int64:
 		return s.BindInt64(index, int64(v))
+	case time.Duration:
+		return s.BindInt64(index, int64(v))
 	case uint:
 		return s.BindInt64(index, int64(v))
 	case uint8:
@@ -96,10 +160,22 @@ func (s *Statement) BindInterface(index int, value interface{}) error {
 		if v.IsZero() {
 			return s.BindNull(index)
 		} else {
-			return s.BindText(index, v.Format(time.RFC3339))
+			return s.BindText(index, v.UTC().Format(time.RFC3339))
+		}
+	case *big.Int:
+		if v == nil {
+			return s.BindNull(index)
+		} else {
+			return s.BindText(index, v.String())
+		}
+	case *big.Rat:
+		if v == nil {
+			return s.BindNull(index)
+		} else {
+			return s.BindText(index, v.String())
 		}
 	default:
-		return SQLITE_MISMATCH
+		return s.bindKind(index, reflect.ValueOf(value))
 	}
 }
 
@@ -212,3 +288,76 @@ func (s *Statement) ClearBindings() error {
 		return nil
 	}
 }
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// bindKind binds a value of a named type using the underlying value,
+// or returns SQLITE_MISMATCH if the kind is not supported
+func (s *Statement) bindKind(index int, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return s.BindInt64(index, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return s.BindInterface(index, rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return s.BindDouble(index, rv.Float())
+	case reflect.Bool:
+		return s.BindInterface(index, rv.Bool())
+	case reflect.String:
+		return s.BindText(index, rv.String())
+	default:
+		return SQLITE_MISMATCH
+	}
+}
+
+// isStruct returns true if the value is a struct or pointer to a struct which
+// is bound field by field, rather than as a single value
+func isStruct(v interface{}) bool {
+	switch v.(type) {
+	case nil, time.Time, *time.Time, big.Int, *big.Int, big.Rat, *big.Rat, driver.Valuer:
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	return rv.Kind() == reflect.Struct
+}
+
+// fieldForParam returns the field for a named parameter, with the prefix
+// removed. The exact name is preferred, otherwise the name is matched
+// without regard to case
+func fieldForParam(fields map[string]reflect.Value, param string) (reflect.Value, bool) {
+	if strings.Contains(sqliteNamedPrefix, param[:1]) {
+		param = param[1:]
+	}
+	if field, exists := fields[param]; exists {
+		return field, true
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, param) {
+			return field, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// fieldValue returns the value of a field for binding. Pointers are
+// dereferenced, and a nil pointer returns nil
+func fieldValue(v reflect.Value) interface{} {
+	if v.Kind() != reflect.Ptr {
+		return v.Interface()
+	} else if v.IsNil() {
+		return nil
+	}
+	switch v.Interface().(type) {
+	case *big.Int, *big.Rat, driver.Valuer:
+		return v.Interface()
+	default:
+		return v.Elem().Interface()
+	}
+}
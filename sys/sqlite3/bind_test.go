@@ -1,6 +1,8 @@
 package sqlite3_test
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"math"
 	"testing"
 	"time"
@@ -40,7 +42,7 @@ func Test_Bind_001(t *testing.T) {
 		{true, int64(1)},
 		{float64(math.Pi), float64(math.Pi)},
 		{float32(math.Pi), float64(float32(math.Pi))},
-		{now, now.Format(time.RFC3339)},
+		{now, now.UTC().Format(time.RFC3339)},
 		{time.Time{}, nil},
 		{nil, nil},
 	}
@@ -59,3 +61,125 @@ func Test_Bind_001(t *testing.T) {
 		}
 	}
 }
+
+type celsius float64
+
+func (c celsius) Value() (driver.Value, error) {
+	return float64(c) + 273.15, nil
+}
+
+func Test_Bind_002(t *testing.T) {
+	db, err := sqlite3.OpenPath(":memory:", sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	st, _, err := db.Prepare("SELECT ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Finalize()
+
+	var tests = []struct {
+		in, out interface{}
+	}{
+		{celsius(100), float64(373.15)},
+		{time.Second, int64(1000000000)},
+		{sql.NullString{String: "test", Valid: true}, "test"},
+		{sql.NullString{String: "test"}, nil},
+		{sql.NullInt64{Int64: 42, Valid: true}, int64(42)},
+		{sql.NullBool{Bool: true, Valid: true}, int64(1)},
+		{sql.NullFloat64{}, nil},
+	}
+
+	for _, test := range tests {
+		st.Reset()
+		if err := st.Bind(test.in); err != nil {
+			t.Error(err)
+			continue
+		}
+		for st.Step() == sqlite3.SQLITE_ROW {
+			out := st.ColumnInterface(0)
+			if out != test.out {
+				t.Errorf("Expected %v (%T) but got %v (%T) for bind type %T", test.out, test.out, out, out, test.in)
+			}
+		}
+	}
+}
+
+func Test_Bind_003(t *testing.T) {
+	db, err := sqlite3.OpenPath(":memory:", sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	st, _, err := db.Prepare("SELECT :a, :b, :c, :Name, ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Finalize()
+
+	value := int64(100)
+	var tests = []struct {
+		in  interface{}
+		out []interface{}
+	}{
+		{struct {
+			A    int64  `sqlite:"a"`
+			B    string `sqlite:"b"`
+			C    *int64 `sqlite:"c"`
+			Name string
+		}{1, "test", &value, "name"}, []interface{}{int64(1), "test", int64(100), "name", nil}},
+		{&struct {
+			A int64          `sqlite:"a"`
+			C *int64         `sqlite:"c"`
+			D sql.NullString `sqlite:"b"`
+		}{2, nil, sql.NullString{}}, []interface{}{int64(2), nil, nil, nil, nil}},
+	}
+
+	for _, test := range tests {
+		st.Reset()
+		if err := st.Bind(test.in); err != nil {
+			t.Error(err)
+			continue
+		}
+		if st.Step() != sqlite3.SQLITE_ROW {
+			t.Error("Expected a row")
+			continue
+		}
+		for i, out := range test.out {
+			if v := st.ColumnInterface(i); v != out {
+				t.Errorf("Column %d: Expected %v (%T) but got %v (%T)", i, out, out, v, v)
+			}
+		}
+	}
+}
+
+type testEnum int
+type testName string
+
+func Test_Bind_004(t *testing.T) {
+	db, err := sqlite3.OpenPath(":memory:", sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	st, _, err := db.Prepare("SELECT ?, ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Finalize()
+
+	// Named types are bound using the underlying value
+	if err := st.Bind(testEnum(2), testName("test")); err != nil {
+		t.Fatal(err)
+	} else if st.Step() != sqlite3.SQLITE_ROW {
+		t.Fatal("Expected a row")
+	}
+	if v := st.ColumnInterface(0); v != int64(2) {
+		t.Errorf("Expected 2 but got %v (%T)", v, v)
+	}
+	if v := st.ColumnInterface(1); v != "test" {
+		t.Errorf("Expected test but got %v (%T)", v, v)
+	}
+}
@@ -3,6 +3,7 @@ package sqlite3
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 	"unsafe"
@@ -74,6 +75,7 @@ type ConnEx struct {
 	CommitHookFunc
 	RollbackHookFunc
 	UpdateHookFunc
+	PreUpdateHookFunc
 	AuthorizerHookFunc
 	ExecFunc
 	TraceFunc
@@ -119,6 +121,14 @@ type ExecFunc func(row, cols []string) bool
 // TraceFunc is invoked for tracing. That's all I can say right now.
 type TraceFunc func(TraceType, unsafe.Pointer, unsafe.Pointer) int
 
+// StatementResult is the outcome of executing one statement with ExecResults
+type StatementResult struct {
+	Index        int    // Index of the statement, starting at zero
+	SQL          string // SQL text of the statement
+	RowsAffected int    // Rows inserted, updated or deleted, including by triggers
+	Err          error  // Error executing the statement, or nil
+}
+
 // Transaction type
 type SQTransaction string
 
@@ -132,7 +142,9 @@ type callback struct {
 // GLOBALS
 
 const (
-	defaultBusyTimeout = 5 * time.Second
+	// DefaultBusyTimeout is the time to wait for a lock held by another
+	// connection before returning SQLITE_BUSY
+	DefaultBusyTimeout = 5 * time.Second
 )
 
 var (
@@ -166,7 +178,7 @@ func OpenPathEx(path string, flags OpenFlags, vfs string) (*ConnEx, error) {
 	cb.add(c)
 
 	// Set busy timeout
-	if err := c.SetBusyTimeout(defaultBusyTimeout); err != nil {
+	if err := c.SetBusyTimeout(DefaultBusyTimeout); err != nil {
 		c.Conn.Close()
 		return nil, err
 	}
@@ -198,6 +210,11 @@ func (c *ConnEx) Close() error {
 	if err := c.SetAuthorizerHook(nil); err != nil {
 		result = multierror.Append(result, err)
 	}
+	if c.PreUpdateHookFunc != nil {
+		if err := c.SetPreUpdateHook(nil); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
 	if err := c.SetTraceHook(nil, 0); err != nil {
 		result = multierror.Append(result, err)
 	}
@@ -395,6 +412,48 @@ func (c *ConnEx) ExecEx(q string, fn ExecFunc, v ...interface{}) error {
 	return nil
 }
 
+// ExecResults runs statements in turn without accepting bind arguments, and
+// returns the result of each statement executed. Execution stops at the first
+// statement which fails, and the error is returned in both the result for that
+// statement and from the method. The callback is invoked for each row of data
+// returned, return true from the callback to abort execution.
+func (c *ConnEx) ExecResults(q string, fn ExecFunc) ([]StatementResult, error) {
+	var results []StatementResult
+	for i := 0; ; i++ {
+		q = strings.TrimSpace(q)
+		if q == "" {
+			break
+		}
+
+		// Prepare the next statement, or end when only comments remain
+		st, extra, err := c.Conn.Prepare(q)
+		if err != nil {
+			results = append(results, StatementResult{Index: i, SQL: q, Err: err})
+			return results, err
+		} else if st == nil {
+			break
+		}
+
+		// Execute the statement and record the changes
+		total := c.TotalChanges()
+		err = c.step(st, fn)
+		result := StatementResult{Index: i, SQL: st.SQL(), RowsAffected: c.TotalChanges() - total, Err: err}
+		if err_ := st.Finalize(); err == nil && err_ != nil {
+			result.Err = err_
+		}
+		results = append(results, result)
+		if result.Err != nil {
+			return results, result.Err
+		}
+
+		// Move to next statement
+		q = extra
+	}
+
+	// Return success
+	return results, nil
+}
+
 func (c *ConnEx) Begin(t SQTransaction) error {
 	return c.Exec("BEGIN "+string(t)+" TRANSACTION", nil)
 }
@@ -410,6 +469,38 @@ func (c *ConnEx) Rollback() error {
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// step executes a statement until done, invoking the callback for each row
+func (c *ConnEx) step(st *Statement, fn ExecFunc) error {
+	var cols, row []string
+	for {
+		err := st.Step()
+		switch {
+		case err == SQLITE_DONE:
+			return nil
+		case err != SQLITE_ROW:
+			if err, ok := err.(SQError); ok {
+				return err.With(C.GoString(C.sqlite3_errmsg((*C.sqlite3)(c.Conn))))
+			}
+			return err
+		case fn == nil:
+			continue
+		}
+		if cols == nil {
+			cols = make([]string, st.ColumnCount())
+			row = make([]string, len(cols))
+			for i := range cols {
+				cols[i] = st.ColumnName(i)
+			}
+		}
+		for i := range row {
+			row[i] = st.ColumnText(i)
+		}
+		if fn(row, cols) {
+			return SQLITE_ABORT
+		}
+	}
+}
+
 // add adds a callback to the map
 func (c *callback) add(conn *ConnEx) {
 	c.Lock()
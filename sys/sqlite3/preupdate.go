@@ -0,0 +1,107 @@
+package sqlite3
+
+import (
+	"unsafe"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#include <sqlite3.h>
+#include <stdlib.h>
+#include <stdint.h>
+
+#ifdef SQLITE_ENABLE_PREUPDATE_HOOK
+extern void go_preupdate_hook(void* userInfo, sqlite3* db, int op, char* schema, char* tbl, sqlite3_int64 oldRowid, sqlite3_int64 newRowid);
+static inline int _sqlite3_preupdate_hook(sqlite3* db, uintptr_t userInfo) {
+	sqlite3_preupdate_hook(db, (void (*)(void* , sqlite3*, int, char const*, char const*, sqlite3_int64, sqlite3_int64))(go_preupdate_hook), (void* )(userInfo));
+	return SQLITE_OK;
+}
+static inline int _sqlite3_preupdate_old(sqlite3* db, int i, sqlite3_value** v) {
+	return sqlite3_preupdate_old(db, i, v);
+}
+static inline int _sqlite3_preupdate_new(sqlite3* db, int i, sqlite3_value** v) {
+	return sqlite3_preupdate_new(db, i, v);
+}
+static inline int _sqlite3_preupdate_count(sqlite3* db) {
+	return sqlite3_preupdate_count(db);
+}
+#else
+static inline int _sqlite3_preupdate_hook(sqlite3* db, uintptr_t userInfo) {
+	return SQLITE_MISUSE;
+}
+static inline int _sqlite3_preupdate_old(sqlite3* db, int i, sqlite3_value** v) {
+	return SQLITE_MISUSE;
+}
+static inline int _sqlite3_preupdate_new(sqlite3* db, int i, sqlite3_value** v) {
+	return SQLITE_MISUSE;
+}
+static inline int _sqlite3_preupdate_count(sqlite3* db) {
+	return 0;
+}
+#endif
+*/
+import "C"
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// PreUpdateHookFunc is invoked before a row is updated, inserted or deleted.
+// SQAction will be one of SQLITE_INSERT, SQLITE_DELETE, or SQLITE_UPDATE.
+// The other arguments are database name, table name, the rowid of the row before
+// and after the change, and functions which return the column values before and
+// after the change. The old values are only available for SQLITE_UPDATE and
+// SQLITE_DELETE and the new values are only available for SQLITE_INSERT and
+// SQLITE_UPDATE, otherwise nil is returned. Values are only valid during the call.
+type PreUpdateHookFunc func(SQAction, string, string, int64, int64, PreUpdateValueFunc, PreUpdateValueFunc)
+
+// PreUpdateValueFunc returns a column value for a column index, or nil
+// if the value is not available
+type PreUpdateValueFunc func(int) *Value
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// SetPreUpdateHook sets the callback for the preupdate hook, use nil to remove
+// the handler. Returns an error if SQLite was not compiled with the
+// SQLITE_ENABLE_PREUPDATE_HOOK option.
+func (c *ConnEx) SetPreUpdateHook(fn PreUpdateHookFunc) error {
+	// Add preupdate hook
+	if err := SQError(C._sqlite3_preupdate_hook((*C.sqlite3)(c.Conn), C.uintptr_t(c.userInfo()))); err != SQLITE_OK {
+		return err.With("SetPreUpdateHook: SQLITE_ENABLE_PREUPDATE_HOOK not enabled")
+	} else {
+		c.PreUpdateHookFunc = fn
+	}
+
+	// Return success
+	return nil
+}
+
+// PreUpdateCount returns the number of columns in the row being changed. It
+// is only valid within the preupdate hook callback.
+func (c *ConnEx) PreUpdateCount() int {
+	return int(C._sqlite3_preupdate_count((*C.sqlite3)(c.Conn)))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// CALLBACKS
+
+//export go_preupdate_hook
+func go_preupdate_hook(userInfo unsafe.Pointer, db *C.sqlite3, op C.int, schema, tbl *C.char, oldRowid, newRowid C.sqlite3_int64) {
+	if c := cb.get(uintptr(userInfo)); c != nil && c.PreUpdateHookFunc != nil {
+		c.PreUpdateHookFunc(SQAction(op), C.GoString(schema), C.GoString(tbl), int64(oldRowid), int64(newRowid), func(i int) *Value {
+			var v *C.sqlite3_value
+			if SQError(C._sqlite3_preupdate_old(db, C.int(i), &v)) != SQLITE_OK {
+				return nil
+			}
+			return (*Value)(v)
+		}, func(i int) *Value {
+			var v *C.sqlite3_value
+			if SQError(C._sqlite3_preupdate_new(db, C.int(i), &v)) != SQLITE_OK {
+				return nil
+			}
+			return (*Value)(v)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package sqlite3
 
 import (
+	"database/sql"
 	"fmt"
+	"math/big"
 	"reflect"
 	"time"
 )
@@ -21,9 +23,12 @@ type Results struct {
 // GLOBALS
 
 var (
-	typeText = reflect.TypeOf("")
-	typeBlob = reflect.TypeOf([]byte{})
-	typeTime = reflect.TypeOf(time.Time{})
+	typeText    = reflect.TypeOf("")
+	typeBlob    = reflect.TypeOf([]byte{})
+	typeTime    = reflect.TypeOf(time.Time{})
+	typeBigInt  = reflect.TypeOf((*big.Int)(nil))
+	typeBigRat  = reflect.TypeOf((*big.Rat)(nil))
+	typeScanner = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -62,7 +67,9 @@ func results(st *Statement, err error) *Results {
 
 // Return next row of values, or nil if there are no more rows.
 // If arguments t are provided, then the values will be
-// cast to the types in t if that is possible
+// cast to the types in t if that is possible. Types which implement
+// sql.Scanner through a pointer (ie, sqlite.NullString) are scanned from
+// the column value, including NULL
 func (r *Results) Next(t ...reflect.Type) []interface{} {
 	// If no more results, return nil,io.EOF
 	if r.err == SQLITE_DONE {
@@ -183,6 +190,15 @@ func (r *Results) value(index int) interface{} {
 func (r *Results) castvalue(index int, t reflect.Type) (interface{}, error) {
 	st := r.st.ColumnType(index)
 
+	// Scan values for types which implement sql.Scanner, including NULL
+	if reflect.PtrTo(t).Implements(typeScanner) {
+		v := reflect.New(t)
+		if err := v.Interface().(sql.Scanner).Scan(r.value(index)); err != nil {
+			return nil, err
+		}
+		return v.Elem().Interface(), nil
+	}
+
 	// Do NULL cases
 	if st == SQLITE_NULL {
 		return reflect.Zero(t).Interface(), nil
@@ -201,13 +217,9 @@ func (r *Results) castvalue(index int, t reflect.Type) (interface{}, error) {
 			return rv.Convert(t).Interface(), nil
 		}
 	case reflect.Bool:
-		if r.st.ColumnInt64(index) == 0 {
-			return false, nil
-		} else {
-			return true, nil
-		}
+		return reflect.ValueOf(r.st.ColumnInt64(index) != 0).Convert(t).Interface(), nil
 	case reflect.String:
-		return r.st.ColumnText(index), nil
+		return reflect.ValueOf(r.st.ColumnText(index)).Convert(t).Interface(), nil
 	case reflect.Float32, reflect.Float64:
 		rv := reflect.ValueOf(r.st.ColumnDouble(index))
 		if rv.CanConvert(t) {
@@ -222,7 +234,7 @@ func (r *Results) castvalue(index int, t reflect.Type) (interface{}, error) {
 		} else if st == SQLITE_FLOAT {
 			return nil, fmt.Errorf("Cannot convert julian day number to time (at this time)")
 		} else if st == SQLITE_INTEGER {
-			return time.Unix(r.st.ColumnInt64(index), 0), nil
+			return time.Unix(r.st.ColumnInt64(index), 0).UTC(), nil
 		}
 	case typeBlob:
 		if st == SQLITE_BLOB {
@@ -230,6 +242,26 @@ func (r *Results) castvalue(index int, t reflect.Type) (interface{}, error) {
 		} else if st == SQLITE_TEXT {
 			return []byte(r.st.ColumnText(index)), nil
 		}
+	case typeBigInt:
+		if st == SQLITE_TEXT {
+			if v, ok := new(big.Int).SetString(r.st.ColumnText(index), 10); ok {
+				return v, nil
+			}
+		} else if st == SQLITE_INTEGER {
+			return new(big.Int).SetInt64(r.st.ColumnInt64(index)), nil
+		}
+	case typeBigRat:
+		if st == SQLITE_TEXT {
+			if v, ok := new(big.Rat).SetString(r.st.ColumnText(index)); ok {
+				return v, nil
+			}
+		} else if st == SQLITE_INTEGER {
+			return new(big.Rat).SetInt64(r.st.ColumnInt64(index)), nil
+		} else if st == SQLITE_FLOAT {
+			if v := new(big.Rat).SetFloat64(r.st.ColumnDouble(index)); v != nil {
+				return v, nil
+			}
+		}
 	}
 
 	// No conversion possible
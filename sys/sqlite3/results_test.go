@@ -2,10 +2,12 @@ package sqlite3_test
 
 import (
 	"math"
+	"math/big"
 	"reflect"
 	"testing"
 	"time"
 
+	sqlite "github.com/mutablelogic/go-sqlite"
 	"github.com/mutablelogic/go-sqlite/sys/sqlite3"
 )
 
@@ -123,3 +125,116 @@ func Test_Results_002(t *testing.T) {
 		}
 	}
 }
+
+func Test_Results_003(t *testing.T) {
+	db, err := sqlite3.OpenPathEx(":memory:", sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	st, err := db.Prepare("SELECT ?, ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	n, _ := new(big.Int).SetString("-98765432109876543210987654321098765432109876543210", 10)
+	q, _ := new(big.Rat).SetString("12345678901234567890123456789/7")
+	types := []reflect.Type{reflect.TypeOf(n), reflect.TypeOf(q)}
+
+	var tests = []struct {
+		in  []interface{}
+		str []interface{}
+	}{
+		{[]interface{}{n, q}, []interface{}{n.String(), q.String()}},
+		{[]interface{}{(*big.Int)(nil), (*big.Rat)(nil)}, []interface{}{nil, nil}},
+	}
+
+	for _, test := range tests {
+		// Values are bound as text
+		r, err := st.Exec(0, test.in...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if values := r.Next(); values == nil || values[0] != test.str[0] || values[1] != test.str[1] {
+			t.Errorf("Expected %v but got %v", test.str, values)
+		}
+
+		// Values are cast back without loss of precision
+		r, err = st.Exec(0, test.in...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := r.Next(types...)
+		if values == nil {
+			t.Fatal("Expected values")
+		}
+		if v := values[0].(*big.Int); (v == nil) != (test.str[0] == nil) || (v != nil && v.Cmp(n) != 0) {
+			t.Errorf("Unexpected value %v", v)
+		}
+		if v := values[1].(*big.Rat); (v == nil) != (test.str[1] == nil) || (v != nil && v.Cmp(q) != 0) {
+			t.Errorf("Unexpected value %v", v)
+		}
+	}
+}
+
+func Test_Results_004(t *testing.T) {
+	db, err := sqlite3.OpenPathEx(":memory:", sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE test (s TEXT, i INTEGER, f REAL, t TEXT, b BLOB)", nil); err != nil {
+		t.Fatal(err)
+	}
+	insert, err := db.Prepare("INSERT INTO test VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer insert.Close()
+	sel, err := db.Prepare("SELECT s, i, f, t, b FROM test WHERE rowid=?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sel.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var tests = [][]interface{}{
+		{sqlite.NullString{}, sqlite.NullInt64{}, sqlite.NullFloat64{}, sqlite.NullTime{}, sqlite.NullBytes{}},
+		{sqlite.NullString{String: "test", Valid: true}, sqlite.NullInt64{Int64: -100, Valid: true}, sqlite.NullFloat64{Float64: 3.5, Valid: true}, sqlite.NullTime{Time: now, Valid: true}, sqlite.NullBytes{Bytes: []byte{1, 2, 3}, Valid: true}},
+		{sqlite.NullString{Valid: true}, sqlite.NullInt64{Valid: true}, sqlite.NullFloat64{Valid: true}, sqlite.NullTime{}, sqlite.NullBytes{Bytes: []byte{0}, Valid: true}},
+	}
+	types := make([]reflect.Type, len(tests[0]))
+	for i, v := range tests[0] {
+		types[i] = reflect.TypeOf(v)
+	}
+	for _, test := range tests {
+		// Bind the values
+		r, err := insert.Exec(0, test...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rowid := r.LastInsertId()
+
+		// NULL values are stored as NULL
+		r, err = sel.Exec(0, rowid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := r.Next()
+		for i, v := range test {
+			if valid := reflect.ValueOf(v).FieldByName("Valid").Bool(); valid != (values[i] != nil) {
+				t.Errorf("Expected valid=%v for %T, got %v", valid, v, values[i])
+			}
+		}
+
+		// Scan the values back into nullable types
+		r, err = sel.Exec(0, rowid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if values := r.Next(types...); !reflect.DeepEqual(values, test) {
+			t.Errorf("Expected %v, got %v", test, values)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package sqlite3
+
+import (
+	"fmt"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#include <sqlite3.h>
+#include <stdlib.h>
+
+#ifdef SQLITE_ENABLE_STMT_SCANSTATUS
+static inline int _sqlite3_scanstatus_enabled() {
+	return 1;
+}
+static inline int _sqlite3_stmt_scanstatus_int64(sqlite3_stmt* st, int idx, int op, sqlite3_int64* v) {
+	return sqlite3_stmt_scanstatus(st, idx, op, v);
+}
+static inline int _sqlite3_stmt_scanstatus_int(sqlite3_stmt* st, int idx, int op, int* v) {
+	return sqlite3_stmt_scanstatus(st, idx, op, v);
+}
+static inline int _sqlite3_stmt_scanstatus_double(sqlite3_stmt* st, int idx, int op, double* v) {
+	return sqlite3_stmt_scanstatus(st, idx, op, v);
+}
+static inline int _sqlite3_stmt_scanstatus_text(sqlite3_stmt* st, int idx, int op, const char** v) {
+	return sqlite3_stmt_scanstatus(st, idx, op, v);
+}
+static inline void _sqlite3_stmt_scanstatus_reset(sqlite3_stmt* st) {
+	sqlite3_stmt_scanstatus_reset(st);
+}
+#else
+static inline int _sqlite3_scanstatus_enabled() {
+	return 0;
+}
+static inline int _sqlite3_stmt_scanstatus_int64(sqlite3_stmt* st, int idx, int op, sqlite3_int64* v) {
+	return 1;
+}
+static inline int _sqlite3_stmt_scanstatus_int(sqlite3_stmt* st, int idx, int op, int* v) {
+	return 1;
+}
+static inline int _sqlite3_stmt_scanstatus_double(sqlite3_stmt* st, int idx, int op, double* v) {
+	return 1;
+}
+static inline int _sqlite3_stmt_scanstatus_text(sqlite3_stmt* st, int idx, int op, const char** v) {
+	return 1;
+}
+static inline void _sqlite3_stmt_scanstatus_reset(sqlite3_stmt* st) {
+}
+#endif
+*/
+import "C"
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ScanStat is the status of a loop in the query plan of a statement
+type ScanStat struct {
+	Name     string  // Name of the table or index used by the loop
+	Explain  string  // Query plan description of the loop
+	SelectId int     // Select identifier of the loop
+	Loops    int64   // Number of times the loop has run
+	Rows     int64   // Number of rows visited by the loop
+	Estimate float64 // Estimated number of rows output by each run of the loop
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s ScanStat) String() string {
+	str := "<scanstat"
+	str += fmt.Sprintf(" name=%q", s.Name)
+	str += fmt.Sprintf(" explain=%q", s.Explain)
+	str += fmt.Sprint(" select_id=", s.SelectId)
+	str += fmt.Sprint(" loops=", s.Loops)
+	str += fmt.Sprint(" rows=", s.Rows)
+	str += fmt.Sprint(" estimate=", s.Estimate)
+	return str + ">"
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ScanStatus returns the status of each loop in the query plan for the
+// statement, accumulated since the statement was prepared or the status
+// was reset. Returns an error if SQLite was not compiled with the
+// SQLITE_ENABLE_STMT_SCANSTATUS option.
+func (s *Statement) ScanStatus() ([]ScanStat, error) {
+	if C._sqlite3_scanstatus_enabled() == 0 {
+		return nil, SQLITE_MISUSE.With("ScanStatus: SQLITE_ENABLE_STMT_SCANSTATUS not enabled")
+	}
+
+	var result []ScanStat
+	for idx := C.int(0); ; idx++ {
+		var stat ScanStat
+		var loops, rows C.sqlite3_int64
+		var selectid C.int
+		var estimate C.double
+		var name, explain *C.char
+		if C._sqlite3_stmt_scanstatus_int64((*C.sqlite3_stmt)(s), idx, C.SQLITE_SCANSTAT_NLOOP, &loops) != 0 {
+			break
+		}
+		C._sqlite3_stmt_scanstatus_int64((*C.sqlite3_stmt)(s), idx, C.SQLITE_SCANSTAT_NVISIT, &rows)
+		C._sqlite3_stmt_scanstatus_double((*C.sqlite3_stmt)(s), idx, C.SQLITE_SCANSTAT_EST, &estimate)
+		C._sqlite3_stmt_scanstatus_int((*C.sqlite3_stmt)(s), idx, C.SQLITE_SCANSTAT_SELECTID, &selectid)
+		C._sqlite3_stmt_scanstatus_text((*C.sqlite3_stmt)(s), idx, C.SQLITE_SCANSTAT_NAME, &name)
+		C._sqlite3_stmt_scanstatus_text((*C.sqlite3_stmt)(s), idx, C.SQLITE_SCANSTAT_EXPLAIN, &explain)
+		stat.Loops, stat.Rows = int64(loops), int64(rows)
+		stat.Estimate, stat.SelectId = float64(estimate), int(selectid)
+		if name != nil {
+			stat.Name = C.GoString(name)
+		}
+		if explain != nil {
+			stat.Explain = C.GoString(explain)
+		}
+		result = append(result, stat)
+	}
+
+	// Return success
+	return result, nil
+}
+
+// ScanStatusReset resets the loop counters returned by ScanStatus
+func (s *Statement) ScanStatusReset() {
+	C._sqlite3_stmt_scanstatus_reset((*C.sqlite3_stmt)(s))
+}
@@ -0,0 +1,317 @@
+package sqlite3
+
+import (
+	"sync"
+	"unsafe"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#include <sqlite3.h>
+#include <stdlib.h>
+#include <stdint.h>
+
+#if defined(SQLITE_ENABLE_SESSION) && defined(SQLITE_ENABLE_PREUPDATE_HOOK)
+extern int go_conflict_handler(void* userInfo, int eConflict, sqlite3_changeset_iter* iter);
+static inline int _sqlite3session_create(sqlite3* db, const char* schema, sqlite3_session** s) {
+	return sqlite3session_create(db, schema, s);
+}
+static inline int _sqlite3session_attach(sqlite3_session* s, const char* table) {
+	return sqlite3session_attach(s, table);
+}
+static inline int _sqlite3session_changeset(sqlite3_session* s, int* n, void** p) {
+	return sqlite3session_changeset(s, n, p);
+}
+static inline int _sqlite3session_isempty(sqlite3_session* s) {
+	return sqlite3session_isempty(s);
+}
+static inline void _sqlite3session_delete(sqlite3_session* s) {
+	sqlite3session_delete(s);
+}
+static inline int _sqlite3changeset_apply(sqlite3* db, int n, void* p, uintptr_t userInfo) {
+	return sqlite3changeset_apply(db, n, p, NULL, (int (*)(void*, int, sqlite3_changeset_iter*))(go_conflict_handler), (void* )(userInfo));
+}
+static inline int _sqlite3changeset_invert(int n, const void* p, int* nOut, void** pOut) {
+	return sqlite3changeset_invert(n, p, nOut, pOut);
+}
+static inline const char* _sqlite3changeset_table(sqlite3_changeset_iter* iter) {
+	const char* table = NULL;
+	int ncols, op;
+	if (sqlite3changeset_op(iter, &table, &ncols, &op, NULL) != SQLITE_OK) {
+		return NULL;
+	}
+	return table;
+}
+#else
+typedef struct sqlite3_session sqlite3_session;
+typedef struct sqlite3_changeset_iter sqlite3_changeset_iter;
+#define SQLITE_CHANGESET_DATA        1
+#define SQLITE_CHANGESET_NOTFOUND    2
+#define SQLITE_CHANGESET_CONFLICT    3
+#define SQLITE_CHANGESET_CONSTRAINT  4
+#define SQLITE_CHANGESET_FOREIGN_KEY 5
+#define SQLITE_CHANGESET_OMIT        0
+#define SQLITE_CHANGESET_REPLACE     1
+#define SQLITE_CHANGESET_ABORT       2
+static inline int _sqlite3session_create(sqlite3* db, const char* schema, sqlite3_session** s) {
+	return SQLITE_MISUSE;
+}
+static inline int _sqlite3session_attach(sqlite3_session* s, const char* table) {
+	return SQLITE_MISUSE;
+}
+static inline int _sqlite3session_changeset(sqlite3_session* s, int* n, void** p) {
+	return SQLITE_MISUSE;
+}
+static inline int _sqlite3session_isempty(sqlite3_session* s) {
+	return 1;
+}
+static inline void _sqlite3session_delete(sqlite3_session* s) {
+}
+static inline int _sqlite3changeset_apply(sqlite3* db, int n, void* p, uintptr_t userInfo) {
+	return SQLITE_MISUSE;
+}
+static inline int _sqlite3changeset_invert(int n, const void* p, int* nOut, void** pOut) {
+	return SQLITE_MISUSE;
+}
+static inline const char* _sqlite3changeset_table(sqlite3_changeset_iter* iter) {
+	return NULL;
+}
+#endif
+*/
+import "C"
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	Session        C.sqlite3_session
+	ConflictType   int
+	ConflictAction int
+)
+
+// ConflictFunc is invoked when a change cannot be applied when applying a changeset.
+// The arguments are the type of conflict and the name of the table, and the return
+// value determines whether the change is omitted or replaced, or the apply is aborted.
+type ConflictFunc func(ConflictType, string) ConflictAction
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+// Ref: https://www.sqlite.org/session/c_changeset_conflict.html
+const (
+	SQLITE_CHANGESET_DATA        ConflictType = C.SQLITE_CHANGESET_DATA        // The row exists but the values do not match the expected values
+	SQLITE_CHANGESET_NOTFOUND    ConflictType = C.SQLITE_CHANGESET_NOTFOUND    // The row to update or delete does not exist
+	SQLITE_CHANGESET_CONFLICT    ConflictType = C.SQLITE_CHANGESET_CONFLICT    // The row to insert has a primary key which already exists
+	SQLITE_CHANGESET_CONSTRAINT  ConflictType = C.SQLITE_CHANGESET_CONSTRAINT  // The change violates a constraint
+	SQLITE_CHANGESET_FOREIGN_KEY ConflictType = C.SQLITE_CHANGESET_FOREIGN_KEY // The changes leave foreign key constraints unsatisfied
+)
+
+// Ref: https://www.sqlite.org/session/c_changeset_abort.html
+const (
+	SQLITE_CHANGESET_OMIT    ConflictAction = C.SQLITE_CHANGESET_OMIT    // Omit the change
+	SQLITE_CHANGESET_REPLACE ConflictAction = C.SQLITE_CHANGESET_REPLACE // Replace the existing row with the change
+	SQLITE_CHANGESET_ABORT   ConflictAction = C.SQLITE_CHANGESET_ABORT   // Abort and rollback the apply
+)
+
+var (
+	mapConflictLock sync.RWMutex
+	mapConflictId   uintptr
+	mapConflict     = make(map[uintptr]ConflictFunc)
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (t ConflictType) String() string {
+	switch t {
+	case SQLITE_CHANGESET_DATA:
+		return "SQLITE_CHANGESET_DATA"
+	case SQLITE_CHANGESET_NOTFOUND:
+		return "SQLITE_CHANGESET_NOTFOUND"
+	case SQLITE_CHANGESET_CONFLICT:
+		return "SQLITE_CHANGESET_CONFLICT"
+	case SQLITE_CHANGESET_CONSTRAINT:
+		return "SQLITE_CHANGESET_CONSTRAINT"
+	case SQLITE_CHANGESET_FOREIGN_KEY:
+		return "SQLITE_CHANGESET_FOREIGN_KEY"
+	default:
+		return "[?? Invalid ConflictType value]"
+	}
+}
+
+func (a ConflictAction) String() string {
+	switch a {
+	case SQLITE_CHANGESET_OMIT:
+		return "SQLITE_CHANGESET_OMIT"
+	case SQLITE_CHANGESET_REPLACE:
+		return "SQLITE_CHANGESET_REPLACE"
+	case SQLITE_CHANGESET_ABORT:
+		return "SQLITE_CHANGESET_ABORT"
+	default:
+		return "[?? Invalid ConflictAction value]"
+	}
+}
+
+func (s *Session) String() string {
+	str := "<session"
+	if s.IsEmpty() {
+		str += " empty"
+	}
+	return str + ">"
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// CreateSession creates a session which records changes made to tables in a
+// schema. Returns an error if SQLite was not compiled with the SQLITE_ENABLE_SESSION
+// option.
+func (c *Conn) CreateSession(schema string) (*Session, error) {
+	var s *C.sqlite3_session
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	// Set CStrings
+	var cSchema *C.char
+	cSchema = C.CString(schema)
+	defer C.free(unsafe.Pointer(cSchema))
+
+	// Create session
+	if err := SQError(C._sqlite3session_create((*C.sqlite3)(c), cSchema, &s)); err != SQLITE_OK {
+		if err == SQLITE_MISUSE {
+			return nil, err.With("CreateSession: SQLITE_ENABLE_SESSION not enabled")
+		}
+		return nil, err
+	}
+
+	// Return success
+	return (*Session)(s), nil
+}
+
+// Close releases all resources associated with the session
+func (s *Session) Close() error {
+	C._sqlite3session_delete((*C.sqlite3_session)(s))
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Attach a table to the session, so changes to the table are recorded. If
+// the table name is empty, then changes to all tables are recorded.
+func (s *Session) Attach(table string) error {
+	var cTable *C.char
+	if table != "" {
+		cTable = C.CString(table)
+		defer C.free(unsafe.Pointer(cTable))
+	}
+	if err := SQError(C._sqlite3session_attach((*C.sqlite3_session)(s), cTable)); err != SQLITE_OK {
+		return err
+	} else {
+		return nil
+	}
+}
+
+// IsEmpty returns true if no changes have been recorded by the session
+func (s *Session) IsEmpty() bool {
+	return intToBool(int(C._sqlite3session_isempty((*C.sqlite3_session)(s))))
+}
+
+// Changeset returns the changes recorded by the session
+func (s *Session) Changeset() ([]byte, error) {
+	var n C.int
+	var p unsafe.Pointer
+	if err := SQError(C._sqlite3session_changeset((*C.sqlite3_session)(s), &n, &p)); err != SQLITE_OK {
+		return nil, err
+	}
+	defer C.sqlite3_free(p)
+
+	// Return success
+	return C.GoBytes(p, n), nil
+}
+
+// ApplyChangeset applies a changeset to a database connection. The conflict
+// function is called for any changes which cannot be applied, if it is nil then
+// any conflict will abort the apply.
+func ApplyChangeset(c *Conn, data []byte, fn ConflictFunc) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	// Register the conflict function
+	id := setConflict(fn)
+	defer deleteConflict(id)
+
+	// Copy data into C memory, as the changeset may not be modified
+	p := C.CBytes(data)
+	defer C.free(p)
+
+	// Apply changeset
+	if err := SQError(C._sqlite3changeset_apply((*C.sqlite3)(c), C.int(len(data)), p, C.uintptr_t(id))); err != SQLITE_OK {
+		return err.With(C.GoString(C.sqlite3_errmsg((*C.sqlite3)(c))))
+	} else {
+		return nil
+	}
+}
+
+// InvertChangeset returns the inverse of a changeset, which when applied undoes
+// the changes: inserts become deletes, deletes become inserts and updates are
+// reversed.
+func InvertChangeset(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	// Copy data into C memory
+	p := C.CBytes(data)
+	defer C.free(p)
+
+	// Invert changeset
+	var n C.int
+	var out unsafe.Pointer
+	if err := SQError(C._sqlite3changeset_invert(C.int(len(data)), p, &n, &out)); err != SQLITE_OK {
+		if err == SQLITE_MISUSE {
+			return nil, err.With("InvertChangeset: SQLITE_ENABLE_SESSION not enabled")
+		}
+		return nil, err
+	}
+	defer C.sqlite3_free(out)
+
+	// Return success
+	return C.GoBytes(out, n), nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func setConflict(fn ConflictFunc) uintptr {
+	mapConflictLock.Lock()
+	defer mapConflictLock.Unlock()
+	mapConflictId++
+	mapConflict[mapConflictId] = fn
+	return mapConflictId
+}
+
+func deleteConflict(id uintptr) {
+	mapConflictLock.Lock()
+	defer mapConflictLock.Unlock()
+	delete(mapConflict, id)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// CALLBACKS
+
+//export go_conflict_handler
+func go_conflict_handler(userInfo unsafe.Pointer, conflict C.int, iter *C.sqlite3_changeset_iter) C.int {
+	mapConflictLock.RLock()
+	fn := mapConflict[uintptr(userInfo)]
+	mapConflictLock.RUnlock()
+	if fn == nil {
+		return C.int(SQLITE_CHANGESET_ABORT)
+	}
+	return C.int(fn(ConflictType(conflict), C.GoString(C._sqlite3changeset_table(iter))))
+}
@@ -0,0 +1,152 @@
+package sqlite3_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	// Module imports
+	sqlite3 "github.com/mutablelogic/go-sqlite/sys/sqlite3"
+)
+
+func Test_Session_001(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	// Open source and destination databases, with the same table
+	src, err := sqlite3.OpenPathEx(filepath.Join(tmpdir, "src.sqlite"), sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	dest, err := sqlite3.OpenPathEx(filepath.Join(tmpdir, "dest.sqlite"), sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dest.Close()
+	for _, db := range []*sqlite3.ConnEx{src, dest} {
+		if err := db.Exec("CREATE TABLE test (a INTEGER PRIMARY KEY, b TEXT)", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Record changes on the source
+	session, err := src.CreateSession("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	if err := session.Attach(""); err != nil {
+		t.Fatal(err)
+	}
+	if !session.IsEmpty() {
+		t.Error("Expected empty session")
+	}
+	if err := src.Exec("INSERT INTO test VALUES (1, 'one'); INSERT INTO test VALUES (2, 'two'); UPDATE test SET b='TWO' WHERE a=2", nil); err != nil {
+		t.Fatal(err)
+	}
+	changeset, err := session.Changeset()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(changeset) == 0 {
+		t.Fatal("Expected changeset")
+	} else {
+		t.Log(session, len(changeset), "bytes")
+	}
+
+	// Apply to destination
+	if err := sqlite3.ApplyChangeset(dest.Conn, changeset, func(conflict sqlite3.ConflictType, table string) sqlite3.ConflictAction {
+		t.Error("Unexpected conflict", conflict, table)
+		return sqlite3.SQLITE_CHANGESET_ABORT
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check rows match
+	var rows []string
+	if err := dest.Exec("SELECT a, b FROM test ORDER BY a", func(row, _ []string) bool {
+		rows = append(rows, row[0]+"="+row[1])
+		return false
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0] != "1=one" || rows[1] != "2=TWO" {
+		t.Error("Unexpected rows", rows)
+	}
+
+	// Applying again results in conflicts, which are omitted
+	var conflicts int
+	if err := sqlite3.ApplyChangeset(dest.Conn, changeset, func(conflict sqlite3.ConflictType, table string) sqlite3.ConflictAction {
+		if table != "test" {
+			t.Error("Unexpected table", table)
+		}
+		conflicts++
+		return sqlite3.SQLITE_CHANGESET_OMIT
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if conflicts == 0 {
+		t.Error("Expected conflicts")
+	}
+}
+
+func Test_Session_002(t *testing.T) {
+	db, err := sqlite3.OpenPathEx(":memory:", sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE test (a INTEGER PRIMARY KEY, b TEXT); INSERT INTO test VALUES (1, 'one')", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Record an insert and an update
+	session, err := db.CreateSession("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	if err := session.Attach("test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("INSERT INTO test VALUES (2, 'two'); UPDATE test SET b='ONE' WHERE a=1", nil); err != nil {
+		t.Fatal(err)
+	}
+	changeset, err := session.Changeset()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Invert the changeset and apply it to undo the changes
+	inverse, err := sqlite3.InvertChangeset(changeset)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(inverse) == 0 {
+		t.Fatal("Expected inverse changeset")
+	}
+	if err := sqlite3.ApplyChangeset(db.Conn, inverse, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the inserted row is gone and the update is reversed
+	var rows []string
+	if err := db.Exec("SELECT a, b FROM test ORDER BY a", func(row, _ []string) bool {
+		rows = append(rows, row[0]+"="+row[1])
+		return false
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0] != "1=one" {
+		t.Error("Unexpected rows", rows)
+	}
+
+	// An empty changeset inverts to an empty changeset
+	if inverse, err := sqlite3.InvertChangeset(nil); err != nil {
+		t.Error(err)
+	} else if len(inverse) != 0 {
+		t.Error("Expected empty changeset")
+	}
+}
@@ -14,6 +14,7 @@ package sqlite3
 #cgo CFLAGS: -DSQLITE_ENABLE_SESSION
 #cgo CFLAGS: -DSQLITE_ENABLE_SNAPSHOT
 #cgo CFLAGS: -DSQLITE_ENABLE_PREUPDATE_HOOK
+#cgo CFLAGS: -DSQLITE_ENABLE_STMT_SCANSTATUS
 #cgo CFLAGS: -DSQLITE_ENABLE_GEOPOLY
 #cgo CFLAGS: -DSQLITE_USE_ALLOCA
 #cgo CFLAGS: -DSQLITE_ENABLE_COLUMN_METADATA
@@ -218,3 +218,115 @@ func Test_SQLite_010(t *testing.T) {
 		t.Log("Keyword ", i, "=>", name, "=>", sqlite3.KeywordCheck(name))
 	}
 }
+
+func Test_SQLite_011(t *testing.T) {
+	db, err := sqlite3.OpenPathEx(":memory:", sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Create a large table
+	if err := db.Exec("CREATE TABLE test (a INTEGER, b TEXT)", nil); err != nil {
+		t.Fatal(err)
+	}
+	before, _, err := db.Status(sqlite3.SQLITE_DBSTATUS_CACHE_USED, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM c WHERE x < 10000) INSERT INTO test SELECT x, printf('%0100d', x) FROM c", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read the table so it is cached, and check the cache has grown
+	if err := db.Exec("SELECT COUNT(*) FROM test WHERE b LIKE '%1%'", nil); err != nil {
+		t.Fatal(err)
+	}
+	if cur, max, err := db.Status(sqlite3.SQLITE_DBSTATUS_CACHE_USED, false); err != nil {
+		t.Error(err)
+	} else if cur <= before {
+		t.Error("Expected cache used to grow, got", before, "=>", cur)
+	} else {
+		t.Log("Cache used", before, "=>", cur, "max", max)
+	}
+
+	// Memory used is never more than the highwater mark
+	if used, highwater := sqlite3.MemoryUsed(), sqlite3.MemoryHighwater(); used <= 0 || used > highwater {
+		t.Error("Unexpected memory used", used, highwater)
+	}
+}
+
+func Test_SQLite_012(t *testing.T) {
+	db, err := sqlite3.OpenPathEx(":memory:", sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Create a table with rows
+	if err := db.Exec("CREATE TABLE test (a INTEGER, b TEXT)", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM c WHERE x < 100) INSERT INTO test SELECT x, x FROM c", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Scan the table to completion
+	st, _, err := db.Conn.Prepare("SELECT * FROM test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Finalize()
+	for {
+		if err := st.Step(); err == sqlite3.SQLITE_DONE {
+			break
+		} else if err != sqlite3.SQLITE_ROW {
+			t.Fatal(err)
+		}
+	}
+
+	// Check the loop for the table scan
+	stats, err := st.ScanStatus()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(stats) != 1 {
+		t.Fatal("Expected one scan status, got", stats)
+	} else if stats[0].Name != "test" || stats[0].Loops != 1 || stats[0].Rows != 100 {
+		t.Error("Unexpected scan status", stats[0])
+	} else {
+		t.Log(stats[0])
+	}
+
+	// Reset the counters
+	st.ScanStatusReset()
+	if stats, err := st.ScanStatus(); err != nil {
+		t.Error(err)
+	} else if len(stats) != 1 || stats[0].Loops != 0 || stats[0].Rows != 0 {
+		t.Error("Unexpected scan status after reset", stats)
+	}
+}
+
+func Test_SQLite_013(t *testing.T) {
+	options := sqlite3.CompileOptions()
+	if len(options) == 0 {
+		t.Fatal("Expected compile options")
+	}
+	found := false
+	for _, option := range options {
+		if option == "ENABLE_FTS5" {
+			found = true
+		}
+		if !sqlite3.CompileOptionUsed(option) {
+			t.Error("Expected option to be used:", option)
+		}
+	}
+	if !found {
+		t.Error("Expected ENABLE_FTS5 in", options)
+	}
+	if !sqlite3.CompileOptionUsed("SQLITE_ENABLE_FTS5") {
+		t.Error("Expected SQLITE_ENABLE_FTS5 to be used")
+	}
+	if sqlite3.CompileOptionUsed("ENABLE_NOT_AN_OPTION") {
+		t.Error("Unexpected ENABLE_NOT_AN_OPTION")
+	}
+}
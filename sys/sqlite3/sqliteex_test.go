@@ -2,6 +2,7 @@ package sqlite3_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -179,3 +180,191 @@ func Test_SQLiteEx_004(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func Test_SQLiteEx_005(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	db, err := sqlite3.OpenPathEx(filepath.Join(tmpdir, "test.sqlite"), sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Error(err)
+	}
+	defer db.Close()
+
+	// Create a table with a row
+	if err := db.Exec("CREATE TABLE test (a INTEGER, b TEXT); INSERT INTO test VALUES (1, 'old')", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Add preupdate hook which records old and new values
+	var oldv, newv string
+	var count int
+	if err := db.SetPreUpdateHook(func(op sqlite3.SQAction, schema, table string, oldRowid, newRowid int64, oldValue, newValue sqlite3.PreUpdateValueFunc) {
+		t.Log(op, schema, table, oldRowid, newRowid)
+		if op != sqlite3.SQLITE_UPDATE || schema != "main" || table != "test" {
+			t.Error("Unexpected arguments to preupdate hook")
+		}
+		count = db.PreUpdateCount()
+		if v := oldValue(1); v != nil {
+			oldv = v.Text()
+		}
+		if v := newValue(1); v != nil {
+			newv = v.Text()
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Update the row
+	if err := db.Exec("UPDATE test SET b='new' WHERE a=1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Error("Unexpected column count", count)
+	}
+	if oldv != "old" {
+		t.Errorf("Unexpected old value %q", oldv)
+	}
+	if newv != "new" {
+		t.Errorf("Unexpected new value %q", newv)
+	}
+
+	// Remove preupdate hook
+	if err := db.SetPreUpdateHook(nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_SQLiteEx_006(t *testing.T) {
+	db, err := sqlite3.OpenPathEx(sqlite3.DefaultMemory, sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE test (a INTEGER)", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		q        string
+		readonly bool
+	}{
+		{"SELECT * FROM test", true},
+		{"SELECT 1; SELECT 2", true},
+		{"INSERT INTO test VALUES (1)", false},
+		{"CREATE TABLE other (a INTEGER)", false},
+		{"SELECT 1; DELETE FROM test", false},
+	}
+	for _, test := range tests {
+		st, err := db.Prepare(test.q)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if v := st.IsReadonly(); v != test.readonly {
+			t.Errorf("%q: Expected readonly %v", test.q, test.readonly)
+		}
+		st.Close()
+	}
+}
+
+func Test_SQLiteEx_007(t *testing.T) {
+	db, err := sqlite3.OpenPathEx(sqlite3.DefaultMemory, sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE test (a INTEGER PRIMARY KEY)", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second statement fails with a constraint error, so the third is not executed
+	results, err := db.ExecResults(`
+		INSERT INTO test VALUES (1), (2), (3);
+		INSERT INTO test VALUES (4), (1);
+		DELETE FROM test;
+	`, nil)
+	if err == nil {
+		t.Fatal("Expected error")
+	} else if len(results) != 2 {
+		t.Fatal("Expected two results, got", results)
+	}
+	if r := results[0]; r.Index != 0 || r.SQL != "INSERT INTO test VALUES (1), (2), (3);" || r.RowsAffected != 3 || r.Err != nil {
+		t.Error("Unexpected result", r)
+	}
+	var sqerr sqlite3.SQError
+	if r := results[1]; r.Index != 1 || r.SQL != "INSERT INTO test VALUES (4), (1);" || r.Err != err {
+		t.Error("Unexpected result", r)
+	} else if !errors.As(r.Err, &sqerr) || sqerr&0xFF != sqlite3.SQLITE_CONSTRAINT {
+		t.Error("Expected SQLITE_CONSTRAINT, got", r.Err)
+	}
+
+	// Rows are returned through the callback, and are not counted as changes
+	var rows []string
+	results, err = db.ExecResults("SELECT a FROM test ORDER BY a; -- comment", func(row, cols []string) bool {
+		rows = append(rows, cols[0]+"="+row[0])
+		return false
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(results) != 1 || results[0].RowsAffected != 0 {
+		t.Error("Unexpected results", results)
+	} else if fmt.Sprint(rows) != "[a=1 a=2 a=3]" {
+		t.Error("Unexpected rows", rows)
+	}
+}
+
+func Test_SQLiteEx_008(t *testing.T) {
+	db, err := sqlite3.OpenPathEx(sqlite3.DefaultMemory, sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE test (a INTEGER PRIMARY KEY, b TEXT)", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute a batch of DDL, DML and SELECT statements
+	st, err := db.Prepare("CREATE INDEX test_b ON test (b); INSERT INTO test (b) VALUES (?), (?); SELECT b FROM test WHERE a > ? ORDER BY a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	results, err := st.ExecAll(nil, []interface{}{"x", "y"}, []interface{}{0})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(results) != 3 {
+		t.Fatal("Expected three results, got", len(results))
+	}
+	if n := results[1].RowsAffected(); n != 2 {
+		t.Error("Expected two rows inserted, got", n)
+	} else if id := results[1].LastInsertId(); id != 2 {
+		t.Error("Expected last insert id 2, got", id)
+	}
+	var rows []interface{}
+	for row := results[2].Next(); row != nil; row = results[2].Next() {
+		rows = append(rows, row[0])
+	}
+	if fmt.Sprint(rows) != "[x y]" {
+		t.Error("Unexpected rows", rows)
+	}
+
+	// More argument sets than statements
+	if _, err := st.ExecAll(nil, nil, nil, nil); !errors.Is(err, sqlite3.SQLITE_RANGE) {
+		t.Error("Expected SQLITE_RANGE, got", err)
+	}
+
+	// The first error is returned with the results before it
+	st2, err := db.Prepare("INSERT INTO test (a, b) VALUES (?, 'z'); INSERT INTO test (a, b) VALUES (?, 'z')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st2.Close()
+	if results, err := st2.ExecAll([]interface{}{10}, []interface{}{10}); err == nil {
+		t.Error("Expected constraint error")
+	} else if len(results) != 1 {
+		t.Error("Expected one result, got", len(results))
+	}
+}
@@ -232,7 +232,8 @@ func (c *Conn) Prepare(query string) (*Statement, string, error) {
 	return (*Statement)(s), C.GoString(cExtra), nil
 }
 
-// Bind parameters
+// Bind parameters in numerical order. A single struct argument is bound
+// to named parameters using BindStruct
 func (s *Statement) Bind(v ...interface{}) error {
 
 	// Check state
@@ -245,6 +246,11 @@ func (s *Statement) Bind(v ...interface{}) error {
 		return err
 	}
 
+	// Bind a struct to named parameters
+	if len(v) == 1 && isStruct(v[0]) {
+		return s.BindStruct(v[0])
+	}
+
 	// Bind parameters
 	var result error
 	for i, v := range v {
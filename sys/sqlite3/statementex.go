@@ -107,6 +107,66 @@ func (s *StatementEx) String() string {
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
+// IsBusy returns true if any of the prepared statements are in the middle
+// of execution
+func (s *StatementEx) IsBusy() bool {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	for _, st := range s.st {
+		if st.IsBusy() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReadonly returns true if none of the prepared statements make direct
+// changes to the content of the database file
+func (s *StatementEx) IsReadonly() bool {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	for _, st := range s.st {
+		if !st.IsReadonly() {
+			return false
+		}
+	}
+	return true
+}
+
+// Bind parameters to prepared statement n, resetting the statement first.
+// Returns SQLITE_RANGE if there is no statement n
+func (s *StatementEx) Bind(n uint, v ...interface{}) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if n >= uint(len(s.st)) {
+		return SQLITE_RANGE
+	}
+	st := s.st[int(n)]
+	if err := st.Reset(); err != nil {
+		return err
+	}
+	return st.Bind(v...)
+}
+
+// Reset all prepared statements so they can be executed again, and
+// clear any bound parameters
+func (s *StatementEx) Reset() error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	var result error
+	for _, st := range s.st {
+		if err := st.Reset(); err != nil {
+			result = multierror.Append(result, err)
+		}
+		if err := st.ClearBindings(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
 // Execute prepared statement n, when called with arguments, this
 // calls Bind() first
 func (s *StatementEx) Exec(n uint, v ...interface{}) (*Results, error) {
@@ -142,6 +202,38 @@ func (s *StatementEx) Exec(n uint, v ...interface{}) (*Results, error) {
 	}
 }
 
+// ExecAll executes every prepared statement in order, binding the matching
+// set of arguments to each statement, and returns the results for each
+// statement. Statements without a matching set of arguments are executed
+// with any existing bindings. Returns SQLITE_RANGE if there are more sets of
+// arguments than statements, or the first error from executing a statement
+// along with the results of the statements executed before it.
+func (s *StatementEx) ExecAll(args ...[]interface{}) ([]*Results, error) {
+	s.Mutex.Lock()
+	n := len(s.st)
+	s.Mutex.Unlock()
+	if len(args) > n {
+		return nil, SQLITE_RANGE
+	}
+
+	// Execute each statement
+	results := make([]*Results, 0, n)
+	for i := 0; i < n; i++ {
+		var v []interface{}
+		if i < len(args) {
+			v = args[i]
+		}
+		r, err := s.Exec(uint(i), v...)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, r)
+	}
+
+	// Return success
+	return results, nil
+}
+
 // Increment adds n to the statement counter and updates the timestamp
 func (s *StatementEx) Inc(n uint64) uint64 {
 	atomic.StoreInt64(&s.ts, time.Now().UnixNano())
@@ -74,26 +74,39 @@ func (s StatusType) String() string {
 ///////////////////////////////////////////////////////////////////////////////
 // METHODS
 
-func (c *Conn) GetStatus(v StatusType) (int, int, error) {
+// Status returns the current and highest values for a connection status
+// counter. If reset is true, the highest value is reset to the current value
+func (c *Conn) Status(v StatusType, reset bool) (int, int, error) {
 	var cur, max C.int
-	if err := SQError(C.sqlite3_db_status((*C.sqlite3)(c), (C.int)(v), &cur, &max, 0)); err != SQLITE_OK {
+	if err := SQError(C.sqlite3_db_status((*C.sqlite3)(c), (C.int)(v), &cur, &max, C.int(boolToInt(reset)))); err != SQLITE_OK {
 		return 0, 0, err
 	} else {
 		return int(cur), int(max), nil
 	}
 }
 
+func (c *Conn) GetStatus(v StatusType) (int, int, error) {
+	return c.Status(v, false)
+}
+
 func (c *Conn) ResetStatus(v StatusType) error {
-	var cur, max C.int
-	if err := SQError(C.sqlite3_db_status((*C.sqlite3)(c), (C.int)(v), &cur, &max, 1)); err != SQLITE_OK {
-		return err
-	} else {
-		return nil
-	}
+	_, _, err := c.Status(v, true)
+	return err
+}
+
+// MemoryUsed returns the number of bytes of memory currently in use
+func MemoryUsed() int64 {
+	return int64(C.sqlite3_memory_used())
+}
+
+// MemoryHighwater returns the highest number of bytes of memory in use since
+// the highwater mark was last reset
+func MemoryHighwater() int64 {
+	return int64(C.sqlite3_memory_highwater(0))
 }
 
 func GetMemoryUsed() (int64, int64) {
-	return int64(C.sqlite3_memory_used()), int64(C.sqlite3_memory_highwater(0))
+	return MemoryUsed(), MemoryHighwater()
 }
 
 func ResetMemoryUsed() {
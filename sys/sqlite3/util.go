@@ -92,6 +92,33 @@ func KeywordCheck(v string) bool {
 	return intToBool(int(C.sqlite3_keyword_check(cStr, cLen)))
 }
 
+// Return the compile-time options used to build the library, without
+// the "SQLITE_" prefix
+func CompileOptions() []string {
+	var result []string
+	for i := 0; ; i++ {
+		if cStr := C.sqlite3_compileoption_get(C.int(i)); cStr == nil {
+			break
+		} else {
+			result = append(result, C.GoString(cStr))
+		}
+	}
+	return result
+}
+
+// Return true if the library was built with the compile-time option. The
+// "SQLITE_" prefix is optional
+func CompileOptionUsed(v string) bool {
+	var cStr *C.char
+
+	// Populate CString
+	cStr = C.CString(v)
+	defer C.free(unsafe.Pointer(cStr))
+
+	// Call and return boolean
+	return intToBool(int(C.sqlite3_compileoption_used(cStr)))
+}
+
 // Sleep
 func Sleep(d time.Duration) {
 	C.sqlite3_sleep(C.int(d / time.Millisecond))
@@ -0,0 +1,606 @@
+package sqlite3
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#include <sqlite3.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+
+typedef struct go_vtab {
+	sqlite3_vtab base;
+	uintptr_t id;
+} go_vtab;
+
+typedef struct go_vtab_cursor {
+	sqlite3_vtab_cursor base;
+	uintptr_t id;
+} go_vtab_cursor;
+
+extern int go_vtab_connect(int, sqlite3*, uintptr_t, int, char**, uintptr_t*, char**);
+extern int go_vtab_bestindex(uintptr_t, sqlite3_vtab*, sqlite3_index_info*);
+extern int go_vtab_disconnect(uintptr_t, sqlite3_vtab*, int);
+extern int go_vtab_open(uintptr_t, sqlite3_vtab*, uintptr_t*);
+extern int go_cursor_close(uintptr_t, sqlite3_vtab_cursor*);
+extern int go_cursor_filter(uintptr_t, sqlite3_vtab_cursor*, int, char*, int, sqlite3_value**);
+extern int go_cursor_next(uintptr_t, sqlite3_vtab_cursor*);
+extern int go_cursor_eof(uintptr_t);
+extern int go_cursor_column(uintptr_t, sqlite3_vtab_cursor*, sqlite3_context*, int);
+extern int go_cursor_rowid(uintptr_t, sqlite3_vtab_cursor*, sqlite3_int64*);
+extern void go_module_destroy(void*);
+
+static inline int _go_vtab_init(int create, sqlite3* db, void* aux, int argc, const char* const* argv, sqlite3_vtab** ppVTab, char** pzErr) {
+	go_vtab* vtab = (go_vtab* )sqlite3_malloc(sizeof(go_vtab));
+	if (vtab == NULL) {
+		return SQLITE_NOMEM;
+	}
+	memset(vtab, 0, sizeof(go_vtab));
+	int rc = go_vtab_connect(create, db, (uintptr_t)aux, argc, (char** )argv, &vtab->id, pzErr);
+	if (rc != SQLITE_OK) {
+		sqlite3_free(vtab);
+		return rc;
+	}
+	*ppVTab = &vtab->base;
+	return SQLITE_OK;
+}
+
+static inline int _go_vtab_create(sqlite3* db, void* aux, int argc, const char* const* argv, sqlite3_vtab** ppVTab, char** pzErr) {
+	return _go_vtab_init(1, db, aux, argc, argv, ppVTab, pzErr);
+}
+
+static inline int _go_vtab_connect(sqlite3* db, void* aux, int argc, const char* const* argv, sqlite3_vtab** ppVTab, char** pzErr) {
+	return _go_vtab_init(0, db, aux, argc, argv, ppVTab, pzErr);
+}
+
+static inline int _go_vtab_bestindex(sqlite3_vtab* vtab, sqlite3_index_info* info) {
+	return go_vtab_bestindex(((go_vtab* )vtab)->id, vtab, info);
+}
+
+static inline int _go_vtab_disconnect(sqlite3_vtab* vtab) {
+	go_vtab_disconnect(((go_vtab* )vtab)->id, vtab, 0);
+	sqlite3_free(vtab->zErrMsg);
+	sqlite3_free(vtab);
+	return SQLITE_OK;
+}
+
+static inline int _go_vtab_destroy(sqlite3_vtab* vtab) {
+	int rc = go_vtab_disconnect(((go_vtab* )vtab)->id, vtab, 1);
+	if (rc == SQLITE_OK) {
+		sqlite3_free(vtab->zErrMsg);
+		sqlite3_free(vtab);
+	}
+	return rc;
+}
+
+static inline int _go_vtab_open(sqlite3_vtab* vtab, sqlite3_vtab_cursor** ppCursor) {
+	go_vtab_cursor* cursor = (go_vtab_cursor* )sqlite3_malloc(sizeof(go_vtab_cursor));
+	if (cursor == NULL) {
+		return SQLITE_NOMEM;
+	}
+	memset(cursor, 0, sizeof(go_vtab_cursor));
+	int rc = go_vtab_open(((go_vtab* )vtab)->id, vtab, &cursor->id);
+	if (rc != SQLITE_OK) {
+		sqlite3_free(cursor);
+		return rc;
+	}
+	*ppCursor = &cursor->base;
+	return SQLITE_OK;
+}
+
+static inline int _go_cursor_close(sqlite3_vtab_cursor* cursor) {
+	int rc = go_cursor_close(((go_vtab_cursor* )cursor)->id, cursor);
+	sqlite3_free(cursor);
+	return rc;
+}
+
+static inline int _go_cursor_filter(sqlite3_vtab_cursor* cursor, int idxNum, const char* idxStr, int argc, sqlite3_value** argv) {
+	return go_cursor_filter(((go_vtab_cursor* )cursor)->id, cursor, idxNum, (char* )idxStr, argc, argv);
+}
+
+static inline int _go_cursor_next(sqlite3_vtab_cursor* cursor) {
+	return go_cursor_next(((go_vtab_cursor* )cursor)->id, cursor);
+}
+
+static inline int _go_cursor_eof(sqlite3_vtab_cursor* cursor) {
+	return go_cursor_eof(((go_vtab_cursor* )cursor)->id);
+}
+
+static inline int _go_cursor_column(sqlite3_vtab_cursor* cursor, sqlite3_context* ctx, int i) {
+	return go_cursor_column(((go_vtab_cursor* )cursor)->id, cursor, ctx, i);
+}
+
+static inline int _go_cursor_rowid(sqlite3_vtab_cursor* cursor, sqlite3_int64* rowid) {
+	return go_cursor_rowid(((go_vtab_cursor* )cursor)->id, cursor, rowid);
+}
+
+static sqlite3_module _go_module = {
+	0,                   // iVersion
+	_go_vtab_create,     // xCreate
+	_go_vtab_connect,    // xConnect
+	_go_vtab_bestindex,  // xBestIndex
+	_go_vtab_disconnect, // xDisconnect
+	_go_vtab_destroy,    // xDestroy
+	_go_vtab_open,       // xOpen
+	_go_cursor_close,    // xClose
+	_go_cursor_filter,   // xFilter
+	_go_cursor_next,     // xNext
+	_go_cursor_eof,      // xEof
+	_go_cursor_column,   // xColumn
+	_go_cursor_rowid,    // xRowid
+};
+
+static inline int _sqlite3_create_module(sqlite3* db, const char* name, uintptr_t id) {
+	return sqlite3_create_module_v2(db, name, &_go_module, (void* )id, go_module_destroy);
+}
+
+static inline char* _sqlite3_vtab_strdup(const char* str) {
+	return sqlite3_mprintf("%s", str);
+}
+
+static inline struct sqlite3_index_constraint* _sqlite3_index_constraint(sqlite3_index_info* info, int i) {
+	return &info->aConstraint[i];
+}
+
+static inline struct sqlite3_index_orderby* _sqlite3_index_orderby(sqlite3_index_info* info, int i) {
+	return &info->aOrderBy[i];
+}
+
+static inline struct sqlite3_index_constraint_usage* _sqlite3_index_constraint_usage(sqlite3_index_info* info, int i) {
+	return &info->aConstraintUsage[i];
+}
+*/
+import "C"
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Module is a virtual table module implemented in Go, which is registered
+// on a connection with CreateModule. Create is called for CREATE VIRTUAL TABLE
+// and Connect when an existing virtual table is opened. Both are passed the
+// module arguments (the module name, database name, table name and any
+// arguments to the module) and should call DeclareVTab on the connection to
+// declare the columns of the table.
+type Module interface {
+	Create(c *Conn, args []string) (VTab, error)
+	Connect(c *Conn, args []string) (VTab, error)
+}
+
+// VTab is an instance of a virtual table. BestIndex chooses the plan for a
+// query, Open returns a new cursor for reading rows. Disconnect is called when
+// the table is closed, and Destroy when the table is dropped.
+type VTab interface {
+	BestIndex(*IndexInfo) error
+	Open() (VTabCursor, error)
+	Disconnect() error
+	Destroy() error
+}
+
+// VTabCursor reads rows from a virtual table. Filter starts a search with
+// the index number, index string and arguments chosen by BestIndex. Next
+// advances to the next row, and Eof returns true when there are no more rows.
+// Column sets the result for a column of the current row on the context.
+type VTabCursor interface {
+	Filter(idxNum int, idxStr string, args []*Value) error
+	Next() error
+	Eof() bool
+	Column(ctx *Context, i int) error
+	RowId() (int64, error)
+	Close() error
+}
+
+// IndexConstraintOp is the operator for a constraint on a virtual table
+type IndexConstraintOp uint8
+
+// IndexConstraint is a constraint on a column of a virtual table, which
+// may be used by BestIndex when the constraint is usable
+type IndexConstraint struct {
+	Column int               // Column constrained, or -1 for the rowid
+	Op     IndexConstraintOp // Constraint operator
+	Usable bool              // True if the constraint can be used
+}
+
+// IndexOrderBy is a term of the ORDER BY clause for a virtual table
+type IndexOrderBy struct {
+	Column int  // Column number
+	Desc   bool // True for descending order
+}
+
+// IndexConstraintUsage is set by BestIndex to pass the value of a constraint
+// as an argument to Filter, where ArgvIndex is greater than zero
+type IndexConstraintUsage struct {
+	ArgvIndex int  // Argument to Filter is args[ArgvIndex-1]
+	Omit      bool // Do not check the constraint again
+}
+
+// IndexInfo is passed to BestIndex with the constraints and order for a query,
+// and the remaining fields are set by BestIndex to choose the query plan
+type IndexInfo struct {
+	// Inputs
+	Constraints []IndexConstraint
+	OrderBy     []IndexOrderBy
+
+	// Outputs
+	ConstraintUsage []IndexConstraintUsage // One for each constraint
+	IdxNum          int                    // Index number passed to Filter
+	IdxStr          string                 // Index string passed to Filter
+	OrderByConsumed bool                   // True if rows are returned in order
+	EstimatedCost   float64                // Estimated cost of the plan
+	EstimatedRows   int64                  // Estimated number of rows returned
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	SQLITE_INDEX_CONSTRAINT_EQ        IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_EQ
+	SQLITE_INDEX_CONSTRAINT_GT        IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_GT
+	SQLITE_INDEX_CONSTRAINT_LE        IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_LE
+	SQLITE_INDEX_CONSTRAINT_LT        IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_LT
+	SQLITE_INDEX_CONSTRAINT_GE        IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_GE
+	SQLITE_INDEX_CONSTRAINT_MATCH     IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_MATCH
+	SQLITE_INDEX_CONSTRAINT_LIKE      IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_LIKE
+	SQLITE_INDEX_CONSTRAINT_GLOB      IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_GLOB
+	SQLITE_INDEX_CONSTRAINT_REGEXP    IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_REGEXP
+	SQLITE_INDEX_CONSTRAINT_NE        IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_NE
+	SQLITE_INDEX_CONSTRAINT_ISNOT     IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_ISNOT
+	SQLITE_INDEX_CONSTRAINT_ISNOTNULL IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_ISNOTNULL
+	SQLITE_INDEX_CONSTRAINT_ISNULL    IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_ISNULL
+	SQLITE_INDEX_CONSTRAINT_IS        IndexConstraintOp = C.SQLITE_INDEX_CONSTRAINT_IS
+)
+
+var (
+	mapVTabLock sync.RWMutex
+	mapVTabId   uintptr
+	mapVTab     = make(map[uintptr]interface{})
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (op IndexConstraintOp) String() string {
+	switch op {
+	case SQLITE_INDEX_CONSTRAINT_EQ:
+		return "SQLITE_INDEX_CONSTRAINT_EQ"
+	case SQLITE_INDEX_CONSTRAINT_GT:
+		return "SQLITE_INDEX_CONSTRAINT_GT"
+	case SQLITE_INDEX_CONSTRAINT_LE:
+		return "SQLITE_INDEX_CONSTRAINT_LE"
+	case SQLITE_INDEX_CONSTRAINT_LT:
+		return "SQLITE_INDEX_CONSTRAINT_LT"
+	case SQLITE_INDEX_CONSTRAINT_GE:
+		return "SQLITE_INDEX_CONSTRAINT_GE"
+	case SQLITE_INDEX_CONSTRAINT_MATCH:
+		return "SQLITE_INDEX_CONSTRAINT_MATCH"
+	case SQLITE_INDEX_CONSTRAINT_LIKE:
+		return "SQLITE_INDEX_CONSTRAINT_LIKE"
+	case SQLITE_INDEX_CONSTRAINT_GLOB:
+		return "SQLITE_INDEX_CONSTRAINT_GLOB"
+	case SQLITE_INDEX_CONSTRAINT_REGEXP:
+		return "SQLITE_INDEX_CONSTRAINT_REGEXP"
+	case SQLITE_INDEX_CONSTRAINT_NE:
+		return "SQLITE_INDEX_CONSTRAINT_NE"
+	case SQLITE_INDEX_CONSTRAINT_ISNOT:
+		return "SQLITE_INDEX_CONSTRAINT_ISNOT"
+	case SQLITE_INDEX_CONSTRAINT_ISNOTNULL:
+		return "SQLITE_INDEX_CONSTRAINT_ISNOTNULL"
+	case SQLITE_INDEX_CONSTRAINT_ISNULL:
+		return "SQLITE_INDEX_CONSTRAINT_ISNULL"
+	case SQLITE_INDEX_CONSTRAINT_IS:
+		return "SQLITE_INDEX_CONSTRAINT_IS"
+	default:
+		return "[?? Invalid IndexConstraintOp value]"
+	}
+}
+
+func (info *IndexInfo) String() string {
+	str := "<indexinfo"
+	for _, c := range info.Constraints {
+		str += fmt.Sprintf(" <constraint column=%v op=%v usable=%v>", c.Column, c.Op, c.Usable)
+	}
+	for _, o := range info.OrderBy {
+		str += fmt.Sprintf(" <orderby column=%v desc=%v>", o.Column, o.Desc)
+	}
+	str += fmt.Sprint(" idxnum=", info.IdxNum)
+	if info.IdxStr != "" {
+		str += fmt.Sprintf(" idxstr=%q", info.IdxStr)
+	}
+	str += fmt.Sprint(" cost=", info.EstimatedCost)
+	return str + ">"
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// CreateModule registers a virtual table module implemented in Go with
+// the connection. Virtual tables are then created with the statement
+// CREATE VIRTUAL TABLE <name> USING <module>(<args>)
+func (c *Conn) CreateModule(name string, module Module) error {
+	if name == "" || module == nil {
+		return SQLITE_MISUSE
+	}
+
+	// Convert name to C string
+	var cName *C.char
+	cName = C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	// Register the module, the handle is released by go_module_destroy
+	// when the module is replaced or the connection is closed
+	id := setMapVTab(module)
+	if err := SQError(C._sqlite3_create_module((*C.sqlite3)(c), cName, C.uintptr_t(id))); err != SQLITE_OK {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+// DeclareVTab declares the columns of a virtual table with a CREATE TABLE
+// statement, and should be called from the Create and Connect methods of
+// a module
+func (c *Conn) DeclareVTab(sql string) error {
+	// Convert sql to C string
+	var cSql *C.char
+	cSql = C.CString(sql)
+	defer C.free(unsafe.Pointer(cSql))
+
+	if err := SQError(C.sqlite3_declare_vtab((*C.sqlite3)(c), cSql)); err != SQLITE_OK {
+		return err.With(C.GoString(C.sqlite3_errmsg((*C.sqlite3)(c))))
+	}
+
+	// Return success
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func setMapVTab(v interface{}) uintptr {
+	mapVTabLock.Lock()
+	defer mapVTabLock.Unlock()
+	mapVTabId++
+	mapVTab[mapVTabId] = v
+	return mapVTabId
+}
+
+func getMapVTab(id C.uintptr_t) interface{} {
+	mapVTabLock.RLock()
+	defer mapVTabLock.RUnlock()
+	return mapVTab[uintptr(id)]
+}
+
+func deleteMapVTab(id C.uintptr_t) {
+	mapVTabLock.Lock()
+	defer mapVTabLock.Unlock()
+	delete(mapVTab, uintptr(id))
+}
+
+// vtabString returns a string allocated by sqlite, which is freed by sqlite
+func vtabString(v string) *C.char {
+	var cStr *C.char
+	cStr = C.CString(v)
+	defer C.free(unsafe.Pointer(cStr))
+	return C._sqlite3_vtab_strdup(cStr)
+}
+
+// vtabError sets the error message on a virtual table and returns
+// the error code, which is SQLITE_ERROR unless err is an SQError
+func vtabError(vtab *C.sqlite3_vtab, err error) C.int {
+	if err == nil {
+		return C.SQLITE_OK
+	}
+	if vtab.zErrMsg != nil {
+		C.sqlite3_free(unsafe.Pointer(vtab.zErrMsg))
+	}
+	vtab.zErrMsg = vtabString(err.Error())
+	var code SQError
+	if errors.As(err, &code) {
+		return C.int(code)
+	}
+	return C.SQLITE_ERROR
+}
+
+//export go_vtab_connect
+func go_vtab_connect(create C.int, db *C.sqlite3, id C.uintptr_t, argc C.int, argv **C.char, vtab *C.uintptr_t, errmsg **C.char) C.int {
+	module, ok := getMapVTab(id).(Module)
+	if !ok {
+		return C.SQLITE_MISUSE
+	}
+
+	// Set arguments
+	args := make([]string, int(argc))
+	for i, arg := range unsafe.Slice(argv, int(argc)) {
+		args[i] = C.GoString(arg)
+	}
+
+	// Create or connect
+	var t VTab
+	var err error
+	if create != 0 {
+		t, err = module.Create((*Conn)(db), args)
+	} else {
+		t, err = module.Connect((*Conn)(db), args)
+	}
+	if err == nil && t == nil {
+		err = SQLITE_MISUSE.With("No virtual table returned")
+	}
+	if err != nil {
+		*errmsg = vtabString(err.Error())
+		var code SQError
+		if errors.As(err, &code) {
+			return C.int(code)
+		}
+		return C.SQLITE_ERROR
+	}
+
+	// Return success
+	*vtab = C.uintptr_t(setMapVTab(t))
+	return C.SQLITE_OK
+}
+
+//export go_vtab_bestindex
+func go_vtab_bestindex(id C.uintptr_t, vtab *C.sqlite3_vtab, info *C.sqlite3_index_info) C.int {
+	t, ok := getMapVTab(id).(VTab)
+	if !ok {
+		return C.SQLITE_MISUSE
+	}
+
+	// Set inputs
+	v := &IndexInfo{
+		Constraints:     make([]IndexConstraint, int(info.nConstraint)),
+		OrderBy:         make([]IndexOrderBy, int(info.nOrderBy)),
+		ConstraintUsage: make([]IndexConstraintUsage, int(info.nConstraint)),
+		EstimatedCost:   float64(info.estimatedCost),
+		EstimatedRows:   int64(info.estimatedRows),
+	}
+	for i := range v.Constraints {
+		c := C._sqlite3_index_constraint(info, C.int(i))
+		v.Constraints[i] = IndexConstraint{int(c.iColumn), IndexConstraintOp(c.op), c.usable != 0}
+	}
+	for i := range v.OrderBy {
+		o := C._sqlite3_index_orderby(info, C.int(i))
+		v.OrderBy[i] = IndexOrderBy{int(o.iColumn), o.desc != 0}
+	}
+
+	// Choose the plan
+	if err := t.BestIndex(v); err != nil {
+		return vtabError(vtab, err)
+	}
+
+	// Set outputs
+	for i, usage := range v.ConstraintUsage {
+		if i >= int(info.nConstraint) {
+			break
+		}
+		u := C._sqlite3_index_constraint_usage(info, C.int(i))
+		u.argvIndex = C.int(usage.ArgvIndex)
+		u.omit = C.uchar(boolToInt(usage.Omit))
+	}
+	info.idxNum = C.int(v.IdxNum)
+	if v.IdxStr != "" {
+		info.idxStr = vtabString(v.IdxStr)
+		info.needToFreeIdxStr = 1
+	}
+	info.orderByConsumed = C.int(boolToInt(v.OrderByConsumed))
+	info.estimatedCost = C.double(v.EstimatedCost)
+	info.estimatedRows = C.sqlite3_int64(v.EstimatedRows)
+
+	// Return success
+	return C.SQLITE_OK
+}
+
+//export go_vtab_disconnect
+func go_vtab_disconnect(id C.uintptr_t, vtab *C.sqlite3_vtab, destroy C.int) C.int {
+	t, ok := getMapVTab(id).(VTab)
+	if !ok {
+		return C.SQLITE_MISUSE
+	}
+	if destroy != 0 {
+		if err := t.Destroy(); err != nil {
+			return vtabError(vtab, err)
+		}
+	} else {
+		t.Disconnect()
+	}
+	deleteMapVTab(id)
+	return C.SQLITE_OK
+}
+
+//export go_vtab_open
+func go_vtab_open(id C.uintptr_t, vtab *C.sqlite3_vtab, cursor *C.uintptr_t) C.int {
+	t, ok := getMapVTab(id).(VTab)
+	if !ok {
+		return C.SQLITE_MISUSE
+	}
+	c, err := t.Open()
+	if err == nil && c == nil {
+		err = SQLITE_MISUSE.With("No cursor returned")
+	}
+	if err != nil {
+		return vtabError(vtab, err)
+	}
+	*cursor = C.uintptr_t(setMapVTab(c))
+	return C.SQLITE_OK
+}
+
+//export go_cursor_close
+func go_cursor_close(id C.uintptr_t, cursor *C.sqlite3_vtab_cursor) C.int {
+	c, ok := getMapVTab(id).(VTabCursor)
+	if !ok {
+		return C.SQLITE_MISUSE
+	}
+	deleteMapVTab(id)
+	return vtabError(cursor.pVtab, c.Close())
+}
+
+//export go_cursor_filter
+func go_cursor_filter(id C.uintptr_t, cursor *C.sqlite3_vtab_cursor, idxNum C.int, idxStr *C.char, argc C.int, argv **C.sqlite3_value) C.int {
+	c, ok := getMapVTab(id).(VTabCursor)
+	if !ok {
+		return C.SQLITE_MISUSE
+	}
+	var str string
+	if idxStr != nil {
+		str = C.GoString(idxStr)
+	}
+	args := make([]*Value, int(argc))
+	for i, arg := range unsafe.Slice(argv, int(argc)) {
+		args[i] = (*Value)(arg)
+	}
+	return vtabError(cursor.pVtab, c.Filter(int(idxNum), str, args))
+}
+
+//export go_cursor_next
+func go_cursor_next(id C.uintptr_t, cursor *C.sqlite3_vtab_cursor) C.int {
+	c, ok := getMapVTab(id).(VTabCursor)
+	if !ok {
+		return C.SQLITE_MISUSE
+	}
+	return vtabError(cursor.pVtab, c.Next())
+}
+
+//export go_cursor_eof
+func go_cursor_eof(id C.uintptr_t) C.int {
+	c, ok := getMapVTab(id).(VTabCursor)
+	if !ok || c.Eof() {
+		return 1
+	}
+	return 0
+}
+
+//export go_cursor_column
+func go_cursor_column(id C.uintptr_t, cursor *C.sqlite3_vtab_cursor, ctx *C.sqlite3_context, i C.int) C.int {
+	c, ok := getMapVTab(id).(VTabCursor)
+	if !ok {
+		return C.SQLITE_MISUSE
+	}
+	return vtabError(cursor.pVtab, c.Column((*Context)(ctx), int(i)))
+}
+
+//export go_cursor_rowid
+func go_cursor_rowid(id C.uintptr_t, cursor *C.sqlite3_vtab_cursor, rowid *C.sqlite3_int64) C.int {
+	c, ok := getMapVTab(id).(VTabCursor)
+	if !ok {
+		return C.SQLITE_MISUSE
+	}
+	v, err := c.RowId()
+	if err != nil {
+		return vtabError(cursor.pVtab, err)
+	}
+	*rowid = C.sqlite3_int64(v)
+	return C.SQLITE_OK
+}
+
+//export go_module_destroy
+func go_module_destroy(id unsafe.Pointer) {
+	deleteMapVTab(C.uintptr_t(uintptr(id)))
+}
@@ -0,0 +1,140 @@
+package sqlite3_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mutablelogic/go-sqlite/sys/sqlite3"
+)
+
+// fixedModule is a read-only virtual table module with a fixed set of rows,
+// which uses an equality constraint on the value column
+type fixedModule struct {
+	rows [][]interface{}
+}
+
+type fixedTable struct {
+	*fixedModule
+}
+
+type fixedCursor struct {
+	*fixedTable
+	rows [][]interface{}
+	n    int
+}
+
+func (m *fixedModule) Create(c *sqlite3.Conn, args []string) (sqlite3.VTab, error) {
+	return m.Connect(c, args)
+}
+
+func (m *fixedModule) Connect(c *sqlite3.Conn, args []string) (sqlite3.VTab, error) {
+	if err := c.DeclareVTab("CREATE TABLE x (name TEXT, value INTEGER)"); err != nil {
+		return nil, err
+	}
+	return &fixedTable{m}, nil
+}
+
+func (t *fixedTable) BestIndex(info *sqlite3.IndexInfo) error {
+	info.EstimatedCost = float64(len(t.rows))
+	for i, c := range info.Constraints {
+		if c.Usable && c.Column == 1 && c.Op == sqlite3.SQLITE_INDEX_CONSTRAINT_EQ {
+			info.ConstraintUsage[i] = sqlite3.IndexConstraintUsage{ArgvIndex: 1, Omit: true}
+			info.IdxNum = 1
+			info.EstimatedCost = 1
+			break
+		}
+	}
+	return nil
+}
+
+func (t *fixedTable) Open() (sqlite3.VTabCursor, error) {
+	return &fixedCursor{fixedTable: t}, nil
+}
+
+func (t *fixedTable) Disconnect() error {
+	return nil
+}
+
+func (t *fixedTable) Destroy() error {
+	return nil
+}
+
+func (c *fixedCursor) Filter(idxNum int, idxStr string, args []*sqlite3.Value) error {
+	c.rows, c.n = nil, 0
+	for _, row := range c.fixedTable.rows {
+		if idxNum == 1 && row[1] != args[0].Int64() {
+			continue
+		}
+		c.rows = append(c.rows, row)
+	}
+	return nil
+}
+
+func (c *fixedCursor) Next() error {
+	c.n++
+	return nil
+}
+
+func (c *fixedCursor) Eof() bool {
+	return c.n >= len(c.rows)
+}
+
+func (c *fixedCursor) Column(ctx *sqlite3.Context, i int) error {
+	return ctx.ResultInterface(c.rows[c.n][i])
+}
+
+func (c *fixedCursor) RowId() (int64, error) {
+	return int64(c.n), nil
+}
+
+func (c *fixedCursor) Close() error {
+	return nil
+}
+
+func Test_VTab_001(t *testing.T) {
+	db, err := sqlite3.OpenPathEx(":memory:", sqlite3.SQLITE_OPEN_CREATE, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	module := &fixedModule{[][]interface{}{{"a", int64(1)}, {"b", int64(2)}, {"c", int64(3)}, {"d", int64(2)}}}
+	if err := db.CreateModule("fixed", module); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("CREATE VIRTUAL TABLE test USING fixed()", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		q        string
+		expected []string
+	}{
+		{"SELECT name, value FROM test", []string{"a 1", "b 2", "c 3", "d 2"}},
+		{"SELECT name, value FROM test WHERE value = 2", []string{"b 2", "d 2"}},
+		{"SELECT name, value FROM test WHERE value > 1 ORDER BY name DESC", []string{"d 2", "c 3", "b 2"}},
+		{"SELECT name, value FROM test WHERE value = 4", nil},
+	}
+	for _, test := range tests {
+		var rows []string
+		if err := db.Exec(test.q, func(row, _ []string) bool {
+			rows = append(rows, fmt.Sprint(row[0], " ", row[1]))
+			return false
+		}); err != nil {
+			t.Error(test.q, err)
+		} else if !reflect.DeepEqual(rows, test.expected) {
+			t.Errorf("%q: Expected %v, got %v", test.q, test.expected, rows)
+		}
+	}
+
+	// The table is read-only
+	if err := db.Exec("INSERT INTO test (name, value) VALUES ('e', 5)", nil); err == nil {
+		t.Error("Expected error inserting into read-only table")
+	}
+
+	// The table can be dropped
+	if err := db.Exec("DROP TABLE test", nil); err != nil {
+		t.Error(err)
+	}
+}